@@ -0,0 +1,114 @@
+// Package build constructs transactions with chained setters, checking
+// that the fields each transaction type requires are present and
+// consistent before it is signed.
+//
+//	tx, err := build.Payment().
+//		From(account).
+//		To(destination).
+//		Amount(amount).
+//		WithMemo("text/plain", "invoice 42", "").
+//		Build()
+//
+// Fee, Sequence and LastLedgerSequence may be left unset for
+// websockets.Autofill to populate.
+package build
+
+import (
+	"fmt"
+
+	"github.com/atticlab/ripple/data"
+)
+
+// The fields common to all transaction types, and their setters, which
+// return the builder B embedding them so that the calls chain
+type common[B any] struct {
+	builder            B
+	account            data.Account
+	fee                *data.Value
+	sequence           uint32
+	ticket             *uint32
+	lastLedgerSequence *uint32
+	sourceTag          *uint32
+	flags              data.TransactionFlag
+	memos              data.Memos
+}
+
+func (c *common[B]) From(account data.Account) B {
+	c.account = account
+	return c.builder
+}
+
+func (c *common[B]) Fee(fee data.Value) B {
+	c.fee = &fee
+	return c.builder
+}
+
+// Sequence is the account sequence of the transaction, which replaces any
+// Ticket, as rippled rejects a transaction with both
+func (c *common[B]) Sequence(n uint32) B {
+	c.sequence = n
+	c.ticket = nil
+	return c.builder
+}
+
+// Ticket uses a ticket in place of the account sequence, making Sequence 0
+func (c *common[B]) Ticket(ticketSequence uint32) B {
+	c.sequence = 0
+	c.ticket = &ticketSequence
+	return c.builder
+}
+
+func (c *common[B]) LastLedgerSequence(n uint32) B {
+	c.lastLedgerSequence = uint32Ptr(n)
+	return c.builder
+}
+
+func (c *common[B]) SourceTag(tag uint32) B {
+	c.sourceTag = uint32Ptr(tag)
+	return c.builder
+}
+
+func (c *common[B]) WithMemo(memoType, memoData, memoFormat string) B {
+	c.memos = append(c.memos, data.NewMemo(memoType, memoData, memoFormat))
+	return c.builder
+}
+
+func (c *common[B]) check(txType data.TransactionType) error {
+	if c.account.IsZero() {
+		return fmt.Errorf("%s requires an Account", txType)
+	}
+	return nil
+}
+
+// apply copies the common fields to tx. Flags are always set, even when
+// zero, so that they are explicit in the signed transaction.
+func (c *common[B]) apply(tx data.Transaction) data.Transaction {
+	base := tx.GetBase()
+	base.Account = c.account
+	if c.fee != nil {
+		base.Fee = *c.fee.Clone()
+	}
+	base.Sequence = c.sequence
+	base.TicketSequence = c.ticket
+	base.LastLedgerSequence = c.lastLedgerSequence
+	base.SourceTag = c.sourceTag
+	flags := c.flags
+	base.Flags = &flags
+	base.Memos = c.memos
+	return tx
+}
+
+func uint32Ptr(n uint32) *uint32 { return &n }
+
+func checkAmount(txType data.TransactionType, name string, amount *data.Amount) error {
+	switch {
+	case amount == nil || amount.Value == nil:
+		return fmt.Errorf("%s requires %s", txType, name)
+	case amount.IsZero() || !amount.IsPositive():
+		return fmt.Errorf("%s %s must be positive: %s", txType, name, amount)
+	}
+	if err := amount.Validate(); err != nil {
+		return fmt.Errorf("%s %s: %s", txType, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/atticlab/ripple/data"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type BuildSuite struct {
+	account, destination, issuer data.Account
+}
+
+var _ = Suite(&BuildSuite{})
+
+func (s *BuildSuite) SetUpSuite(c *C) {
+	for _, a := range []struct {
+		account *data.Account
+		address string
+	}{
+		{&s.account, "rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de"},
+		{&s.destination, "rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn"},
+		{&s.issuer, "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"},
+	} {
+		account, err := data.NewAccountFromAddress(a.address)
+		c.Assert(err, IsNil)
+		*a.account = *account
+	}
+}
+
+func amount(c *C, s string) data.Amount {
+	a, err := data.NewAmount(s)
+	c.Assert(err, IsNil)
+	return *a
+}
+
+func (s *BuildSuite) TestPayment(c *C) {
+	fee, err := data.NewNativeValue(12)
+	c.Assert(err, IsNil)
+	tx, err := Payment().
+		From(s.account).
+		To(s.destination).
+		DestinationTag(7).
+		Amount(amount(c, "10/USD/"+s.issuer.String())).
+		DeliverMin(amount(c, "9/USD/"+s.issuer.String())).
+		Fee(*fee).
+		Ticket(3).
+		WithMemo("text/plain", "invoice 42", "").
+		Build()
+	c.Assert(err, IsNil)
+	payment := tx.(*data.Payment)
+	c.Check(payment.TransactionType, Equals, data.PAYMENT)
+	c.Check(payment.Account, Equals, s.account)
+	c.Check(payment.Destination, Equals, s.destination)
+	c.Check(*payment.DestinationTag, Equals, uint32(7))
+	c.Check(payment.Amount.String(), Equals, "10/USD/"+s.issuer.String())
+	c.Check(*payment.Flags, Equals, data.TxPartialPayment)
+	c.Check(payment.Fee.Drops(), Equals, uint64(12))
+	sequence, ticket := payment.SequenceOrTicket()
+	c.Check(sequence, Equals, uint32(3))
+	c.Check(ticket, Equals, true)
+	c.Assert(payment.Memos, HasLen, 1)
+	memo, _ := payment.Memos[0].DataString()
+	c.Check(memo, Equals, "invoice 42")
+
+	tx, err = Payment().From(s.account).To(s.destination).Amount(amount(c, "1")).Build()
+	c.Assert(err, IsNil)
+	c.Check(*tx.GetBase().Flags, Equals, data.TransactionFlag(0))
+}
+
+func (s *BuildSuite) TestSequenceOrTicket(c *C) {
+	// Whichever is set last is used, as rippled rejects both
+	tx, err := AccountSet().From(s.account).Ticket(3).Sequence(6).Build()
+	c.Assert(err, IsNil)
+	c.Check(tx.GetBase().Sequence, Equals, uint32(6))
+	c.Check(tx.GetBase().TicketSequence, IsNil)
+
+	tx, err = AccountSet().From(s.account).Sequence(6).Ticket(3).Build()
+	c.Assert(err, IsNil)
+	c.Check(tx.GetBase().Sequence, Equals, uint32(0))
+	c.Check(*tx.GetBase().TicketSequence, Equals, uint32(3))
+}
+
+func (s *BuildSuite) TestInvalid(c *C) {
+	usd := amount(c, "10/USD/"+s.issuer.String())
+	for _, test := range []struct {
+		builder interface {
+			Build() (data.Transaction, error)
+		}
+		err string
+	}{
+		{Payment().To(s.destination).Amount(usd), "Payment requires an Account"},
+		{Payment().From(s.account).Amount(usd), "Payment requires a Destination"},
+		{Payment().From(s.account).To(s.destination), "Payment requires Amount"},
+		{Payment().From(s.account).To(s.destination).Amount(amount(c, "-1")), "Payment Amount must be positive: .*"},
+		{Payment().From(s.account).To(s.account).Amount(usd), "Payment to self: .*"},
+		{Payment().From(s.account).To(s.destination).Amount(amount(c, "1")).PartialPayment(), "XRP to XRP partial Payment"},
+		{Payment().From(s.account).To(s.destination).Amount(usd).DeliverMin(amount(c, "1")), "Payment DeliverMin .* is not in the asset of Amount .*"},
+		{OfferCreate().From(s.account).Pays(usd), "OfferCreate missing TakerGets"},
+		{OfferCreate().From(s.account).Pays(usd).Gets(amount(c, "1")).ImmediateOrCancel().FillOrKill(), "OfferCreate cannot be both ImmediateOrCancel and FillOrKill"},
+		{OfferCancel().From(s.account), "OfferCancel requires an OfferSequence"},
+		{TrustSet().From(s.account), "TrustSet requires a LimitAmount"},
+		{TrustSet().From(s.account).Limit(amount(c, "1")), "XRP has no trust line"},
+		{TrustSet().From(s.issuer).Limit(usd), "Trust line to self: .*"},
+		{TrustSet().From(s.account).Limit(usd).Freeze().ClearFreeze(), "TrustSet cannot both set and clear Freeze"},
+		{AccountSet(), "AccountSet requires an Account"},
+		{AccountSet().From(s.account).Set(data.TxDefaultRipple).Clear(data.TxDefaultRipple), "AccountSet cannot both set and clear flag 8"},
+		{AccountSet().From(s.account).TransferRate(999999999), "AccountSet TransferRate out of range: 999999999"},
+		{AccountSet().From(s.account).TickSize(2), "AccountSet TickSize out of range: 2"},
+	} {
+		_, err := test.builder.Build()
+		c.Check(err, ErrorMatches, test.err)
+	}
+}
+
+func (s *BuildSuite) TestOffers(c *C) {
+	tx, err := OfferCreate().
+		From(s.account).
+		Pays(amount(c, "10/USD/"+s.issuer.String())).
+		Gets(amount(c, "100")).
+		Replace(5).
+		Sell().
+		Passive().
+		Sequence(6).
+		LastLedgerSequence(1000).
+		Build()
+	c.Assert(err, IsNil)
+	offer := tx.(*data.OfferCreate)
+	c.Check(offer.TransactionType, Equals, data.OFFER_CREATE)
+	c.Check(*offer.OfferSequence, Equals, uint32(5))
+	c.Check(*offer.Flags, Equals, data.TxSell|data.TxPassive)
+	c.Check(offer.Sequence, Equals, uint32(6))
+	c.Check(*offer.LastLedgerSequence, Equals, uint32(1000))
+
+	tx, err = OfferCancel().From(s.account).Offer(5).Build()
+	c.Assert(err, IsNil)
+	c.Check(tx.(*data.OfferCancel).OfferSequence, Equals, uint32(5))
+}
+
+func (s *BuildSuite) TestTrustSet(c *C) {
+	tx, err := TrustSet().
+		From(s.account).
+		Limit(amount(c, "1000/USD/"+s.issuer.String())).
+		NoRipple().
+		Qualities(0, 0).
+		Build()
+	c.Assert(err, IsNil)
+	trust := tx.(*data.TrustSet)
+	c.Check(trust.TransactionType, Equals, data.TRUST_SET)
+	c.Check(trust.LimitAmount.String(), Equals, "1000/USD/"+s.issuer.String())
+	c.Check(*trust.Flags, Equals, data.TxSetNoRipple)
+	c.Check(*trust.QualityIn, Equals, uint32(0))
+}
+
+func (s *BuildSuite) TestAccountSet(c *C) {
+	tx, err := AccountSet().
+		From(s.account).
+		Set(data.TxDefaultRipple).
+		Domain("example.com").
+		TransferRate(1002000000).
+		Build()
+	c.Assert(err, IsNil)
+	set := tx.(*data.AccountSet)
+	c.Check(set.TransactionType, Equals, data.ACCOUNT_SET)
+	c.Check(*set.SetFlag, Equals, uint32(data.TxDefaultRipple))
+	c.Check(set.ClearFlag, IsNil)
+	c.Check(string(*set.Domain), Equals, "example.com")
+	c.Check(*set.TransferRate, Equals, uint32(1002000000))
+}
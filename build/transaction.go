@@ -0,0 +1,377 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/atticlab/ripple/data"
+)
+
+type PaymentBuilder struct {
+	common[*PaymentBuilder]
+	destination    data.Account
+	destinationTag *uint32
+	amount         *data.Amount
+	sendMax        *data.Amount
+	deliverMin     *data.Amount
+	paths          *data.PathSet
+	invoiceID      *data.Hash256
+}
+
+// Payment starts a Payment, which requires From, To and Amount
+func Payment() *PaymentBuilder {
+	b := &PaymentBuilder{}
+	b.builder = b
+	return b
+}
+
+func (b *PaymentBuilder) To(account data.Account) *PaymentBuilder {
+	b.destination = account
+	return b
+}
+
+func (b *PaymentBuilder) DestinationTag(tag uint32) *PaymentBuilder {
+	b.destinationTag = uint32Ptr(tag)
+	return b
+}
+
+func (b *PaymentBuilder) Amount(amount data.Amount) *PaymentBuilder {
+	b.amount = amount.Clone()
+	return b
+}
+
+// SendMax limits the amount spent by a cross-currency payment
+func (b *PaymentBuilder) SendMax(amount data.Amount) *PaymentBuilder {
+	b.sendMax = amount.Clone()
+	return b
+}
+
+// DeliverMin is the least a partial payment may deliver, and so also
+// makes the payment partial
+func (b *PaymentBuilder) DeliverMin(amount data.Amount) *PaymentBuilder {
+	b.deliverMin = amount.Clone()
+	b.flags |= data.TxPartialPayment
+	return b
+}
+
+func (b *PaymentBuilder) Paths(paths data.PathSet) *PaymentBuilder {
+	b.paths = &paths
+	return b
+}
+
+func (b *PaymentBuilder) InvoiceID(id data.Hash256) *PaymentBuilder {
+	b.invoiceID = &id
+	return b
+}
+
+// PartialPayment allows less than Amount to be delivered
+func (b *PaymentBuilder) PartialPayment() *PaymentBuilder {
+	b.flags |= data.TxPartialPayment
+	return b
+}
+
+func (b *PaymentBuilder) NoDirectRipple() *PaymentBuilder {
+	b.flags |= data.TxNoDirectRipple
+	return b
+}
+
+func (b *PaymentBuilder) LimitQuality() *PaymentBuilder {
+	b.flags |= data.TxLimitQuality
+	return b
+}
+
+func (b *PaymentBuilder) Build() (data.Transaction, error) {
+	if err := b.check(data.PAYMENT); err != nil {
+		return nil, err
+	}
+	if b.destination.IsZero() {
+		return nil, fmt.Errorf("Payment requires a Destination")
+	}
+	if err := checkAmount(data.PAYMENT, "Amount", b.amount); err != nil {
+		return nil, err
+	}
+	asset := *b.amount.Asset()
+	native := b.amount.IsNative()
+	if b.sendMax != nil {
+		if err := checkAmount(data.PAYMENT, "SendMax", b.sendMax); err != nil {
+			return nil, err
+		}
+		native = native && b.sendMax.IsNative()
+	}
+	if b.deliverMin != nil {
+		if err := checkAmount(data.PAYMENT, "DeliverMin", b.deliverMin); err != nil {
+			return nil, err
+		}
+		if *b.deliverMin.Asset() != asset {
+			return nil, fmt.Errorf("Payment DeliverMin %s is not in the asset of Amount %s", b.deliverMin, b.amount)
+		}
+	}
+	switch {
+	case b.account.Equals(b.destination) && (b.sendMax == nil || *b.sendMax.Asset() == asset):
+		return nil, fmt.Errorf("Payment to self: %s", b.account)
+	case native && b.paths != nil:
+		return nil, fmt.Errorf("XRP to XRP Payment with paths")
+	case native && b.flags&data.TxPartialPayment != 0:
+		return nil, fmt.Errorf("XRP to XRP partial Payment")
+	}
+	txm := data.NewTransactionWithMetadata(data.PAYMENT)
+	tx := txm.Transaction.(*data.Payment)
+	tx.Destination = b.destination
+	tx.DestinationTag = b.destinationTag
+	tx.Amount = *b.amount
+	tx.SendMax, tx.DeliverMin = b.sendMax, b.deliverMin
+	tx.Paths = b.paths
+	tx.InvoiceID = b.invoiceID
+	return b.apply(tx), nil
+}
+
+type OfferCreateBuilder struct {
+	common[*OfferCreateBuilder]
+	takerPays, takerGets data.Amount
+	offerSequence        *uint32
+	expiration           *uint32
+}
+
+// OfferCreate starts an OfferCreate, which requires From, Pays and Gets
+func OfferCreate() *OfferCreateBuilder {
+	b := &OfferCreateBuilder{}
+	b.builder = b
+	return b
+}
+
+// Pays is the amount the taker of the offer pays, which the creator receives
+func (b *OfferCreateBuilder) Pays(amount data.Amount) *OfferCreateBuilder {
+	b.takerPays = amount
+	return b
+}
+
+// Gets is the amount the taker of the offer gets, which the creator spends
+func (b *OfferCreateBuilder) Gets(amount data.Amount) *OfferCreateBuilder {
+	b.takerGets = amount
+	return b
+}
+
+// Replace cancels the offer created with sequence before placing this one
+func (b *OfferCreateBuilder) Replace(sequence uint32) *OfferCreateBuilder {
+	b.offerSequence = uint32Ptr(sequence)
+	return b
+}
+
+// Expiration is in seconds since the Ripple epoch
+func (b *OfferCreateBuilder) Expiration(t uint32) *OfferCreateBuilder {
+	b.expiration = uint32Ptr(t)
+	return b
+}
+
+func (b *OfferCreateBuilder) Passive() *OfferCreateBuilder {
+	b.flags |= data.TxPassive
+	return b
+}
+
+func (b *OfferCreateBuilder) ImmediateOrCancel() *OfferCreateBuilder {
+	b.flags |= data.TxImmediateOrCancel
+	return b
+}
+
+func (b *OfferCreateBuilder) FillOrKill() *OfferCreateBuilder {
+	b.flags |= data.TxFillOrKill
+	return b
+}
+
+func (b *OfferCreateBuilder) Sell() *OfferCreateBuilder {
+	b.flags |= data.TxSell
+	return b
+}
+
+func (b *OfferCreateBuilder) Build() (data.Transaction, error) {
+	if err := b.check(data.OFFER_CREATE); err != nil {
+		return nil, err
+	}
+	if b.flags&data.TxImmediateOrCancel != 0 && b.flags&data.TxFillOrKill != 0 {
+		return nil, fmt.Errorf("OfferCreate cannot be both ImmediateOrCancel and FillOrKill")
+	}
+	txm, err := data.NewOfferCreate(b.account, b.takerPays, b.takerGets)
+	if err != nil {
+		return nil, err
+	}
+	tx := txm.Transaction.(*data.OfferCreate)
+	tx.OfferSequence = b.offerSequence
+	tx.Expiration = b.expiration
+	return b.apply(tx), nil
+}
+
+type OfferCancelBuilder struct {
+	common[*OfferCancelBuilder]
+	offerSequence uint32
+}
+
+// OfferCancel starts an OfferCancel, which requires From and Offer
+func OfferCancel() *OfferCancelBuilder {
+	b := &OfferCancelBuilder{}
+	b.builder = b
+	return b
+}
+
+// Offer is the sequence of the transaction which created the offer
+func (b *OfferCancelBuilder) Offer(sequence uint32) *OfferCancelBuilder {
+	b.offerSequence = sequence
+	return b
+}
+
+func (b *OfferCancelBuilder) Build() (data.Transaction, error) {
+	if err := b.check(data.OFFER_CANCEL); err != nil {
+		return nil, err
+	}
+	txm, err := data.NewOfferCancel(b.account, b.offerSequence)
+	if err != nil {
+		return nil, err
+	}
+	return b.apply(txm.Transaction), nil
+}
+
+type TrustSetBuilder struct {
+	common[*TrustSetBuilder]
+	limit                 *data.Amount
+	qualityIn, qualityOut *uint32
+}
+
+// TrustSet starts a TrustSet, which requires From and Limit
+func TrustSet() *TrustSetBuilder {
+	b := &TrustSetBuilder{}
+	b.builder = b
+	return b
+}
+
+// Limit is the most of the currency of its issuer the account will hold
+func (b *TrustSetBuilder) Limit(amount data.Amount) *TrustSetBuilder {
+	b.limit = amount.Clone()
+	return b
+}
+
+// Qualities are in billionths, with 0 for the default of 1e9
+func (b *TrustSetBuilder) Qualities(in, out uint32) *TrustSetBuilder {
+	b.qualityIn, b.qualityOut = uint32Ptr(in), uint32Ptr(out)
+	return b
+}
+
+func (b *TrustSetBuilder) Authorize() *TrustSetBuilder {
+	b.flags |= data.TxSetAuth
+	return b
+}
+
+func (b *TrustSetBuilder) NoRipple() *TrustSetBuilder {
+	b.flags |= data.TxSetNoRipple
+	return b
+}
+
+func (b *TrustSetBuilder) ClearNoRipple() *TrustSetBuilder {
+	b.flags |= data.TxClearNoRipple
+	return b
+}
+
+func (b *TrustSetBuilder) Freeze() *TrustSetBuilder {
+	b.flags |= data.TxSetFreeze
+	return b
+}
+
+func (b *TrustSetBuilder) ClearFreeze() *TrustSetBuilder {
+	b.flags |= data.TxClearFreeze
+	return b
+}
+
+func (b *TrustSetBuilder) Build() (data.Transaction, error) {
+	if err := b.check(data.TRUST_SET); err != nil {
+		return nil, err
+	}
+	switch {
+	case b.limit == nil || b.limit.Value == nil:
+		return nil, fmt.Errorf("TrustSet requires a LimitAmount")
+	case b.limit.IsNative():
+		return nil, fmt.Errorf("XRP has no trust line")
+	case !b.limit.IsPositive():
+		return nil, fmt.Errorf("TrustSet LimitAmount must not be negative: %s", b.limit)
+	case b.account.Equals(b.limit.Issuer):
+		return nil, fmt.Errorf("Trust line to self: %s", b.account)
+	case b.flags&data.TxSetNoRipple != 0 && b.flags&data.TxClearNoRipple != 0:
+		return nil, fmt.Errorf("TrustSet cannot both set and clear NoRipple")
+	case b.flags&data.TxSetFreeze != 0 && b.flags&data.TxClearFreeze != 0:
+		return nil, fmt.Errorf("TrustSet cannot both set and clear Freeze")
+	}
+	if err := b.limit.Validate(); err != nil {
+		return nil, fmt.Errorf("TrustSet LimitAmount: %s", err)
+	}
+	txm := data.NewTransactionWithMetadata(data.TRUST_SET)
+	tx := txm.Transaction.(*data.TrustSet)
+	tx.LimitAmount = *b.limit
+	tx.QualityIn, tx.QualityOut = b.qualityIn, b.qualityOut
+	return b.apply(tx), nil
+}
+
+type AccountSetBuilder struct {
+	common[*AccountSetBuilder]
+	setFlag, clearFlag *uint32
+	domain             *data.VariableLength
+	transferRate       *uint32
+	tickSize           *uint8
+}
+
+// AccountSet starts an AccountSet, which requires only From
+func AccountSet() *AccountSetBuilder {
+	b := &AccountSetBuilder{}
+	b.builder = b
+	return b
+}
+
+// Set sets an account flag, such as data.TxDefaultRipple
+func (b *AccountSetBuilder) Set(flag data.TransactionFlag) *AccountSetBuilder {
+	b.setFlag = uint32Ptr(uint32(flag))
+	return b
+}
+
+// Clear clears an account flag, such as data.TxSetRequireDest
+func (b *AccountSetBuilder) Clear(flag data.TransactionFlag) *AccountSetBuilder {
+	b.clearFlag = uint32Ptr(uint32(flag))
+	return b
+}
+
+// Domain sets the domain of the account, or clears it if empty
+func (b *AccountSetBuilder) Domain(domain string) *AccountSetBuilder {
+	v := data.VariableLength(domain)
+	b.domain = &v
+	return b
+}
+
+// TransferRate is in billionths above 1e9, with 0 for no fee
+func (b *AccountSetBuilder) TransferRate(rate uint32) *AccountSetBuilder {
+	b.transferRate = uint32Ptr(rate)
+	return b
+}
+
+// TickSize is the significant digits of the exchange rates of the
+// account's offers, from 3 to 15, with 0 to clear it
+func (b *AccountSetBuilder) TickSize(size uint8) *AccountSetBuilder {
+	b.tickSize = &size
+	return b
+}
+
+func (b *AccountSetBuilder) Build() (data.Transaction, error) {
+	if err := b.check(data.ACCOUNT_SET); err != nil {
+		return nil, err
+	}
+	switch {
+	case b.setFlag != nil && b.clearFlag != nil && *b.setFlag == *b.clearFlag:
+		return nil, fmt.Errorf("AccountSet cannot both set and clear flag %d", *b.setFlag)
+	case b.transferRate != nil && *b.transferRate != 0 && (*b.transferRate < 1000000000 || *b.transferRate > 2000000000):
+		return nil, fmt.Errorf("AccountSet TransferRate out of range: %d", *b.transferRate)
+	case b.tickSize != nil && *b.tickSize != 0 && (*b.tickSize < 3 || *b.tickSize > 15):
+		return nil, fmt.Errorf("AccountSet TickSize out of range: %d", *b.tickSize)
+	case b.domain != nil && len(*b.domain) > 256:
+		return nil, fmt.Errorf("AccountSet Domain too long: %d bytes", len(*b.domain))
+	}
+	txm := data.NewTransactionWithMetadata(data.ACCOUNT_SET)
+	tx := txm.Transaction.(*data.AccountSet)
+	tx.SetFlag, tx.ClearFlag = b.setFlag, b.clearFlag
+	tx.Domain = b.domain
+	tx.TransferRate = b.transferRate
+	tx.TickSize = b.tickSize
+	return b.apply(tx), nil
+}
@@ -2,6 +2,7 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -86,7 +87,7 @@ func (s ActionSlice) Submit(host string) error {
 		return err
 	}
 	var submit = func(seed data.Seed, fee data.Value, keyType data.KeyType, tx data.Transaction, txType data.TransactionType) error {
-		result, err := remote.Submit(tx)
+		result, err := remote.Submit(context.Background(), tx)
 		if err != nil {
 			return err
 		}
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"math/big"
 	"strings"
@@ -12,6 +13,56 @@ import (
 var bigRadix = big.NewInt(58)
 var bigZero = big.NewInt(0)
 
+var alphabetIndex [256]int8
+
+func init() {
+	for i := range alphabetIndex {
+		alphabetIndex[i] = -1
+	}
+	for i := 0; i < len(ALPHABET); i++ {
+		alphabetIndex[ALPHABET[i]] = int8(i)
+	}
+}
+
+// IsValidAddress reports whether s is a well formed classic account address,
+// checking the alphabet, version and checksum without allocating.
+func IsValidAddress(s string) bool {
+	const length = 25 // version + account id + checksum
+	if len(s) < 25 || len(s) > hashTypes[RIPPLE_ACCOUNT_ID].MaximumCharacters {
+		return false
+	}
+	var b [length]byte
+	for i := 0; i < len(s); i++ {
+		carry := int(alphabetIndex[s[i]])
+		if carry < 0 {
+			return false
+		}
+		for j := length - 1; j >= 0; j-- {
+			carry += int(b[j]) * 58
+			b[j] = byte(carry)
+			carry >>= 8
+		}
+		if carry != 0 {
+			return false
+		}
+	}
+	// Each leading zero byte is encoded as a leading zero digit
+	var zeros int
+	for zeros < length && b[zeros] == 0 {
+		zeros++
+	}
+	var digits int
+	for digits < len(s) && s[digits] == ALPHABET[0] {
+		digits++
+	}
+	if zeros != digits || b[0] != byte(RIPPLE_ACCOUNT_ID) {
+		return false
+	}
+	first := sha256.Sum256(b[:length-4])
+	second := sha256.Sum256(first[:])
+	return bytes.Equal(second[:4], b[length-4:])
+}
+
 // Base58Decode decodes a modified base58 string to a byte slice and checks checksum.
 func Base58Decode(b, alphabet string) ([]byte, error) {
 	if len(b) < 5 {
@@ -0,0 +1,42 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// The prefix of a payment channel claim, 'CLM'
+var claimPrefix = []byte{0x43, 0x4C, 0x4D, 0x00}
+
+// ClaimMessage returns the message signed by an off-ledger claim of drops
+// from a payment channel, as in rippled's channel_authorize
+func ClaimMessage(channel []byte, drops uint64) ([]byte, error) {
+	if len(channel) != 32 {
+		return nil, fmt.Errorf("Wrong channel length: %d", len(channel))
+	}
+	msg := make([]byte, len(claimPrefix)+len(channel)+8)
+	copy(msg, claimPrefix)
+	copy(msg[len(claimPrefix):], channel)
+	binary.BigEndian.PutUint64(msg[len(claimPrefix)+len(channel):], drops)
+	return msg, nil
+}
+
+// SignClaim signs a claim of drops from a payment channel with the key at
+// sequence, which is nil for Ed25519 keys
+func SignClaim(key Key, sequence *uint32, channel []byte, drops uint64) ([]byte, error) {
+	msg, err := ClaimMessage(channel, drops)
+	if err != nil {
+		return nil, err
+	}
+	return Sign(key.Private(sequence), Sha512Half(msg), msg)
+}
+
+// VerifyClaim reports whether signature is a claim of drops from a payment
+// channel by publicKey, as in rippled's channel_verify
+func VerifyClaim(publicKey, channel []byte, drops uint64, signature []byte) (bool, error) {
+	msg, err := ClaimMessage(channel, drops)
+	if err != nil {
+		return false, err
+	}
+	return Verify(publicKey, Sha512Half(msg), msg, signature)
+}
@@ -0,0 +1,47 @@
+package crypto
+
+import (
+	"encoding/hex"
+
+	. "gopkg.in/check.v1"
+)
+
+type ClaimSuite struct{}
+
+var _ = Suite(&ClaimSuite{})
+
+func (s *ClaimSuite) TestVerifyClaim(c *C) {
+	channel, err := hex.DecodeString("5DB01B7FFED6B67E6B0414DED11E051D2EE2B7619CE0EAA6286D67A3A4D5BDB3")
+	c.Assert(err, IsNil)
+	publicKey, err := NewRippleHashCheck("aB44YfzW24VDEJQ2UuLPV2PvqcPCSoLnL7y5M1EzhdW4LnK5xMS3", RIPPLE_ACCOUNT_PUBLIC)
+	c.Assert(err, IsNil)
+	signature, err := hex.DecodeString("304402204EF0AFB78AC23ED1C472E74F4299C0C21F1B21D07EFC0A3838A420F76D783A400220154FB11B6F54320666E4C36CA7F686C16A3A0456800BBC43746F34AF50290064")
+	c.Assert(err, IsNil)
+	ok, err := VerifyClaim(publicKey.Payload(), channel, 1000000, signature)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	ok, err = VerifyClaim(publicKey.Payload(), channel, 1000001, signature)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+
+	seed, err := NewSeed("snoPBrXtMeMyMHUVTgbuqAfg1SUTb")
+	c.Assert(err, IsNil)
+	for _, keyType := range []KeyType{ECDSA, Ed25519} {
+		keyPair, err := seed.DeriveKeyPair(keyType, 0)
+		c.Assert(err, IsNil)
+		signature, err := SignClaim(keyPair.Key, keyPair.Sequence, channel, 500)
+		c.Assert(err, IsNil)
+		ok, err := VerifyClaim(keyPair.Public(), channel, 500, signature)
+		c.Assert(err, IsNil)
+		c.Check(ok, Equals, true)
+		ok, err = VerifyClaim(keyPair.Public(), channel, 501, signature)
+		c.Assert(err, IsNil)
+		c.Check(ok, Equals, false)
+	}
+
+	msg, err := ClaimMessage(channel, 1)
+	c.Assert(err, IsNil)
+	c.Check(hex.EncodeToString(msg), Equals, "434c4d00"+hex.EncodeToString(channel)+"0000000000000001")
+	_, err = ClaimMessage(channel[:31], 1)
+	c.Check(err, ErrorMatches, "Wrong channel length: 31")
+}
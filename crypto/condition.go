@@ -0,0 +1,102 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+)
+
+// DER tags used by PREIMAGE-SHA-256 crypto-conditions
+// https://tools.ietf.org/html/draft-thomas-crypto-conditions-04
+const (
+	preimageType        byte = 0xA0 // [0] constructed
+	preimageTag         byte = 0x80 // [0] preimage or fingerprint
+	preimageCostTag     byte = 0x81 // [1] cost
+	preimageFingerprint      = sha256.Size
+)
+
+// ValidateFulfillment reports whether a PREIMAGE-SHA-256 fulfillment, as used
+// by EscrowFinish, satisfies condition. An error is returned if either is
+// malformed or of another type.
+func ValidateFulfillment(condition, fulfillment []byte) (bool, error) {
+	preimage, err := readPreimageFulfillment(fulfillment)
+	if err != nil {
+		return false, err
+	}
+	fingerprint, cost, err := readPreimageCondition(condition)
+	if err != nil {
+		return false, err
+	}
+	hash := sha256.Sum256(preimage)
+	return bytes.Equal(hash[:], fingerprint) && cost == uint64(len(preimage)), nil
+}
+
+func readPreimageFulfillment(b []byte) ([]byte, error) {
+	content, err := readDERSequence(b)
+	if err != nil {
+		return nil, fmt.Errorf("Malformed fulfillment: %s", err)
+	}
+	tag, preimage, rest, err := readDER(content)
+	if err != nil || tag != preimageTag || len(rest) > 0 {
+		return nil, fmt.Errorf("Malformed fulfillment: %X", b)
+	}
+	return preimage, nil
+}
+
+func readPreimageCondition(b []byte) ([]byte, uint64, error) {
+	content, err := readDERSequence(b)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Malformed condition: %s", err)
+	}
+	tag, fingerprint, rest, err := readDER(content)
+	if err != nil || tag != preimageTag || len(fingerprint) != preimageFingerprint {
+		return nil, 0, fmt.Errorf("Malformed condition: %X", b)
+	}
+	tag, value, rest, err := readDER(rest)
+	if err != nil || tag != preimageCostTag || len(value) == 0 || len(value) > 8 || len(rest) > 0 {
+		return nil, 0, fmt.Errorf("Malformed condition: %X", b)
+	}
+	var cost uint64
+	for _, v := range value {
+		cost = cost<<8 | uint64(v)
+	}
+	return fingerprint, cost, nil
+}
+
+// readDERSequence returns the content of a PREIMAGE-SHA-256 type
+func readDERSequence(b []byte) ([]byte, error) {
+	tag, content, rest, err := readDER(b)
+	switch {
+	case err != nil:
+		return nil, err
+	case tag != preimageType:
+		return nil, fmt.Errorf("Unsupported type: %X", tag)
+	case len(rest) > 0:
+		return nil, fmt.Errorf("Trailing bytes: %X", rest)
+	default:
+		return content, nil
+	}
+}
+
+// readDER splits a single DER tag-length-value from b
+func readDER(b []byte) (tag byte, content, rest []byte, err error) {
+	if len(b) < 2 {
+		return 0, nil, nil, fmt.Errorf("Truncated DER: %X", b)
+	}
+	tag, length, b := b[0], int(b[1]), b[2:]
+	if length&0x80 != 0 {
+		n := length & 0x7F
+		if n == 0 || n > 3 || len(b) < n {
+			return 0, nil, nil, fmt.Errorf("Bad DER length: %X", b)
+		}
+		length = 0
+		for _, v := range b[:n] {
+			length = length<<8 | int(v)
+		}
+		b = b[n:]
+	}
+	if len(b) < length {
+		return 0, nil, nil, fmt.Errorf("Truncated DER: %X", b)
+	}
+	return tag, b[:length], b[length:], nil
+}
@@ -0,0 +1,54 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+type ConditionSuite struct{}
+
+var _ = Suite(&ConditionSuite{})
+
+func (s *ConditionSuite) TestValidateFulfillment(c *C) {
+	long := strings.Repeat("61", 200)
+	for _, t := range []struct {
+		Condition   string
+		Fulfillment string
+		Valid       bool
+	}{
+		// Empty preimage
+		{"A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810100", "A0028000", true},
+		// Preimage "aaa"
+		{"A02580209834876DCFB05CB167A5C24953EBA58C4AC89B1ADF57F28F2F9D09AF107EE8F0810103", "A0058003616161", true},
+		// 200 byte preimage needs a long form length
+		{"A0268020C2A908D98F5DF987ADE41B5FCE213067EFBCC21EF2240212A41E54B5E7C28AE5810200C8", "A081CB8081C8" + long, true},
+		{"A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810100", "A0058003616161", false},
+		// Correct fingerprint, wrong cost
+		{"A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810101", "A0028000", false},
+	} {
+		condition, err := hex.DecodeString(t.Condition)
+		c.Assert(err, IsNil)
+		fulfillment, err := hex.DecodeString(t.Fulfillment)
+		c.Assert(err, IsNil)
+		valid, err := ValidateFulfillment(condition, fulfillment)
+		c.Assert(err, IsNil, Commentf(t.Fulfillment))
+		c.Check(valid, Equals, t.Valid, Commentf(t.Fulfillment))
+	}
+
+	for _, t := range []struct {
+		Condition, Fulfillment, Error string
+	}{
+		{"A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810100", "A2028000", "Malformed fulfillment: Unsupported type: A2"},
+		{"A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810100", "A00380", "Malformed fulfillment: Truncated DER: .*"},
+		{"A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855", "A0028000", "Malformed condition: .*"},
+	} {
+		condition, err := hex.DecodeString(t.Condition)
+		c.Assert(err, IsNil)
+		fulfillment, err := hex.DecodeString(t.Fulfillment)
+		c.Assert(err, IsNil)
+		_, err = ValidateFulfillment(condition, fulfillment)
+		c.Check(err, ErrorMatches, t.Error)
+	}
+}
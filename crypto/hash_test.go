@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"encoding/hex"
 	"testing"
 
 	. "github.com/atticlab/ripple/testing"
@@ -49,3 +50,65 @@ func accountCheck(v interface{}) Hash {
 func (s *HashSuite) TestHashes(c *C) {
 	accountTests.Test(c)
 }
+
+func (s *HashSuite) TestHasher(c *C) {
+	// 'TXN' prefix followed by a serialized transaction, written in chunks
+	tx, err := hex.DecodeString("12000322000000002400000001202100000001")
+	c.Assert(err, IsNil)
+	hasher := NewHasher(0x54584E00)
+	for i := 0; i < len(tx); i += 3 {
+		end := i + 3
+		if end > len(tx) {
+			end = len(tx)
+		}
+		hasher.Write(tx[i:end])
+	}
+	sum := hasher.Sum256()
+	c.Assert(sum[:], DeepEquals, Sha512Half(append([]byte{0x54, 0x58, 0x4E, 0x00}, tx...)))
+}
+
+func (s *HashSuite) TestIsValidAddress(c *C) {
+	for _, account := range testAccounts {
+		c.Check(IsValidAddress(account.Account), Equals, true, Commentf(account.Account))
+	}
+	c.Check(IsValidAddress(ACCOUNT_ZERO), Equals, true)
+	c.Check(IsValidAddress(ACCOUNT_ONE), Equals, true)
+	c.Check(IsValidAddress(NaN), Equals, true)
+
+	id := make([]byte, 21)
+	id[1] = 0xAB
+	c.Check(IsValidAddress(Base58Encode(id, ALPHABET)), Equals, true)
+	id[0] = 1
+	c.Check(IsValidAddress(Base58Encode(id, ALPHABET)), Equals, false, Commentf("Wrong version"))
+
+	for _, bad := range []string{
+		"",
+		"0",
+		"rG1QQv2nh2gr7RCZ1P8YYcBUKCCN633jCm",  // Bad checksum
+		"rG1QQv2nh2gr7RCZ1P8YYcBUKCCN633jC",   // Truncated
+		"rrG1QQv2nh2gr7RCZ1P8YYcBUKCCN633jCn", // Extra leading zero
+		"rG1QQv2nh2gr7RCZ1P8YYcBUKCCN633j0n",  // Not in alphabet
+		"snoPBrXtMeMyMHUVTgbuqAfg1SUTb",       // Family seed
+		"n9KPnVLn7ewVzHvn218DcEYsnWLzKerTDwhpofhk4Ym1RUq4TeGw", // Node public key
+	} {
+		c.Check(IsValidAddress(bad), Equals, false, Commentf(bad))
+	}
+}
+
+func BenchmarkIsValidAddress(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !IsValidAddress(ROOT) {
+			b.Fatal("invalid")
+		}
+	}
+}
+
+func BenchmarkNewRippleHashCheck(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewRippleHashCheck(ROOT, RIPPLE_ACCOUNT_ID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -107,3 +107,79 @@ func (s *KeySuite) TestEd25119(c *C) {
 	c.Check(checkSignature(c, key.Private(nil), other.Public(nil), hash, msg), Equals, false)
 	c.Check(checkSignature(c, other.Private(nil), key.Public(nil), hash, msg), Equals, false)
 }
+
+func (s *KeySuite) TestSeedKeyPair(c *C) {
+	for _, test := range []struct {
+		seed    string
+		keyType KeyType
+		index   uint32
+		account string
+	}{
+		{"snoPBrXtMeMyMHUVTgbuqAfg1SUTb", ECDSA, 0, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"},
+		{"snoPBrXtMeMyMHUVTgbuqAfg1SUTb", ECDSA, 1, "r4bYF7SLUMD7QgSLLpgJx38WJSY12ViRjP"},
+		{"snoPBrXtMeMyMHUVTgbuqAfg1SUTb", Ed25519, 0, "rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf"},
+		{"sp5fghtJtpUorTwvof1NpDXAzNwf5", ECDSA, 0, "rU6K7V3Po4snVhBBaU29sesqs2qTQJWDw1"},
+	} {
+		seed, err := NewSeed(test.seed)
+		c.Assert(err, IsNil)
+		c.Check(seed.String(), Equals, test.seed)
+		keyPair, err := seed.DeriveKeyPair(test.keyType, test.index)
+		c.Assert(err, IsNil)
+		c.Check(keyPair.KeyType, Equals, test.keyType)
+		c.Check(checkHash(keyPair.Account()), Equals, test.account, Commentf(test.seed))
+		hash := Sha512Half([]byte("Hello, nurse!"))
+		c.Check(checkSignature(c, keyPair.Private(), keyPair.Public(), hash, []byte("Hello, nurse!")), Equals, true)
+	}
+
+	seed, err := GenerateSeed()
+	c.Assert(err, IsNil)
+	other, err := GenerateSeed()
+	c.Assert(err, IsNil)
+	c.Check(*seed, Not(Equals), *other)
+	parsed, err := NewSeed(seed.String())
+	c.Assert(err, IsNil)
+	c.Check(*parsed, Equals, *seed)
+
+	_, err = seed.DeriveKeyPair(Ed25519, 1)
+	c.Check(err, ErrorMatches, "Ed25519 keys do not support account families: 1")
+	_, err = seed.DeriveKeyPair(KeyType(2), 0)
+	c.Check(err, ErrorMatches, "Unknown key type: 2")
+	_, err = NewSeed("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Check(err, NotNil)
+}
+
+func (s *KeySuite) TestParseSeed(c *C) {
+	for _, test := range []struct {
+		seed    string
+		keyType KeyType
+		account string
+	}{
+		{"snoPBrXtMeMyMHUVTgbuqAfg1SUTb", ECDSA, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"},
+		{"sEdSKaCy2JT7JaM7v95H9SxkhP9wS2r", Ed25519, "rLUEXYuLiQptky37CqLcm9USQpPiz5rkpD"},
+	} {
+		seed, keyType, err := ParseSeed(test.seed)
+		c.Assert(err, IsNil, Commentf(test.seed))
+		c.Check(keyType, Equals, test.keyType)
+		encoded, err := seed.Encode(keyType)
+		c.Assert(err, IsNil)
+		c.Check(encoded, Equals, test.seed)
+		keyPair, err := seed.DeriveKeyPair(keyType, 0)
+		c.Assert(err, IsNil)
+		c.Check(checkHash(keyPair.Account()), Equals, test.account, Commentf(test.seed))
+	}
+
+	seed, err := GenerateSeed()
+	c.Assert(err, IsNil)
+	encoded, err := seed.Encode(Ed25519)
+	c.Assert(err, IsNil)
+	c.Check(encoded[:3], Equals, "sEd")
+	parsed, keyType, err := ParseSeed(encoded)
+	c.Assert(err, IsNil)
+	c.Check(*parsed, Equals, *seed)
+	c.Check(keyType, Equals, Ed25519)
+
+	_, err = seed.Encode(KeyType(2))
+	c.Check(err, ErrorMatches, "Unknown key type: 2")
+	_, _, err = ParseSeed("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Check(err, NotNil)
+}
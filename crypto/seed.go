@@ -0,0 +1,144 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+)
+
+// KeyType is the signing algorithm of a KeyPair, which data.KeyType converts to
+type KeyType int
+
+const (
+	ECDSA   KeyType = 0 // secp256k1
+	Ed25519 KeyType = 1
+)
+
+func (keyType KeyType) String() string {
+	switch keyType {
+	case ECDSA:
+		return "ECDSA"
+	case Ed25519:
+		return "Ed25519"
+	default:
+		return "unknown key type"
+	}
+}
+
+// Seed is the secret from which the key pairs of an account are derived.
+// data.Seed derives its keys from here.
+type Seed [16]byte
+
+// GenerateSeed returns a new random Seed
+func GenerateSeed() (*Seed, error) {
+	var seed Seed
+	if _, err := rand.Read(seed[:]); err != nil {
+		return nil, err
+	}
+	return &seed, nil
+}
+
+// NewSeed parses a base58 family seed, such as snoPBrXtMeMyMHUVTgbuqAfg1SUTb
+func NewSeed(s string) (*Seed, error) {
+	hash, err := NewRippleHashCheck(s, RIPPLE_FAMILY_SEED)
+	if err != nil {
+		return nil, err
+	}
+	var seed Seed
+	copy(seed[:], hash.Payload())
+	return &seed, nil
+}
+
+// The version bytes of an Ed25519 seed, which encodes as sEd...
+var ed25519SeedPrefix = []byte{0x01, 0xE1, 0x4B}
+
+// ParseSeed parses a base58 seed of either key type, returning Ed25519 for
+// sEd... seeds and ECDSA for family seeds
+func ParseSeed(s string) (*Seed, KeyType, error) {
+	decoded, err := Base58Decode(s, ALPHABET)
+	if err != nil {
+		return nil, 0, err
+	}
+	payload := decoded[:len(decoded)-4]
+	if len(payload) == len(ed25519SeedPrefix)+len(Seed{}) && bytes.HasPrefix(payload, ed25519SeedPrefix) {
+		var seed Seed
+		copy(seed[:], payload[len(ed25519SeedPrefix):])
+		return &seed, Ed25519, nil
+	}
+	seed, err := NewSeed(s)
+	if err != nil {
+		return nil, 0, err
+	}
+	return seed, ECDSA, nil
+}
+
+// Encode returns the base58 seed which ParseSeed reads back as the key type
+func (s Seed) Encode(keyType KeyType) (string, error) {
+	switch keyType {
+	case ECDSA:
+		hash, err := NewFamilySeed(s[:])
+		if err != nil {
+			return "", err
+		}
+		return hash.String(), nil
+	case Ed25519:
+		return Base58Encode(append(append([]byte(nil), ed25519SeedPrefix...), s[:]...), ALPHABET), nil
+	default:
+		return "", fmt.Errorf("Unknown key type: %d", keyType)
+	}
+}
+
+func (s Seed) String() string {
+	hash, err := NewFamilySeed(s[:])
+	if err != nil {
+		return fmt.Sprintf("Bad Seed: %X", s[:])
+	}
+	return hash.String()
+}
+
+// KeyPair is a derived signing key. Sequence is the account family index
+// for ECDSA keys and nil for Ed25519 keys, which have no families.
+type KeyPair struct {
+	Key      Key
+	KeyType  KeyType
+	Sequence *uint32
+}
+
+// DeriveKeyPair derives the key pair of the given type at index in the
+// account family. The usual account is at index 0, the only index
+// supported for Ed25519.
+func (s Seed) DeriveKeyPair(keyType KeyType, index uint32) (*KeyPair, error) {
+	switch keyType {
+	case ECDSA:
+		key, err := NewECDSAKey(s[:])
+		if err != nil {
+			return nil, err
+		}
+		return &KeyPair{Key: key, KeyType: ECDSA, Sequence: &index}, nil
+	case Ed25519:
+		if index != 0 {
+			return nil, fmt.Errorf("Ed25519 keys do not support account families: %d", index)
+		}
+		key, err := NewEd25519Key(s[:])
+		if err != nil {
+			return nil, err
+		}
+		return &KeyPair{Key: key, KeyType: Ed25519}, nil
+	default:
+		return nil, fmt.Errorf("Unknown key type: %d", keyType)
+	}
+}
+
+// Account returns the account id of the key pair, which data.Account
+// can be copied from
+func (k *KeyPair) Account() (Hash, error) {
+	return AccountId(k.Key, k.Sequence)
+}
+
+func (k *KeyPair) Public() []byte {
+	return k.Key.Public(k.Sequence)
+}
+
+func (k *KeyPair) Private() []byte {
+	return k.Key.Private(k.Sequence)
+}
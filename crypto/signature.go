@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/agl/ed25519"
 	"github.com/btcsuite/btcd/btcec"
@@ -54,6 +55,18 @@ func verifyEd25519(pubKey, signature, msg []byte) (bool, error) {
 	}
 }
 
+// IsFullyCanonical reports whether a DER encoded ECDSA signature has an S
+// in the lower half of the curve order, as rippled requires. Each signature
+// has a high S twin which is otherwise equally valid.
+func IsFullyCanonical(signature []byte) bool {
+	sig, err := btcec.ParseDERSignature(signature, btcec.S256())
+	if err != nil {
+		return false
+	}
+	halfOrder := new(big.Int).Rsh(btcec.S256().N, 1)
+	return sig.S.Cmp(halfOrder) <= 0
+}
+
 // Returns DER encoded signature from input hash
 func signECDSA(privateKey, hash []byte) ([]byte, error) {
 	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), privateKey)
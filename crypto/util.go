@@ -3,6 +3,8 @@ package crypto
 import (
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding/binary"
+	gohash "hash"
 
 	"golang.org/x/crypto/ripemd160"
 )
@@ -39,3 +41,28 @@ func Sha256RipeMD160(b []byte) []byte {
 	ripe.Write(sha.Sum(nil))
 	return ripe.Sum(nil)
 }
+
+// Hasher computes a SHA512Half of a hash prefix followed by everything
+// written to it, without buffering the serialized object.
+type Hasher struct {
+	digest gohash.Hash
+}
+
+func NewHasher(prefix uint32) *Hasher {
+	h := &Hasher{digest: sha512.New()}
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], prefix)
+	h.digest.Write(b[:])
+	return h
+}
+
+func (h *Hasher) Write(b []byte) (int, error) {
+	return h.digest.Write(b)
+}
+
+// Returns the first 32 bytes of the SHA512 of everything written so far
+func (h *Hasher) Sum256() [32]byte {
+	var sum [32]byte
+	copy(sum[:], h.digest.Sum(nil))
+	return sum
+}
@@ -0,0 +1,68 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// X-addresses encode an account id and an optional destination tag
+// https://github.com/xrp-community/standards-drafts/issues/6
+var (
+	xAddressMainPrefix = [2]byte{0x05, 0x44} // 'X'
+	xAddressTestPrefix = [2]byte{0x04, 0x93} // 'T'
+)
+
+const xAddressLength = 2 + 20 + 1 + 8 // prefix + account id + flag + tag
+
+// EncodeXAddress returns the X-address for an account id. A nil tag is
+// encoded as "no tag", which is distinct from a tag of 0.
+func EncodeXAddress(account [20]byte, tag *uint32, test bool) (string, error) {
+	b := make([]byte, xAddressLength)
+	prefix := xAddressMainPrefix
+	if test {
+		prefix = xAddressTestPrefix
+	}
+	copy(b, prefix[:])
+	copy(b[2:], account[:])
+	if tag != nil {
+		b[22] = 1
+		binary.LittleEndian.PutUint32(b[23:], *tag)
+	}
+	// The remaining 4 bytes are reserved for 64 bit tags and must be zero
+	return Base58Encode(b, ALPHABET), nil
+}
+
+// DecodeXAddress returns the account id, the destination tag (nil when absent)
+// and whether the address is for the test network.
+func DecodeXAddress(s string) (account [20]byte, tag *uint32, test bool, err error) {
+	b, err := Base58Decode(s, ALPHABET)
+	if err != nil {
+		return account, nil, false, err
+	}
+	if len(b) != xAddressLength+4 {
+		return account, nil, false, fmt.Errorf("Bad X-address length: %s", s)
+	}
+	switch [2]byte{b[0], b[1]} {
+	case xAddressMainPrefix:
+	case xAddressTestPrefix:
+		test = true
+	default:
+		return account, nil, false, fmt.Errorf("Bad X-address prefix: %s", s)
+	}
+	copy(account[:], b[2:22])
+	if binary.LittleEndian.Uint32(b[27:31]) != 0 {
+		return account, nil, false, fmt.Errorf("Unsupported X-address tag: %s", s)
+	}
+	value := binary.LittleEndian.Uint32(b[23:27])
+	switch b[22] {
+	case 0:
+		if value != 0 {
+			return account, nil, false, fmt.Errorf("Bad X-address tag: %s", s)
+		}
+	case 1:
+		tag = &value
+	default:
+		return account, nil, false, fmt.Errorf("Bad X-address flag: %s", s)
+	}
+	return account, tag, test, nil
+}
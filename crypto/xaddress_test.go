@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type XAddressSuite struct{}
+
+var _ = Suite(&XAddressSuite{})
+
+func uint32Ptr(v uint32) *uint32 { return &v }
+
+var xAddressTests = []struct {
+	Classic string
+	Tag     *uint32
+	Main    string
+	Test    string
+}{
+	{"r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", nil, "X7AcgcsBL6XDcUb289X4mJ8djcdyKaB5hJDWMArnXr61cqZ", "T719a5UwUCnEs54UsxG9CJYYDhwmFCqkr7wxCcNcfZ6p5GZ"},
+	{"r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", uint32Ptr(0), "X7AcgcsBL6XDcUb289X4mJ8djcdyKaGYE2g3sXRpLuiwxaX", "T719a5UwUCnEs54UsxG9CJYYDhwmFCv2Ukd2MUeYASJ7dur"},
+	{"r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", uint32Ptr(1), "X7AcgcsBL6XDcUb289X4mJ8djcdyKaGZMhc9YTE92ehJ2Fu", "T719a5UwUCnEs54UsxG9CJYYDhwmFCvbJNZbi37gBGkRkbE"},
+	{"r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", uint32Ptr(14), "X7AcgcsBL6XDcUb289X4mJ8djcdyKaGo2K5VpXpmCqbV2gS", "T719a5UwUCnEs54UsxG9CJYYDhwmFCvqXVCALUGJGSbNV3x"},
+	{"r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", uint32Ptr(11747), "X7AcgcsBL6XDcUb289X4mJ8djcdyKaLFuhLRuNXPrDeJd9A", "T719a5UwUCnEs54UsxG9CJYYDhwmFCziiNHtUukubF2Mg6t"},
+	{"r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", uint32Ptr(4294967295), "X7AcgcsBL6XDcUb289X4mJ8djcdyKaM4S135zJJmc3HMChp", "T719a5UwUCnEs54UsxG9CJYYDhwmFgrQQsaBHyYJU8UEW1q"},
+	{"rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf", nil, "XVLhHMPHU98es4dbozjVtdWzVrDjtV5fdx1mHp98tDMoQXb", "TVE26TYGhfLC7tQDno7G8dGtxSkYQn49b3qD26PK7FcGSKE"},
+}
+
+func accountId(c *C, classic string) [20]byte {
+	h, err := NewRippleHashCheck(classic, RIPPLE_ACCOUNT_ID)
+	c.Assert(err, IsNil)
+	var id [20]byte
+	copy(id[:], h.Payload())
+	return id
+}
+
+func (s *XAddressSuite) TestEncodeDecode(c *C) {
+	for _, t := range xAddressTests {
+		id := accountId(c, t.Classic)
+		for _, network := range []struct {
+			Test    bool
+			Address string
+		}{{false, t.Main}, {true, t.Test}} {
+			encoded, err := EncodeXAddress(id, t.Tag, network.Test)
+			c.Assert(err, IsNil)
+			c.Check(encoded, Equals, network.Address)
+
+			account, tag, test, err := DecodeXAddress(network.Address)
+			c.Assert(err, IsNil, Commentf(network.Address))
+			c.Check(account, Equals, id)
+			c.Check(test, Equals, network.Test)
+			if t.Tag == nil {
+				c.Check(tag, IsNil, Commentf(network.Address))
+			} else {
+				c.Assert(tag, NotNil, Commentf(network.Address))
+				c.Check(*tag, Equals, *t.Tag)
+			}
+		}
+	}
+}
+
+func (s *XAddressSuite) TestDecodeErrors(c *C) {
+	for _, address := range []string{
+		"",
+		"r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", // classic address
+		"X7AcgcsBL6XDcUb289X4mJ8djcdyKaB5hJDWMArnXr61cqY", // bad checksum
+		"X7AcgcsBL6XDcUb289X4mJ8djcdyKaB5hJDWMArnXr61cq",  // truncated
+	} {
+		_, _, _, err := DecodeXAddress(address)
+		c.Check(err, NotNil, Commentf(address))
+	}
+}
@@ -0,0 +1,78 @@
+package data
+
+import (
+	"container/list"
+	"sync"
+)
+
+// The number of base58 addresses remembered by Account.Address
+const addressCacheSize = 4096
+
+type addressEntry struct {
+	account Account
+	address string
+}
+
+// addressCache is a concurrency-safe LRU cache of account addresses
+type addressCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // most recently used first
+	entries map[Account]*list.Element
+}
+
+func newAddressCache(size int) *addressCache {
+	return &addressCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[Account]*list.Element, size),
+	}
+}
+
+var addresses = newAddressCache(addressCacheSize)
+
+func (c *addressCache) get(a Account) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[a]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*addressEntry).address, true
+}
+
+func (c *addressCache) add(a Account, address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[a]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[a] = c.order.PushFront(&addressEntry{a, address})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*addressEntry).account)
+	}
+}
+
+// address returns the base58 address of a, remembering the most recently used
+func (a Account) address() (string, error) {
+	if address, ok := addresses.get(a); ok {
+		return address, nil
+	}
+	hash, err := a.Hash()
+	if err != nil {
+		return "", err
+	}
+	address := hash.String()
+	addresses.add(a, address)
+	return address, nil
+}
+
+// Address returns the base58 address of the account. The addresses of
+// recently used accounts are cached, so repeated calls are cheap.
+func (a Account) Address() string {
+	return a.String()
+}
@@ -0,0 +1,88 @@
+package data
+
+import (
+	"sync"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+type AddressSuite struct{}
+
+var _ = Suite(&AddressSuite{})
+
+func (s *AddressSuite) TestAddressCache(c *C) {
+	cache := newAddressCache(2)
+	a, b, d := Account{1}, Account{2}, Account{3}
+	cache.add(a, "a")
+	cache.add(b, "b")
+	_, ok := cache.get(a)
+	c.Assert(ok, Equals, true)
+	// b is now the least recently used
+	cache.add(d, "d")
+	_, ok = cache.get(b)
+	c.Check(ok, Equals, false)
+	for account, expected := range map[Account]string{a: "a", d: "d"} {
+		address, ok := cache.get(account)
+		c.Check(ok, Equals, true)
+		c.Check(address, Equals, expected)
+	}
+	c.Check(cache.order.Len(), Equals, 2)
+	c.Check(cache.entries, HasLen, 2)
+}
+
+func (s *AddressSuite) TestAddress(c *C) {
+	accounts := make([]Account, 2*addressCacheSize)
+	for i := range accounts {
+		accounts[i] = Account{byte(i >> 8), byte(i)}
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, account := range accounts {
+				hash, err := account.Hash()
+				c.Check(err, IsNil)
+				c.Check(account.Address(), Equals, hash.String())
+			}
+		}()
+	}
+	wg.Wait()
+	c.Check(addresses.order.Len() <= addressCacheSize, Equals, true)
+
+	account, err := NewAccountFromAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Assert(err, IsNil)
+	text, err := account.MarshalText()
+	c.Assert(err, IsNil)
+	c.Check(string(text), Equals, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Check(account.Address(), Equals, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+}
+
+func benchmarkAccounts() []Account {
+	accounts := make([]Account, 100)
+	for i := range accounts {
+		accounts[i] = Account{byte(i), 1, 2, 3}
+	}
+	return accounts
+}
+
+func BenchmarkAccountAddressCached(b *testing.B) {
+	accounts := benchmarkAccounts()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = accounts[i%len(accounts)].Address()
+	}
+}
+
+func BenchmarkAccountAddressUncached(b *testing.B) {
+	accounts := benchmarkAccounts()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hash, err := accounts[i%len(accounts)].Hash()
+		if err != nil {
+			b.Fatal(err)
+		}
+		_ = hash.String()
+	}
+}
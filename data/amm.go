@@ -0,0 +1,74 @@
+package data
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// AMM trading fees are in units of 1/100,000, the maximum is 1%
+const (
+	ammFeeDenominator         uint32 = 100000
+	ammAuctionSlotFeeFraction uint32 = 10
+	AMMMaxTradingFee          uint32 = 1000
+)
+
+// EffectiveTradingFee returns the fee paid on a swap, the holder of the
+// auction slot and its authorised accounts pay a tenth of the base fee.
+func EffectiveTradingFee(baseFee uint32, auctionSlotHolder bool) uint32 {
+	if auctionSlotHolder {
+		return baseFee / ammAuctionSlotFeeFraction
+	}
+	return baseFee
+}
+
+// Issue is the wire form of an asset, as used for the Asset and Asset2
+// fields of AMM transactions. The issuer is omitted for XRP.
+type Issue struct {
+	Currency Currency
+	Issuer   Account
+}
+
+func sameAsset(a, b Amount) bool {
+	return a.IsNative() == b.IsNative() && a.Currency.Equals(b.Currency) && a.Issuer.Equals(b.Issuer)
+}
+
+// AMMSwapOutput returns the amount paid out by an AMM pool with reserves
+// poolIn and poolOut when amountIn is deposited. The constant product
+// formula is applied after deducting the trading fee from amountIn:
+//
+//	out = poolOut * in * (1 - fee) / (poolIn + in * (1 - fee))
+//
+// As in rippled, the output is rounded down in favour of the pool.
+func AMMSwapOutput(poolIn, poolOut Amount, tradingFee uint32, amountIn Amount) (Amount, error) {
+	switch {
+	case poolIn.Value == nil || poolOut.Value == nil || amountIn.Value == nil:
+		return Amount{}, fmt.Errorf("AMM swap has a nil Value")
+	case !sameAsset(poolIn, amountIn):
+		return Amount{}, fmt.Errorf("AMM swap input %s does not match pool %s", amountIn.Asset(), poolIn.Asset())
+	case sameAsset(poolIn, poolOut):
+		return Amount{}, fmt.Errorf("AMM pool assets must differ: %s", poolIn.Asset())
+	case poolIn.IsZero() || poolIn.IsNegative() || poolOut.IsZero() || poolOut.IsNegative():
+		return Amount{}, fmt.Errorf("AMM pool reserves must be positive")
+	case amountIn.IsNegative():
+		return Amount{}, fmt.Errorf("AMM swap input must not be negative: %s", amountIn)
+	case tradingFee > AMMMaxTradingFee:
+		return Amount{}, fmt.Errorf("AMM trading fee too high: %d", tradingFee)
+	}
+	in := new(big.Rat).Mul(amountIn.Rat(), big.NewRat(int64(ammFeeDenominator-tradingFee), int64(ammFeeDenominator)))
+	out := new(big.Rat).Mul(poolOut.Rat(), in)
+	out.Quo(out, in.Add(in, poolIn.Rat()))
+	value, err := newValueFromRat(out, poolOut.IsNative())
+	if err != nil {
+		return Amount{}, err
+	}
+	return *newAmount(value, poolOut.Currency, poolOut.Issuer), nil
+}
+
+// AMMSwapOutputForHolder is AMMSwapOutput with the trading fee discounted
+// when the swapper holds the auction slot.
+func AMMSwapOutputForHolder(poolIn, poolOut Amount, tradingFee uint32, auctionSlotHolder bool, amountIn Amount) (Amount, error) {
+	if tradingFee > AMMMaxTradingFee {
+		return Amount{}, fmt.Errorf("AMM trading fee too high: %d", tradingFee)
+	}
+	return AMMSwapOutput(poolIn, poolOut, EffectiveTradingFee(tradingFee, auctionSlotHolder), amountIn)
+}
@@ -0,0 +1,61 @@
+package data
+
+import . "gopkg.in/check.v1"
+
+type AMMSuite struct{}
+
+var _ = Suite(&AMMSuite{})
+
+const ammIssuer = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+
+func (s *AMMSuite) TestAMMSwapOutput(c *C) {
+	tests := []struct {
+		poolIn, poolOut string
+		fee             uint32
+		in, expected    string
+	}{
+		{"100/USD/" + ammIssuer, "10000000000", 0, "10/USD/" + ammIssuer, "909.090909/XRP"},
+		{"100/USD/" + ammIssuer, "10000000000", 1000, "10/USD/" + ammIssuer, "900.818926/XRP"},
+		{"1000000000", "500/USD/" + ammIssuer, 500, "100000000", "45.24783992723965/USD/" + ammIssuer},
+		{"1000000000", "500/USD/" + ammIssuer, 500, "0", "0/USD/" + ammIssuer},
+	}
+	for _, test := range tests {
+		out, err := AMMSwapOutput(*amountCheck(test.poolIn), *amountCheck(test.poolOut), test.fee, *amountCheck(test.in))
+		c.Assert(err, IsNil)
+		c.Check(out.String(), Equals, test.expected)
+	}
+}
+
+func (s *AMMSuite) TestAMMSwapOutputErrors(c *C) {
+	usd, xrp := *amountCheck("100/USD/" + ammIssuer), *amountCheck("1000000")
+	_, err := AMMSwapOutput(usd, xrp, 0, *amountCheck("1"))
+	c.Check(err, ErrorMatches, "AMM swap input XRP does not match pool USD/.*")
+	_, err = AMMSwapOutput(usd, usd, 0, usd)
+	c.Check(err, ErrorMatches, "AMM pool assets must differ: USD/.*")
+	_, err = AMMSwapOutput(usd, xrp, 1001, usd)
+	c.Check(err, ErrorMatches, "AMM trading fee too high: 1001")
+	_, err = AMMSwapOutput(usd, *amountCheck("0"), 0, usd)
+	c.Check(err, ErrorMatches, "AMM pool reserves must be positive")
+}
+
+func (s *AMMSuite) TestEffectiveTradingFee(c *C) {
+	c.Check(EffectiveTradingFee(1000, false), Equals, uint32(1000))
+	c.Check(EffectiveTradingFee(1000, true), Equals, uint32(100))
+	c.Check(EffectiveTradingFee(605, true), Equals, uint32(60))
+	c.Check(EffectiveTradingFee(0, true), Equals, uint32(0))
+
+	poolIn, poolOut, in := *amountCheck("100/USD/" + ammIssuer), *amountCheck("10000000000"), *amountCheck("10/USD/" + ammIssuer)
+	holder, err := AMMSwapOutputForHolder(poolIn, poolOut, 1000, true, in)
+	c.Assert(err, IsNil)
+	discounted, err := AMMSwapOutput(poolIn, poolOut, 100, in)
+	c.Assert(err, IsNil)
+	c.Check(holder.String(), Equals, discounted.String())
+	c.Check(holder.String(), Equals, "908.264387/XRP")
+
+	other, err := AMMSwapOutputForHolder(poolIn, poolOut, 1000, false, in)
+	c.Assert(err, IsNil)
+	c.Check(other.String(), Equals, "900.818926/XRP")
+
+	_, err = AMMSwapOutputForHolder(poolIn, poolOut, 5000, true, in)
+	c.Check(err, ErrorMatches, "AMM trading fee too high: 5000")
+}
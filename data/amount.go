@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
+	"math/big"
 	"strings"
 
 	"github.com/atticlab/ripple/crypto"
@@ -84,6 +86,30 @@ func (a Amount) Equals(b Amount) bool {
 		a.Issuer == b.Issuer
 }
 
+// Compare orders amounts of the same currency and issuer by value, with
+// the result of Value.Compare. Amounts of different assets are ordered by
+// currency and then issuer, with XRP first.
+func (a Amount) Compare(b Amount) int {
+	switch {
+	case a.IsNative() && !b.IsNative():
+		return -1
+	case !a.IsNative() && b.IsNative():
+		return 1
+	}
+	if c := bytes.Compare(a.Currency.Bytes(), b.Currency.Bytes()); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(a.Issuer.Bytes(), b.Issuer.Bytes()); c != 0 {
+		return c
+	}
+	return a.Value.Compare(*b.Value)
+}
+
+// Less is true when a orders before b, as with Compare
+func (a Amount) Less(b Amount) bool {
+	return a.Compare(b) < 0
+}
+
 // Returns true if the values are equal, but ignores the currency and issuer
 func (a Amount) SameValue(b *Amount) bool {
 	return a.Value.Equals(*b.Value)
@@ -93,6 +119,67 @@ func (a Amount) Clone() *Amount {
 	return newAmount(a.Value.Clone(), a.Currency, a.Issuer)
 }
 
+// ZeroAmount returns a zero Amount of the currency and issuer,
+// which is native for XRP
+func ZeroAmount(c Currency, issuer Account) *Amount {
+	if c.IsNative() {
+		return newAmount(zeroNative.Clone(), zeroCurrency, zeroAccount)
+	}
+	return newAmount(zeroNonNative.Clone(), c, issuer)
+}
+
+// IsZero is true for a zero or missing Value, whatever its exponent
+func (a Amount) IsZero() bool {
+	return a.Value == nil || a.Value.IsZero()
+}
+
+// Validate checks that a native amount has no currency or issuer and
+// that a non-native amount is not in XRP, which rippled rejects
+func (a Amount) Validate() error {
+	switch iso, _ := a.Currency.ISO(); {
+	case a.Value == nil:
+		return fmt.Errorf("Amount has no value")
+	case a.IsNative() && !a.Currency.IsNative():
+		return fmt.Errorf("Native amount has a currency: %s", a.Currency)
+	case a.IsNative() && !a.Issuer.IsZero():
+		return fmt.Errorf("Native amount has an issuer: %s", a.Issuer)
+	case !a.IsNative() && iso == "XRP":
+		return fmt.Errorf("Non-native amount has XRP currency: %s", a.Value)
+	}
+	return nil
+}
+
+// SpendableBalance returns the XRP balance of an account in excess of its
+// reserve of baseReserve plus ownerReserve for each owned object, all in
+// drips. It is zero when the reserve is not met.
+func SpendableBalance(balance *Amount, ownerCount uint32, baseReserve, ownerReserve uint64) (*Amount, error) {
+	if balance == nil || balance.Value == nil || !balance.IsNative() {
+		return nil, fmt.Errorf("Spendable balance requires an XRP balance: %v", balance)
+	}
+	spendable := ZeroAmount(zeroCurrency, zeroAccount)
+	if balance.negative || ownerReserve != 0 && uint64(ownerCount) > (math.MaxUint64-baseReserve)/ownerReserve {
+		return spendable, nil
+	}
+	if reserve := baseReserve + uint64(ownerCount)*ownerReserve; balance.num > reserve {
+		value, err := NewNativeValue(int64(balance.num - reserve))
+		if err != nil {
+			return nil, err
+		}
+		spendable.Value = value
+	}
+	return spendable, nil
+}
+
+// Decimal returns the amount exactly as mantissa * 10^-scale.
+// Native amounts are in XRP with a scale of 6.
+func (a Amount) Decimal() (*big.Int, int, error) {
+	if a.Value == nil {
+		return nil, 0, fmt.Errorf("Amount has no value")
+	}
+	mantissa, scale := a.Value.Decimal()
+	return mantissa, scale, nil
+}
+
 // Returns a new Amount with the same currency and issuer, but a zero value
 func (a Amount) ZeroClone() *Amount {
 	return newAmount(a.Value.ZeroClone(), a.Currency, a.Issuer)
@@ -240,21 +327,40 @@ func (a Amount) Asset() *Asset {
 	}
 }
 
+// NewExchangeRate returns the quality of an offer paying a for b, as
+// rippled computes it for the index of the offer's BookDirectory: a/b with
+// native amounts in drops, the offset in the top byte and the mantissa in
+// the rest. Offers with a lower rate are better for the taker and are
+// crossed first. The rate is zero when either amount is.
 func NewExchangeRate(a, b *Amount) (ExchangeRate, error) {
-	if b.IsZero() {
+	if a.IsZero() || b.IsZero() {
 		return 0, nil
 	}
-	rate, err := a.Divide(b)
+	num, err := a.Value.NonNative()
+	if err != nil {
+		return 0, err
+	}
+	den, err := b.Value.NonNative()
+	if err != nil {
+		return 0, err
+	}
+	rate, err := num.Divide(*den)
 	if err != nil {
 		return 0, err
 	}
 	if rate.IsZero() {
 		return 0, nil
 	}
-	if rate.offset >= -100 || rate.offset <= 155 {
-		panic("Impossible Rate")
+	if rate.offset < -100 || rate.offset > 155 {
+		return 0, fmt.Errorf("Exchange rate out of range: %s", rate.debug())
 	}
-	return ExchangeRate(uint64(rate.offset+100)<<54 | uint64(rate.num)), nil
+	return ExchangeRate(uint64(rate.offset+100)<<56 | rate.num), nil
+}
+
+// Value returns the rate as a non-native Value
+func (e ExchangeRate) Value() (*Value, error) {
+	v := newValue(false, false, uint64(e)&(1<<56-1), int64(e>>56)-100)
+	return v, v.canonicalise()
 }
 
 func (e *ExchangeRate) Bytes() []byte {
@@ -1,7 +1,11 @@
 package data
 
 import (
+	"bytes"
 	"fmt"
+	"math"
+	"math/big"
+	"strings"
 	"testing"
 
 	. "github.com/atticlab/ripple/testing"
@@ -168,6 +172,204 @@ func (s *AmountSuite) TestAmount(c *C) {
 	amountTests.Test(c)
 }
 
+func (s *AmountSuite) TestZeroAmount(c *C) {
+	usd := amountCheck("1/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL")
+	zero := ZeroAmount(usd.Currency, usd.Issuer)
+	c.Check(zero.IsZero(), Equals, true)
+	c.Check(zero.IsNative(), Equals, false)
+	c.Check(zero.String(), Equals, "0/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL")
+	c.Check(ZeroAmount(zeroCurrency, usd.Issuer).String(), Equals, "0/XRP")
+	c.Check(ZeroAmount(zeroCurrency, usd.Issuer).IsZero(), Equals, true)
+	c.Check(Amount{}.IsZero(), Equals, true)
+	c.Check(usd.IsZero(), Equals, false)
+
+	// An offer consumed exactly
+	consumed, err := usd.Subtract(amountCheck("1/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL"))
+	c.Assert(err, IsNil)
+	c.Check(consumed.IsZero(), Equals, true)
+
+	for _, offset := range []int64{-96, -15, 0, 80} {
+		value, err := NewNonNativeValue(0, offset)
+		c.Assert(err, IsNil)
+		c.Check(newAmount(value, usd.Currency, usd.Issuer).IsZero(), Equals, true, Commentf("%d", offset))
+	}
+	// Non-canonical wire encodings of zero, with exponent and sign bits set
+	for _, u := range []uint64{0x8000000000000000, 0x9000000000000000, 0xC000000000000000, 0xBFC0000000000000} {
+		var value Value
+		c.Assert(value.Unmarshal(bytes.NewReader([]byte{byte(u >> 56), 0, 0, 0, 0, 0, 0, 0})), IsNil)
+		c.Check(newAmount(&value, usd.Currency, usd.Issuer).IsZero(), Equals, true, Commentf("%X", u))
+	}
+}
+
+func (s *AmountSuite) TestDecimal(c *C) {
+	xrp := big.NewRat(1000000, 1)
+	for _, t := range []struct {
+		Amount   string
+		Mantissa string
+		Scale    int
+	}{
+		{"0", "0", 6},
+		{"1", "1", 6},
+		{"-1", "-1", 6},
+		{"1.0", "1000000", 6},
+		{"100000000000", "100000000000", 6},
+		{"0/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL", "0", 0},
+		{"1/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL", "1", 0},
+		{"-12.5/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL", "-125", 1},
+		{"0.0000001234/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL", "1234", 10},
+		{"1234567890123456/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL", "1234567890123456", 0},
+		{"1e20/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL", "100000000000000000000", 0},
+		{"9999999999999999e80/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL", "9999999999999999" + strings.Repeat("0", 80), 0},
+		{"1e-81/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL", "1", 81},
+	} {
+		a := amountCheck(t.Amount)
+		mantissa, scale, err := a.Decimal()
+		c.Assert(err, IsNil)
+		c.Check(mantissa.String(), Equals, t.Mantissa, Commentf(t.Amount))
+		c.Check(scale, Equals, t.Scale, Commentf(t.Amount))
+		exact := new(big.Rat).SetFrac(mantissa, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil))
+		expected := a.Rat()
+		if a.IsNative() {
+			expected.Quo(expected, xrp)
+		}
+		c.Check(exact.Cmp(expected), Equals, 0, Commentf(t.Amount))
+	}
+	_, _, err := Amount{}.Decimal()
+	c.Check(err, ErrorMatches, "Amount has no value")
+}
+
+func (s *AmountSuite) TestSpendableBalance(c *C) {
+	const base, owner = 10000000, 2000000
+	for _, t := range []struct {
+		Balance    string
+		OwnerCount uint32
+		Expected   string
+	}{
+		{"14000000", 2, "0/XRP"},
+		{"14000001", 2, "0.000001/XRP"},
+		{"13999999", 2, "0/XRP"},
+		{"50000000", 0, "40/XRP"},
+		{"50000000", 5, "30/XRP"},
+		{"0", 0, "0/XRP"},
+		{"-1", 0, "0/XRP"},
+		{"100000000000000000", math.MaxUint32, "91410065400/XRP"},
+	} {
+		spendable, err := SpendableBalance(amountCheck(t.Balance), t.OwnerCount, base, owner)
+		c.Assert(err, IsNil)
+		c.Check(spendable.String(), Equals, t.Expected, Commentf("%s %d", t.Balance, t.OwnerCount))
+		c.Check(spendable.IsNative(), Equals, true)
+	}
+	spendable, err := SpendableBalance(amountCheck("50000000"), math.MaxUint32, math.MaxUint64-1, math.MaxUint64)
+	c.Assert(err, IsNil)
+	c.Check(spendable.IsZero(), Equals, true)
+	_, err = SpendableBalance(amountCheck("100/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL"), 0, base, owner)
+	c.Check(err, ErrorMatches, "Spendable balance requires an XRP balance: .*")
+	_, err = SpendableBalance(nil, 0, base, owner)
+	c.Check(err, NotNil)
+}
+
+func (s *AmountSuite) TestValidate(c *C) {
+	issuer := amountCheck("1/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL").Issuer
+	usd := amountCheck("1/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL").Currency
+	var xrpCode Currency
+	copy(xrpCode[12:], "XRP")
+	for _, t := range []struct {
+		Amount Amount
+		Error  string
+	}{
+		{*amountCheck("1"), ""},
+		{*amountCheck("1/USD/rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL"), ""},
+		{*amountCheck("1/USD"), ""},
+		{Amount{Value: amountCheck("1").Value, Issuer: issuer}, "Native amount has an issuer: rNDKeo9RrCiRdfsMG8AdoZvNZxHASGzbZL"},
+		{Amount{Value: amountCheck("1").Value, Currency: usd}, "Native amount has a currency: USD"},
+		{Amount{Value: amountCheck("1/USD").Value, Issuer: issuer}, "Non-native amount has XRP currency: 1"},
+		{Amount{Value: amountCheck("1/USD").Value, Currency: xrpCode, Issuer: issuer}, "Non-native amount has XRP currency: 1"},
+		{Amount{}, "Amount has no value"},
+	} {
+		err := t.Amount.Validate()
+		if t.Error == "" {
+			c.Check(err, IsNil)
+			_, err = t.Amount.MarshalJSON()
+			c.Check(err, IsNil)
+			continue
+		}
+		c.Check(err, ErrorMatches, t.Error)
+		if t.Amount.Value != nil {
+			_, err = t.Amount.MarshalJSON()
+			c.Check(err, ErrorMatches, t.Error)
+		}
+	}
+}
+
+func (s *AmountSuite) TestCompare(c *C) {
+	ordered := []string{
+		"-1/XRP",
+		"0.5/XRP",
+		"1/XRP",
+		"-1/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B",
+		"0/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B",
+		"1e-20/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B",
+		"2/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B",
+		"1/USD/rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			a, b := amountCheck(ordered[i]), amountCheck(ordered[j])
+			var expected int
+			switch {
+			case i < j:
+				expected = -1
+			case i > j:
+				expected = 1
+			}
+			c.Check(a.Compare(*b), Equals, expected, Commentf("%s %s", a, b))
+			c.Check(a.Less(*b), Equals, i < j)
+		}
+	}
+	c.Check(amountCheck("1.0/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B").Equals(*amountCheck("1/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")), Equals, true)
+}
+
+func (s *AmountSuite) TestExchangeRate(c *C) {
+	// Offers placed and not yet crossed, whose rates are the last 8 bytes
+	// of their BookDirectory
+	for _, test := range []struct {
+		pays, gets    interface{}
+		bookDirectory string
+	}{
+		{"0.034800328/BTC/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B", int64(5000500000), "37AAC93D336021AE94310D0430FFA090F7137C97D473488C4918B98284A03161"},
+		{"174.72/CNY/razqQKzJRdB4UxFPWf5NEpEG3WMkmwgcXA", int64(6400064000), "7254404DF6B7FBFFEF34DC38867A7E7DE610B513997B78804D09B2E54D0BD965"},
+		{"1.38387/LTC/rNPRNzBB92BVpAhhZr4iXDTveCgV5Pofm9", "47.04742839/ILS/rNPRNzBB92BVpAhhZr4iXDTveCgV5Pofm9", "C747B3E597BBEC549DAFCB8F1158E098FDC1825D522AFDA7530A733870731527"},
+	} {
+		pays, gets := amountCheck(test.pays), amountCheck(test.gets)
+		rate, err := NewExchangeRate(pays, gets)
+		c.Assert(err, IsNil)
+		text, err := rate.MarshalText()
+		c.Assert(err, IsNil)
+		c.Check(string(text), Equals, test.bookDirectory[48:], Commentf("%s %s", pays, gets))
+
+		bookDirectory, err := NewHash256(test.bookDirectory)
+		c.Assert(err, IsNil)
+		offer := Offer{TakerPays: pays, TakerGets: gets}
+		computed, err := offer.Quality()
+		c.Assert(err, IsNil)
+		offer.BookDirectory = bookDirectory
+		quality, err := offer.Quality()
+		c.Assert(err, IsNil)
+		c.Check(quality, Equals, computed)
+	}
+
+	rate, err := NewExchangeRate(amountCheck("1.86/JPY/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"), amountCheck(int64(1000000)))
+	c.Assert(err, IsNil)
+	c.Check(rate, Equals, ExchangeRate(0x4F069BA8FF484000))
+	value, err := rate.Value()
+	c.Assert(err, IsNil)
+	c.Check(value.String(), Equals, "0.00000186")
+
+	rate, err = NewExchangeRate(amountCheck("1/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"), amountCheck("0/XRP"))
+	c.Assert(err, IsNil)
+	c.Check(rate, Equals, ExchangeRate(0))
+}
+
 func ExampleValue_Add() {
 	v1, _ := NewValue("100", false)
 	v2, _ := NewValue("200.199", false)
@@ -58,7 +58,9 @@ func (s *BalanceSlice) Add(account *Account, balance, change *Value, currency *C
 }
 
 func (txm *TransactionWithMetaData) Balances() (BalanceSlice, error) {
-	if txm.GetTransactionType() != OFFER_CREATE && txm.GetTransactionType() != PAYMENT {
+	switch txm.GetTransactionType() {
+	case OFFER_CREATE, PAYMENT, CLAWBACK:
+	default:
 		return nil, nil
 	}
 	var (
@@ -136,3 +138,97 @@ func (txm *TransactionWithMetaData) Balances() (BalanceSlice, error) {
 	sort.Sort(balances)
 	return balances, nil
 }
+
+// BalanceChanges returns the net change to each account's balances caused by
+// the transaction, diffing the previous and final fields of every affected
+// AccountRoot and RippleState. XRP changes include the fee, so the sending
+// account's XRP change is what it received less what it spent and paid in fees.
+// Trust line changes are reported for both sides, with the counterparty as issuer.
+func (txm *TransactionWithMetaData) BalanceChanges() (map[Account][]Amount, error) {
+	changes := make(map[Account][]Amount)
+	add := func(account Account, change *Amount) {
+		if !change.IsZero() {
+			changes[account] = append(changes[account], *change)
+		}
+	}
+	for _, effect := range txm.MetaData.AffectedNodes {
+		_, final, previous, state := effect.AffectedNode()
+		switch current := final.(type) {
+		case *AccountRoot:
+			before := previous.(*AccountRoot).Balance
+			switch {
+			case current.Balance == nil:
+				continue
+			case before == nil && state != Created:
+				continue
+			case before == nil:
+				before = &zeroNative
+			}
+			change, err := current.Balance.Add(*before.Negate())
+			if err != nil {
+				return nil, err
+			}
+			add(*current.Account, newAmount(change, zeroCurrency, zeroAccount))
+		case *RippleState:
+			before := previous.(*RippleState).Balance
+			switch {
+			case current.Balance == nil:
+				continue
+			case before == nil && state != Created:
+				continue
+			case before == nil:
+				before = &Amount{Value: &zeroNonNative}
+			}
+			change, err := current.Balance.Value.Add(*before.Value.Negate())
+			if err != nil {
+				return nil, err
+			}
+			low, high := current.LowLimit.Issuer, current.HighLimit.Issuer
+			currency := current.Balance.Currency
+			add(low, newAmount(change, currency, high))
+			add(high, newAmount(change.Negate(), currency, low))
+		}
+	}
+	return changes, nil
+}
+
+// BalanceSummary is the effect of a transaction on balances, as usually
+// wanted when ingesting transactions
+type BalanceSummary struct {
+	Changes   map[Account][]Amount // As returned by BalanceChanges
+	Fee       Amount               // XRP destroyed, included in the XRP change of the sender
+	Delivered *Amount              // Received by the destination of a payment, if known
+}
+
+// BalanceSummary returns the net balance changes of each account by
+// currency and issuer, the fee burnt and the amount delivered by a
+// payment. Delivered is taken from the metadata, or is Amount for a
+// successful payment which is not partial, and is otherwise nil.
+func (txm *TransactionWithMetaData) BalanceSummary() (*BalanceSummary, error) {
+	changes, err := txm.BalanceChanges()
+	if err != nil {
+		return nil, err
+	}
+	summary := &BalanceSummary{
+		Changes: changes,
+		Fee:     *newAmount(txm.GetBase().Fee.Clone(), zeroCurrency, zeroAccount),
+	}
+	if delivered, ok := txm.MetaData.Delivered(); ok {
+		summary.Delivered = delivered.Clone()
+	} else if payment, ok := txm.Transaction.(*Payment); ok && txm.MetaData.TransactionResult.Success() &&
+		(payment.Flags == nil || *payment.Flags&TxPartialPayment == 0) {
+		summary.Delivered = payment.Amount.Clone()
+	}
+	return summary, nil
+}
+
+// Change returns the net change to the balance of account in asset, or nil
+// if it is unchanged
+func (s *BalanceSummary) Change(account Account, asset *Asset) *Amount {
+	for _, change := range s.Changes[account] {
+		if asset.Matches(&change) {
+			return change.Clone()
+		}
+	}
+	return nil
+}
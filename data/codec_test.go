@@ -93,3 +93,31 @@ func (s *CodecSuite) TestParseMetaData(c *C) {
 		}
 	}
 }
+
+func (s *CodecSuite) TestNodeId(c *C) {
+	for _, test := range internal.Transactions {
+		tx, err := ReadTransaction(test.Reader())
+		c.Assert(err, IsNil)
+		hash, _, err := Raw(tx)
+		c.Assert(err, IsNil)
+		nodeId, err := NodeId(tx)
+		c.Assert(err, IsNil)
+		c.Assert(nodeId.String(), Equals, hash.String(), Commentf(test.Description))
+	}
+	for _, test := range internal.Nodes {
+		expected, err := NewHash256(test.NodeId())
+		c.Assert(err, IsNil)
+		n, err := ReadPrefix(test.Reader(), *expected)
+		if err != nil {
+			continue
+		}
+		nodeId, err := NodeId(n)
+		c.Assert(err, IsNil)
+		hash, _, err := Raw(n)
+		c.Assert(err, IsNil)
+		c.Assert(nodeId.String(), Equals, hash.String(), Commentf(test.Description))
+		if _, ok := n.(*Ledger); ok {
+			c.Assert(nodeId.String(), Equals, expected.String(), Commentf(test.Description))
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"time"
 )
 
 type Currency [20]byte
@@ -45,6 +46,37 @@ func NewCurrency(s string) (Currency, error) {
 	}
 }
 
+// NewDemurrageCurrency builds an interest-bearing currency from a 3 character
+// code, an annual interest rate (negative for demurrage, -0.005 is 0.5%pa)
+// and the time from which interest accrues.
+func NewDemurrageCurrency(code string, interestRate float64, startTime time.Time) (Currency, error) {
+	var currency Currency
+	if len(code) != 3 || code == "XRP" {
+		return currency, fmt.Errorf("Bad Currency: %s", code)
+	}
+	if interestRate <= -1 || interestRate == 0 || math.IsNaN(interestRate) || math.IsInf(interestRate, 0) {
+		return currency, fmt.Errorf("Bad interest rate: %f", interestRate)
+	}
+	currency[0] = 0x01
+	copy(currency[1:4], []byte(code))
+	binary.BigEndian.PutUint32(currency[4:8], NewRippleTime(startTime).Uint32())
+	efold := float64(secondsInYear) / math.Log(1+interestRate)
+	binary.BigEndian.PutUint64(currency[8:16], math.Float64bits(efold))
+	return currency, nil
+}
+
+// Demurrage returns the annual interest rate and start time of an
+// interest-bearing currency. ok is false for any other currency type.
+func (c Currency) Demurrage() (rate float64, start time.Time, ok bool) {
+	if c.Type() != CT_DEMURRAGE {
+		return 0, time.Time{}, false
+	}
+	efold := math.Float64frombits(binary.BigEndian.Uint64(c[8:16]))
+	rate = math.Expm1(float64(secondsInYear) / efold)
+	start = RippleTime{binary.BigEndian.Uint32(c[4:8])}.Time()
+	return rate, start, true
+}
+
 func (a Currency) Compare(b Currency) int {
 	return bytes.Compare(a[:], b[:])
 }
@@ -107,6 +139,20 @@ func (c Currency) Rate(seconds uint32) float64 {
 
 const secondsInYear = uint32(3600 * 24 * 365)
 
+// ISO returns the three character code of XRP and of standard currencies.
+// It is false for hex, demurrage and unprintable currencies.
+func (c Currency) ISO() (string, bool) {
+	switch c.Type() {
+	case CT_XRP:
+		return "XRP", true
+	case CT_STANDARD:
+		if code := c.Machine(); len(code) == 3 {
+			return code, true
+		}
+	}
+	return "", false
+}
+
 // Currency in human parsable form
 // Demurrage is formatted, for example, as XAU (0.50%pa)
 func (c Currency) String() string {
@@ -1,6 +1,9 @@
 package data
 
 import (
+	"math"
+	"time"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -47,3 +50,56 @@ func (s *CurrencySuite) TestCurrencyTypes(c *C) {
 	c.Assert(wtf.String(), Equals, "0000000000000000000000007F80010000000000")
 	c.Assert(wtf.Type(), Equals, CT_STANDARD)
 }
+
+func (s *CurrencySuite) TestISO(c *C) {
+	for _, t := range []struct {
+		Currency string
+		ISO      string
+		OK       bool
+	}{
+		{"XRP", "XRP", true},
+		{"USD", "USD", true},
+		{"0000000000000000000000005553440000000000", "USD", true},
+		{"815841551A748AD2C1F76FF6ECB0CCCD00000000", "", false},
+		{"015841551A748AD2C1F76FF6ECB0CCCD00000000", "", false},
+		{"0000000000000000000000007F80010000000000", "", false},
+	} {
+		currency, err := NewCurrency(t.Currency)
+		c.Assert(err, IsNil)
+		iso, ok := currency.ISO()
+		c.Check(iso, Equals, t.ISO, Commentf(t.Currency))
+		c.Check(ok, Equals, t.OK, Commentf(t.Currency))
+	}
+}
+
+func (s *CurrencySuite) TestDemurrage(c *C) {
+	zero := time.Unix(rippleTimeEpoch, 0)
+	xau, err := NewDemurrageCurrency("XAU", -0.005, zero)
+	c.Assert(err, IsNil)
+	c.Assert(xau.Machine(), Equals, "0158415500000000C1F76FF6ECB0BAC600000000")
+	c.Assert(xau.String(), Equals, "XAU (0.50%pa)")
+
+	documented, err := NewCurrency("015841551A748AD2C1F76FF6ECB0CCCD00000000")
+	c.Assert(err, IsNil)
+	rate, start, ok := documented.Demurrage()
+	c.Assert(ok, Equals, true)
+	c.Assert(math.Abs(rate+0.005) < 1e-9, Equals, true, Commentf("%v", rate))
+	c.Assert(start.Equal(time.Date(2014, 1, 24, 2, 22, 10, 0, time.UTC)), Equals, true, Commentf("%v", start))
+
+	started, err := NewDemurrageCurrency("XAU", rate, start)
+	c.Assert(err, IsNil)
+	c.Assert(started[:8], DeepEquals, documented[:8])
+
+	usd, err := NewCurrency("USD")
+	c.Assert(err, IsNil)
+	_, _, ok = usd.Demurrage()
+	c.Assert(ok, Equals, false)
+
+	for _, bad := range []struct {
+		code string
+		rate float64
+	}{{"XRP", 0.01}, {"US", 0.01}, {"USD", 0}, {"USD", -1}} {
+		_, err := NewDemurrageCurrency(bad.code, bad.rate, zero)
+		c.Assert(err, NotNil, Commentf("%v", bad))
+	}
+}
@@ -1,6 +1,8 @@
 package data
 
 import (
+	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"reflect"
@@ -101,6 +103,29 @@ func ReadTransactionAndMetadata(tx, meta Reader, hash Hash256, ledger uint32) (*
 	return txm, nil
 }
 
+// DecodeTxBlob parses a hex encoded signed transaction, such as the
+// tx_blob of a submit request. The result has no metadata but the
+// transaction hash is computed from the blob.
+func DecodeTxBlob(hexBlob string) (*TransactionWithMetaData, error) {
+	txm := new(TransactionWithMetaData)
+	if err := txm.decodeTxBlob(hexBlob); err != nil {
+		return nil, err
+	}
+	return txm, nil
+}
+
+func (txm *TransactionWithMetaData) decodeTxBlob(hexBlob string) error {
+	b, err := hex.DecodeString(hexBlob)
+	if err != nil {
+		return fmt.Errorf("Bad tx_blob: %s", err)
+	}
+	if txm.Transaction, err = ReadTransaction(bytes.NewReader(b)); err != nil {
+		return err
+	}
+	*txm.GetHash(), err = txm.ComputeHash()
+	return err
+}
+
 // For internal use when reading Prefix format
 func readTransactionWithMetadata(r Reader, ledger uint32, nodeId Hash256) (*TransactionWithMetaData, error) {
 	br, err := NewVariableByteReader(r)
@@ -160,7 +185,11 @@ func ReadLedgerEntry(r Reader, nodeId Hash256) (LedgerEntry, error) {
 	if err != nil {
 		return nil, err
 	}
-	le := LedgerEntryFactory[leType]()
+	factory, err := GetLedgerEntryFactory(LedgerEntryType(leType))
+	if err != nil {
+		return nil, err
+	}
+	le := factory()
 	v := reflect.ValueOf(le)
 	// LedgerEntries have 32 bytes of index suffixed
 	// but don't have a variable bytes indicator
@@ -287,6 +316,13 @@ func readObject(r Reader, v *reflect.Value) error {
 				err := readObject(r, &m)
 				v.Set(m.Elem())
 				return err
+			case "AuthAccount":
+				var authAccount AuthAccount
+				a := reflect.ValueOf(&authAccount)
+				inner := reflect.ValueOf(&authAccount.AuthAccount)
+				err := readObject(r, &inner)
+				v.Set(a.Elem())
+				return err
 			case "Memo":
 				var memo Memo
 				m := reflect.ValueOf(&memo)
@@ -294,6 +330,20 @@ func readObject(r Reader, v *reflect.Value) error {
 				err := readObject(r, &inner)
 				v.Set(m.Elem())
 				return err
+			case "PriceData":
+				var priceData PriceData
+				p := reflect.ValueOf(&priceData)
+				inner := reflect.ValueOf(&priceData.PriceData)
+				err := readObject(r, &inner)
+				v.Set(p.Elem())
+				return err
+			case "NFToken":
+				var nftoken NFToken
+				n := reflect.ValueOf(&nftoken)
+				inner := reflect.ValueOf(&nftoken.NFToken)
+				err := readObject(r, &inner)
+				v.Set(n.Elem())
+				return err
 			default:
 				return fmt.Errorf("Unexpected object: %s for field: %s", v.Type(), name)
 			}
@@ -8,21 +8,41 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+
+	"github.com/atticlab/ripple/crypto"
 )
 
 func Raw(h Hashable) (Hash256, []byte, error) {
 	return raw(h, h.Prefix(), nil, false)
 }
 
+// NodeId streams the serialization of h into the hasher
+// rather than buffering it.
 func NodeId(h Hashable) (Hash256, error) {
-	nodeid, _, err := raw(h, h.Prefix(), nil, false)
-	return nodeid, err
+	hasher := crypto.NewHasher(uint32(h.Prefix()))
+	if err := writeRaw(hasher, h, false); err != nil {
+		return zero256, err
+	}
+	return Hash256(hasher.Sum256()), nil
 }
 
 func SigningHash(s SignerAgent, signingSuffix []byte) (Hash256, []byte, error) {
 	return raw(s, s.SigningPrefix(), signingSuffix, true)
 }
 
+// EncodeTxBlob returns the transaction as upper case hex in the canonical
+// binary format, such as the tx_blob of a submit request
+func (txm *TransactionWithMetaData) EncodeTxBlob() (string, error) {
+	if txm.Transaction == nil {
+		return "", fmt.Errorf("No transaction to encode")
+	}
+	_, raw, err := Raw(txm.Transaction)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%X", raw), nil
+}
+
 func Node(h Storer) (Hash256, []byte, error) {
 	var header bytes.Buffer
 	for _, v := range []interface{}{h.Ledger(), h.Ledger(), h.NodeType(), h.Prefix()} {
@@ -87,7 +107,11 @@ func writeRaw(w io.Writer, value interface{}, ignoreSigningFields bool) error {
 		}
 		return write(w, txid)
 	case Transaction:
-		return encode(w, value, ignoreSigningFields)
+		tx, err := prepareSigners(v)
+		if err != nil {
+			return err
+		}
+		return encode(w, tx, ignoreSigningFields)
 	case LedgerEntry:
 		if err := encode(w, v, ignoreSigningFields); err != nil {
 			return err
@@ -104,7 +128,10 @@ func writeRaw(w io.Writer, value interface{}, ignoreSigningFields bool) error {
 
 func encode(w io.Writer, value interface{}, ignoreSigningFields bool) error {
 	v := reflect.Indirect(reflect.ValueOf(value))
-	fields := getFields(&v, 0)
+	// Only the optional hashes of transactions are omitted when zero, the
+	// fields of ledger entries and metadata are encoded as they are
+	_, omitZeroHashes := value.(Transaction)
+	fields := getFields(&v, 0, omitZeroHashes)
 	// fmt.Println(fields.String())
 	return fields.Each(func(e enc, v interface{}) error {
 		if err := writeEncoding(w, e); err != nil {
@@ -141,7 +168,7 @@ func (s *fieldSlice) Append(e enc, v interface{}, children fieldSlice) {
 	*s = append(*s, field{e, v, children})
 }
 
-func getFields(v *reflect.Value, depth int) fieldSlice {
+func getFields(v *reflect.Value, depth int, omitZeroHashes bool) fieldSlice {
 	// fmt.Println(v, v.Kind(), v.Type().Name())
 	length := v.NumField()
 	fields := make(fieldSlice, 0, length)
@@ -155,6 +182,10 @@ func getFields(v *reflect.Value, depth int) fieldSlice {
 		if fieldName == "LedgerEntryType" && depth > 1 && typ.Name() == "leBase" {
 			continue
 		}
+		// The index of a ledger entry is its key, not one of its fields
+		if fieldName == "LedgerIndex" && typ.Name() == "leBase" {
+			continue
+		}
 		encoding := reverseEncodings[fieldName]
 		f := v.Field(i)
 		// fmt.Println(fieldName, encoding, f, f.Kind())
@@ -162,30 +193,40 @@ func getFields(v *reflect.Value, depth int) fieldSlice {
 			f = f.Elem()
 		}
 		if f.Kind() == reflect.Ptr {
+			// Optional hashes are omitted when zero
+			if omitZeroHashes && f.Type() == hash256PtrType && !f.IsNil() && f.CanInterface() && f.Interface().(*Hash256).IsZero() {
+				continue
+			}
 			f = f.Elem()
 		}
+		// The exported fields of an unexported embedded struct, such as
+		// leBase, are encoded although the struct itself is inaccessible
+		if typ.Field(i).Anonymous && f.Kind() == reflect.Struct && !f.CanInterface() {
+			fields = append(fields, getFields(&f, depth+1, omitZeroHashes)...)
+			continue
+		}
 		if !f.IsValid() || !f.CanInterface() || (f.Kind() == reflect.Slice && f.Len() == 0) {
 			continue
 		}
 		switch encoding.typ {
 		case ST_UINT8, ST_UINT16, ST_UINT32, ST_UINT64:
 			fields.Append(encoding, f.Addr().Interface(), nil)
-		case ST_HASH128, ST_HASH256, ST_AMOUNT, ST_VL, ST_ACCOUNT, ST_HASH160, ST_PATHSET, ST_VECTOR256:
+		case ST_HASH128, ST_HASH256, ST_AMOUNT, ST_VL, ST_ACCOUNT, ST_HASH160, ST_PATHSET, ST_VECTOR256, ST_ISSUE, ST_CURRENCY:
 			fields.Append(encoding, f.Addr().Interface(), nil)
 		case ST_ARRAY:
 			var children fieldSlice
 			for i := 0; i < f.Len(); i++ {
 				f2 := f.Index(i)
-				children = append(children, getFields(&f2, depth+1)...)
+				children = append(children, getFields(&f2, depth+1, omitZeroHashes)...)
 			}
 			children.Append(reverseEncodings["EndOfArray"], nil, nil)
 			fields.Append(encoding, nil, children)
 		case ST_OBJECT:
-			children := getFields(&f, depth+1)
+			children := getFields(&f, depth+1, omitZeroHashes)
 			children.Append(reverseEncodings["EndOfObject"], nil, nil)
 			fields.Append(encoding, nil, children)
 		default:
-			fields = append(fields, getFields(&f, depth+1)...)
+			fields = append(fields, getFields(&f, depth+1, omitZeroHashes)...)
 		}
 	}
 	fields.Sort()
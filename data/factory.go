@@ -1,5 +1,7 @@
 package data
 
+import "fmt"
+
 // Horrible look up tables
 // Could all this be one big map?
 
@@ -8,40 +10,52 @@ type TransactionType uint16
 
 const (
 	// LedgerEntryType values come from rippled's "LedgerFormats.h"
-	SIGNER_LIST   LedgerEntryType = 0x53 // 'S'
-	TICKET        LedgerEntryType = 0x54 // 'T'
-	ACCOUNT_ROOT  LedgerEntryType = 0x61 // 'a'
-	DIRECTORY     LedgerEntryType = 0x64 // 'd'
-	AMENDMENTS    LedgerEntryType = 0x66 // 'f'
-	LEDGER_HASHES LedgerEntryType = 0x68 // 'h'
-	OFFER         LedgerEntryType = 0x6f // 'o'
-	RIPPLE_STATE  LedgerEntryType = 0x72 // 'r'
-	FEE_SETTINGS  LedgerEntryType = 0x73 // 's'
-	ESCROW        LedgerEntryType = 0x75 // 'u'
-	PAY_CHANNEL   LedgerEntryType = 0x78 // 'x'
-	CHECK         LedgerEntryType = 0x63 // 'C'
+	SIGNER_LIST     LedgerEntryType = 0x53 // 'S'
+	TICKET          LedgerEntryType = 0x54 // 'T'
+	ACCOUNT_ROOT    LedgerEntryType = 0x61 // 'a'
+	DIRECTORY       LedgerEntryType = 0x64 // 'd'
+	AMENDMENTS      LedgerEntryType = 0x66 // 'f'
+	LEDGER_HASHES   LedgerEntryType = 0x68 // 'h'
+	OFFER           LedgerEntryType = 0x6f // 'o'
+	RIPPLE_STATE    LedgerEntryType = 0x72 // 'r'
+	FEE_SETTINGS    LedgerEntryType = 0x73 // 's'
+	ESCROW          LedgerEntryType = 0x75 // 'u'
+	PAY_CHANNEL     LedgerEntryType = 0x78 // 'x'
+	CHECK           LedgerEntryType = 0x63 // 'C'
+	ORACLE          LedgerEntryType = 0x80
+	NFTOKEN_PAGE    LedgerEntryType = 0x50 // 'P'
+	NFTOKEN_OFFER   LedgerEntryType = 0x37
+	DEPOSIT_PREAUTH LedgerEntryType = 0x70 // 'p'
 
 	// TransactionType values come from rippled's "TxFormats.h"
-	PAYMENT         TransactionType = 0
-	ESCROW_CREATE   TransactionType = 1
-	ESCROW_FINISH   TransactionType = 2
-	ACCOUNT_SET     TransactionType = 3
-	ESCROW_CANCEL   TransactionType = 4
-	SET_REGULAR_KEY TransactionType = 5
-	OFFER_CREATE    TransactionType = 7
-	OFFER_CANCEL    TransactionType = 8
-	TICKET_CREATE   TransactionType = 10
-	TICKET_CANCEL   TransactionType = 11
-	SIGNER_LIST_SET TransactionType = 12
-	PAYCHAN_CREATE  TransactionType = 13
-	PAYCHAN_FUND    TransactionType = 14
-	PAYCHAN_CLAIM   TransactionType = 15
-	CHECK_CREATE    TransactionType = 16
-	CHECK_CASH      TransactionType = 17
-	CHECK_CANCEL    TransactionType = 18
-	TRUST_SET       TransactionType = 20
-	AMENDMENT       TransactionType = 100
-	SET_FEE         TransactionType = 101
+	PAYMENT              TransactionType = 0
+	ESCROW_CREATE        TransactionType = 1
+	ESCROW_FINISH        TransactionType = 2
+	ACCOUNT_SET          TransactionType = 3
+	ESCROW_CANCEL        TransactionType = 4
+	SET_REGULAR_KEY      TransactionType = 5
+	OFFER_CREATE         TransactionType = 7
+	OFFER_CANCEL         TransactionType = 8
+	TICKET_CREATE        TransactionType = 10
+	TICKET_CANCEL        TransactionType = 11
+	SIGNER_LIST_SET      TransactionType = 12
+	PAYCHAN_CREATE       TransactionType = 13
+	PAYCHAN_FUND         TransactionType = 14
+	PAYCHAN_CLAIM        TransactionType = 15
+	CHECK_CREATE         TransactionType = 16
+	CHECK_CASH           TransactionType = 17
+	CHECK_CANCEL         TransactionType = 18
+	DEPOSIT_PREAUTH_TX   TransactionType = 19
+	TRUST_SET            TransactionType = 20
+	NFTOKEN_MINT         TransactionType = 25
+	NFTOKEN_BURN         TransactionType = 26
+	NFTOKEN_CREATE_OFFER TransactionType = 27
+	NFTOKEN_CANCEL_OFFER TransactionType = 28
+	NFTOKEN_ACCEPT_OFFER TransactionType = 29
+	CLAWBACK             TransactionType = 30
+	AMM_BID              TransactionType = 39
+	AMENDMENT            TransactionType = 100
+	SET_FEE              TransactionType = 101
 )
 
 var LedgerFactory = [...]func() Hashable{
@@ -49,90 +63,120 @@ var LedgerFactory = [...]func() Hashable{
 }
 
 var LedgerEntryFactory = [...]func() LedgerEntry{
-	ACCOUNT_ROOT:  func() LedgerEntry { return &AccountRoot{leBase: leBase{LedgerEntryType: ACCOUNT_ROOT}} },
-	DIRECTORY:     func() LedgerEntry { return &Directory{leBase: leBase{LedgerEntryType: DIRECTORY}} },
-	AMENDMENTS:    func() LedgerEntry { return &Amendments{leBase: leBase{LedgerEntryType: AMENDMENTS}} },
-	LEDGER_HASHES: func() LedgerEntry { return &LedgerHashes{leBase: leBase{LedgerEntryType: LEDGER_HASHES}} },
-	OFFER:         func() LedgerEntry { return &Offer{leBase: leBase{LedgerEntryType: OFFER}} },
-	RIPPLE_STATE:  func() LedgerEntry { return &RippleState{leBase: leBase{LedgerEntryType: RIPPLE_STATE}} },
-	FEE_SETTINGS:  func() LedgerEntry { return &FeeSettings{leBase: leBase{LedgerEntryType: FEE_SETTINGS}} },
-	ESCROW:        func() LedgerEntry { return &Escrow{leBase: leBase{LedgerEntryType: ESCROW}} },
-	SIGNER_LIST:   func() LedgerEntry { return &SignerList{leBase: leBase{LedgerEntryType: SIGNER_LIST}} },
-	TICKET:        func() LedgerEntry { return &Ticket{leBase: leBase{LedgerEntryType: TICKET}} },
-	PAY_CHANNEL:   func() LedgerEntry { return &PayChannel{leBase: leBase{LedgerEntryType: PAY_CHANNEL}} },
-	CHECK:         func() LedgerEntry { return &Check{leBase: leBase{LedgerEntryType: CHECK}} },
+	ACCOUNT_ROOT:    func() LedgerEntry { return &AccountRoot{leBase: leBase{LedgerEntryType: ACCOUNT_ROOT}} },
+	DIRECTORY:       func() LedgerEntry { return &Directory{leBase: leBase{LedgerEntryType: DIRECTORY}} },
+	AMENDMENTS:      func() LedgerEntry { return &Amendments{leBase: leBase{LedgerEntryType: AMENDMENTS}} },
+	LEDGER_HASHES:   func() LedgerEntry { return &LedgerHashes{leBase: leBase{LedgerEntryType: LEDGER_HASHES}} },
+	OFFER:           func() LedgerEntry { return &Offer{leBase: leBase{LedgerEntryType: OFFER}} },
+	RIPPLE_STATE:    func() LedgerEntry { return &RippleState{leBase: leBase{LedgerEntryType: RIPPLE_STATE}} },
+	FEE_SETTINGS:    func() LedgerEntry { return &FeeSettings{leBase: leBase{LedgerEntryType: FEE_SETTINGS}} },
+	ESCROW:          func() LedgerEntry { return &Escrow{leBase: leBase{LedgerEntryType: ESCROW}} },
+	SIGNER_LIST:     func() LedgerEntry { return &SignerList{leBase: leBase{LedgerEntryType: SIGNER_LIST}} },
+	TICKET:          func() LedgerEntry { return &Ticket{leBase: leBase{LedgerEntryType: TICKET}} },
+	PAY_CHANNEL:     func() LedgerEntry { return &PayChannel{leBase: leBase{LedgerEntryType: PAY_CHANNEL}} },
+	CHECK:           func() LedgerEntry { return &Check{leBase: leBase{LedgerEntryType: CHECK}} },
+	ORACLE:          func() LedgerEntry { return &Oracle{leBase: leBase{LedgerEntryType: ORACLE}} },
+	NFTOKEN_PAGE:    func() LedgerEntry { return &NFTokenPage{leBase: leBase{LedgerEntryType: NFTOKEN_PAGE}} },
+	NFTOKEN_OFFER:   func() LedgerEntry { return &NFTokenOffer{leBase: leBase{LedgerEntryType: NFTOKEN_OFFER}} },
+	DEPOSIT_PREAUTH: func() LedgerEntry { return &DepositPreauth{leBase: leBase{LedgerEntryType: DEPOSIT_PREAUTH}} },
 }
 
 var TxFactory = [...]func() Transaction{
-	PAYMENT:         func() Transaction { return &Payment{TxBase: TxBase{TransactionType: PAYMENT}} },
-	ACCOUNT_SET:     func() Transaction { return &AccountSet{TxBase: TxBase{TransactionType: ACCOUNT_SET}} },
-	SET_REGULAR_KEY: func() Transaction { return &SetRegularKey{TxBase: TxBase{TransactionType: SET_REGULAR_KEY}} },
-	OFFER_CREATE:    func() Transaction { return &OfferCreate{TxBase: TxBase{TransactionType: OFFER_CREATE}} },
-	OFFER_CANCEL:    func() Transaction { return &OfferCancel{TxBase: TxBase{TransactionType: OFFER_CANCEL}} },
-	TRUST_SET:       func() Transaction { return &TrustSet{TxBase: TxBase{TransactionType: TRUST_SET}} },
-	AMENDMENT:       func() Transaction { return &Amendment{TxBase: TxBase{TransactionType: AMENDMENT}} },
-	SET_FEE:         func() Transaction { return &SetFee{TxBase: TxBase{TransactionType: SET_FEE}} },
-	ESCROW_CREATE:   func() Transaction { return &EscrowCreate{TxBase: TxBase{TransactionType: ESCROW_CREATE}} },
-	ESCROW_FINISH:   func() Transaction { return &EscrowFinish{TxBase: TxBase{TransactionType: ESCROW_FINISH}} },
-	ESCROW_CANCEL:   func() Transaction { return &EscrowCancel{TxBase: TxBase{TransactionType: ESCROW_CANCEL}} },
-	SIGNER_LIST_SET: func() Transaction { return &SignerListSet{TxBase: TxBase{TransactionType: SIGNER_LIST_SET}} },
-	PAYCHAN_CREATE:  func() Transaction { return &PaymentChannelCreate{TxBase: TxBase{TransactionType: PAYCHAN_CREATE}} },
-	PAYCHAN_FUND:    func() Transaction { return &PaymentChannelFund{TxBase: TxBase{TransactionType: PAYCHAN_FUND}} },
-	PAYCHAN_CLAIM:   func() Transaction { return &PaymentChannelClaim{TxBase: TxBase{TransactionType: PAYCHAN_CLAIM}} },
-	CHECK_CREATE:    func() Transaction { return &CheckCreate{TxBase: TxBase{TransactionType: CHECK_CREATE}} },
-	CHECK_CASH:      func() Transaction { return &CheckCash{TxBase: TxBase{TransactionType: CHECK_CASH}} },
-	CHECK_CANCEL:    func() Transaction { return &CheckCancel{TxBase: TxBase{TransactionType: CHECK_CANCEL}} },
+	PAYMENT:              func() Transaction { return &Payment{TxBase: TxBase{TransactionType: PAYMENT}} },
+	ACCOUNT_SET:          func() Transaction { return &AccountSet{TxBase: TxBase{TransactionType: ACCOUNT_SET}} },
+	SET_REGULAR_KEY:      func() Transaction { return &SetRegularKey{TxBase: TxBase{TransactionType: SET_REGULAR_KEY}} },
+	OFFER_CREATE:         func() Transaction { return &OfferCreate{TxBase: TxBase{TransactionType: OFFER_CREATE}} },
+	OFFER_CANCEL:         func() Transaction { return &OfferCancel{TxBase: TxBase{TransactionType: OFFER_CANCEL}} },
+	TRUST_SET:            func() Transaction { return &TrustSet{TxBase: TxBase{TransactionType: TRUST_SET}} },
+	AMENDMENT:            func() Transaction { return &Amendment{TxBase: TxBase{TransactionType: AMENDMENT}} },
+	SET_FEE:              func() Transaction { return &SetFee{TxBase: TxBase{TransactionType: SET_FEE}} },
+	ESCROW_CREATE:        func() Transaction { return &EscrowCreate{TxBase: TxBase{TransactionType: ESCROW_CREATE}} },
+	ESCROW_FINISH:        func() Transaction { return &EscrowFinish{TxBase: TxBase{TransactionType: ESCROW_FINISH}} },
+	ESCROW_CANCEL:        func() Transaction { return &EscrowCancel{TxBase: TxBase{TransactionType: ESCROW_CANCEL}} },
+	SIGNER_LIST_SET:      func() Transaction { return &SignerListSet{TxBase: TxBase{TransactionType: SIGNER_LIST_SET}} },
+	PAYCHAN_CREATE:       func() Transaction { return &PaymentChannelCreate{TxBase: TxBase{TransactionType: PAYCHAN_CREATE}} },
+	PAYCHAN_FUND:         func() Transaction { return &PaymentChannelFund{TxBase: TxBase{TransactionType: PAYCHAN_FUND}} },
+	PAYCHAN_CLAIM:        func() Transaction { return &PaymentChannelClaim{TxBase: TxBase{TransactionType: PAYCHAN_CLAIM}} },
+	TICKET_CREATE:        func() Transaction { return &TicketCreate{TxBase: TxBase{TransactionType: TICKET_CREATE}} },
+	CHECK_CREATE:         func() Transaction { return &CheckCreate{TxBase: TxBase{TransactionType: CHECK_CREATE}} },
+	CHECK_CASH:           func() Transaction { return &CheckCash{TxBase: TxBase{TransactionType: CHECK_CASH}} },
+	CHECK_CANCEL:         func() Transaction { return &CheckCancel{TxBase: TxBase{TransactionType: CHECK_CANCEL}} },
+	DEPOSIT_PREAUTH_TX:   func() Transaction { return &DepositPreauthTx{TxBase: TxBase{TransactionType: DEPOSIT_PREAUTH_TX}} },
+	AMM_BID:              func() Transaction { return &AMMBid{TxBase: TxBase{TransactionType: AMM_BID}} },
+	NFTOKEN_MINT:         func() Transaction { return &NFTokenMint{TxBase: TxBase{TransactionType: NFTOKEN_MINT}} },
+	NFTOKEN_BURN:         func() Transaction { return &NFTokenBurn{TxBase: TxBase{TransactionType: NFTOKEN_BURN}} },
+	NFTOKEN_CREATE_OFFER: func() Transaction { return &NFTokenCreateOffer{TxBase: TxBase{TransactionType: NFTOKEN_CREATE_OFFER}} },
+	NFTOKEN_CANCEL_OFFER: func() Transaction { return &NFTokenCancelOffer{TxBase: TxBase{TransactionType: NFTOKEN_CANCEL_OFFER}} },
+	NFTOKEN_ACCEPT_OFFER: func() Transaction { return &NFTokenAcceptOffer{TxBase: TxBase{TransactionType: NFTOKEN_ACCEPT_OFFER}} },
+	CLAWBACK:             func() Transaction { return &Clawback{TxBase: TxBase{TransactionType: CLAWBACK}} },
 }
 
 var ledgerEntryNames = [...]string{
-	ACCOUNT_ROOT:  "AccountRoot",
-	DIRECTORY:     "DirectoryNode",
-	AMENDMENTS:    "Amendments",
-	LEDGER_HASHES: "LedgerHashes",
-	OFFER:         "Offer",
-	RIPPLE_STATE:  "RippleState",
-	FEE_SETTINGS:  "FeeSettings",
-	ESCROW:        "Escrow",
-	SIGNER_LIST:   "SignerList",
-	TICKET:        "Ticket",
-	PAY_CHANNEL:   "PayChannel",
-	CHECK:         "Check",
+	ACCOUNT_ROOT:    "AccountRoot",
+	DIRECTORY:       "DirectoryNode",
+	AMENDMENTS:      "Amendments",
+	LEDGER_HASHES:   "LedgerHashes",
+	OFFER:           "Offer",
+	RIPPLE_STATE:    "RippleState",
+	FEE_SETTINGS:    "FeeSettings",
+	ESCROW:          "Escrow",
+	SIGNER_LIST:     "SignerList",
+	TICKET:          "Ticket",
+	PAY_CHANNEL:     "PayChannel",
+	CHECK:           "Check",
+	ORACLE:          "Oracle",
+	NFTOKEN_PAGE:    "NFTokenPage",
+	NFTOKEN_OFFER:   "NFTokenOffer",
+	DEPOSIT_PREAUTH: "DepositPreauth",
 }
 
 var ledgerEntryTypes = map[string]LedgerEntryType{
-	"AccountRoot":   ACCOUNT_ROOT,
-	"DirectoryNode": DIRECTORY,
-	"Amendments":    AMENDMENTS,
-	"LedgerHashes":  LEDGER_HASHES,
-	"Offer":         OFFER,
-	"RippleState":   RIPPLE_STATE,
-	"FeeSettings":   FEE_SETTINGS,
-	"Escrow":        ESCROW,
-	"SignerList":    SIGNER_LIST,
-	"Ticket":        TICKET,
-	"PayChannel":    PAY_CHANNEL,
-	"Check":         CHECK,
+	"AccountRoot":    ACCOUNT_ROOT,
+	"DirectoryNode":  DIRECTORY,
+	"Amendments":     AMENDMENTS,
+	"LedgerHashes":   LEDGER_HASHES,
+	"Offer":          OFFER,
+	"RippleState":    RIPPLE_STATE,
+	"FeeSettings":    FEE_SETTINGS,
+	"Escrow":         ESCROW,
+	"SignerList":     SIGNER_LIST,
+	"Ticket":         TICKET,
+	"PayChannel":     PAY_CHANNEL,
+	"Check":          CHECK,
+	"Oracle":         ORACLE,
+	"NFTokenPage":    NFTOKEN_PAGE,
+	"NFTokenOffer":   NFTOKEN_OFFER,
+	"DepositPreauth": DEPOSIT_PREAUTH,
 }
 
 var txNames = [...]string{
-	PAYMENT:         "Payment",
-	ACCOUNT_SET:     "AccountSet",
-	SET_REGULAR_KEY: "SetRegularKey",
-	OFFER_CREATE:    "OfferCreate",
-	OFFER_CANCEL:    "OfferCancel",
-	TRUST_SET:       "TrustSet",
-	AMENDMENT:       "EnableAmendment",
-	SET_FEE:         "SetFee",
-	ESCROW_CREATE:   "EscrowCreate",
-	ESCROW_FINISH:   "EscrowFinish",
-	ESCROW_CANCEL:   "EscrowCancel",
-	SIGNER_LIST_SET: "SignerListSet",
-	PAYCHAN_CREATE:  "PaymentChannelCreate",
-	PAYCHAN_FUND:    "PaymentChannelFund",
-	PAYCHAN_CLAIM:   "PaymentChannelClaim",
-	CHECK_CREATE:    "CheckCreate",
-	CHECK_CASH:      "CheckCash",
-	CHECK_CANCEL:    "CheckCancel",
+	PAYMENT:              "Payment",
+	ACCOUNT_SET:          "AccountSet",
+	SET_REGULAR_KEY:      "SetRegularKey",
+	OFFER_CREATE:         "OfferCreate",
+	OFFER_CANCEL:         "OfferCancel",
+	TRUST_SET:            "TrustSet",
+	AMENDMENT:            "EnableAmendment",
+	SET_FEE:              "SetFee",
+	ESCROW_CREATE:        "EscrowCreate",
+	ESCROW_FINISH:        "EscrowFinish",
+	ESCROW_CANCEL:        "EscrowCancel",
+	SIGNER_LIST_SET:      "SignerListSet",
+	PAYCHAN_CREATE:       "PaymentChannelCreate",
+	PAYCHAN_FUND:         "PaymentChannelFund",
+	PAYCHAN_CLAIM:        "PaymentChannelClaim",
+	TICKET_CREATE:        "TicketCreate",
+	CHECK_CREATE:         "CheckCreate",
+	CHECK_CASH:           "CheckCash",
+	CHECK_CANCEL:         "CheckCancel",
+	DEPOSIT_PREAUTH_TX:   "DepositPreauth",
+	AMM_BID:              "AMMBid",
+	NFTOKEN_MINT:         "NFTokenMint",
+	NFTOKEN_BURN:         "NFTokenBurn",
+	NFTOKEN_CREATE_OFFER: "NFTokenCreateOffer",
+	NFTOKEN_CANCEL_OFFER: "NFTokenCancelOffer",
+	NFTOKEN_ACCEPT_OFFER: "NFTokenAcceptOffer",
+	CLAWBACK:             "Clawback",
 }
 
 var txTypes = map[string]TransactionType{
@@ -151,9 +195,18 @@ var txTypes = map[string]TransactionType{
 	"PaymentChannelCreate": PAYCHAN_CREATE,
 	"PaymentChannelFund":   PAYCHAN_FUND,
 	"PaymentChannelClaim":  PAYCHAN_CLAIM,
+	"TicketCreate":         TICKET_CREATE,
 	"CheckCreate":          CHECK_CREATE,
 	"CheckCash":            CHECK_CASH,
 	"CheckCancel":          CHECK_CANCEL,
+	"DepositPreauth":       DEPOSIT_PREAUTH_TX,
+	"AMMBid":               AMM_BID,
+	"NFTokenMint":          NFTOKEN_MINT,
+	"NFTokenBurn":          NFTOKEN_BURN,
+	"NFTokenCreateOffer":   NFTOKEN_CREATE_OFFER,
+	"NFTokenCancelOffer":   NFTOKEN_CANCEL_OFFER,
+	"NFTokenAcceptOffer":   NFTOKEN_ACCEPT_OFFER,
+	"Clawback":             CLAWBACK,
 }
 
 var HashableTypes []string
@@ -188,3 +241,12 @@ func GetTxFactoryByType(txType string) func() Transaction {
 func GetLedgerEntryFactoryByType(leType string) func() LedgerEntry {
 	return LedgerEntryFactory[ledgerEntryTypes[leType]]
 }
+
+// GetLedgerEntryFactory returns the factory for leType or an error
+// if it is not a known ledger entry type
+func GetLedgerEntryFactory(leType LedgerEntryType) (func() LedgerEntry, error) {
+	if int(leType) >= len(LedgerEntryFactory) || LedgerEntryFactory[leType] == nil {
+		return nil, fmt.Errorf("Unknown LedgerEntryType: 0x%04X", uint16(leType))
+	}
+	return LedgerEntryFactory[leType], nil
+}
@@ -19,20 +19,22 @@ const (
 	TxCircle         TransactionFlag = 0x00080000 // Not implemented
 
 	// AccountSet flags
-	TxSetRequireDest   TransactionFlag = 0x00000001
-	TxSetRequireAuth   TransactionFlag = 0x00000002
-	TxSetDisallowXRP   TransactionFlag = 0x00000003
-	TxSetDisableMaster TransactionFlag = 0x00000004
-	TxSetAccountTxnID  TransactionFlag = 0x00000005
-	TxNoFreeze         TransactionFlag = 0x00000006
-	TxGlobalFreeze     TransactionFlag = 0x00000007
-	TxDefaultRipple    TransactionFlag = 0x00000008
-	TxRequireDestTag   TransactionFlag = 0x00010000
-	TxOptionalDestTag  TransactionFlag = 0x00020000
-	TxRequireAuth      TransactionFlag = 0x00040000
-	TxOptionalAuth     TransactionFlag = 0x00080000
-	TxDisallowXRP      TransactionFlag = 0x00100000
-	TxAllowXRP         TransactionFlag = 0x00200000
+	TxSetRequireDest            TransactionFlag = 0x00000001
+	TxSetRequireAuth            TransactionFlag = 0x00000002
+	TxSetDisallowXRP            TransactionFlag = 0x00000003
+	TxSetDisableMaster          TransactionFlag = 0x00000004
+	TxSetAccountTxnID           TransactionFlag = 0x00000005
+	TxNoFreeze                  TransactionFlag = 0x00000006
+	TxGlobalFreeze              TransactionFlag = 0x00000007
+	TxDefaultRipple             TransactionFlag = 0x00000008
+	TxSetDepositAuth            TransactionFlag = 0x00000009
+	TxSetAllowTrustLineClawback TransactionFlag = 0x00000010
+	TxRequireDestTag            TransactionFlag = 0x00010000
+	TxOptionalDestTag           TransactionFlag = 0x00020000
+	TxRequireAuth               TransactionFlag = 0x00040000
+	TxOptionalAuth              TransactionFlag = 0x00080000
+	TxDisallowXRP               TransactionFlag = 0x00100000
+	TxAllowXRP                  TransactionFlag = 0x00200000
 
 	// OfferCreate flags
 	TxPassive           TransactionFlag = 0x00010000
@@ -54,19 +56,30 @@ const (
 	// PaymentChannelClaim flags
 	TxRenew TransactionFlag = 0x00010000
 	TxClose TransactionFlag = 0x00020000
+
+	// NFTokenMint flags
+	TxBurnable     TransactionFlag = 0x00000001
+	TxOnlyXRP      TransactionFlag = 0x00000002
+	TxTrustLine    TransactionFlag = 0x00000004
+	TxTransferable TransactionFlag = 0x00000008
+
+	// NFTokenCreateOffer flags
+	TxSellNFToken TransactionFlag = 0x00000001
 )
 
 // Ledger entry flags
 const (
 	// AccountRoot flags
-	LsPasswordSpent  LedgerEntryFlag = 0x00010000
-	LsRequireDestTag LedgerEntryFlag = 0x00020000
-	LsRequireAuth    LedgerEntryFlag = 0x00040000
-	LsDisallowXRP    LedgerEntryFlag = 0x00080000
-	LsDisableMaster  LedgerEntryFlag = 0x00100000
-	LsNoFreeze       LedgerEntryFlag = 0x00200000
-	LsGlobalFreeze   LedgerEntryFlag = 0x00400000
-	LsDefaultRipple  LedgerEntryFlag = 0x00800000
+	LsPasswordSpent          LedgerEntryFlag = 0x00010000
+	LsRequireDestTag         LedgerEntryFlag = 0x00020000
+	LsRequireAuth            LedgerEntryFlag = 0x00040000
+	LsDisallowXRP            LedgerEntryFlag = 0x00080000
+	LsDisableMaster          LedgerEntryFlag = 0x00100000
+	LsNoFreeze               LedgerEntryFlag = 0x00200000
+	LsGlobalFreeze           LedgerEntryFlag = 0x00400000
+	LsDefaultRipple          LedgerEntryFlag = 0x00800000
+	LsDepositAuth            LedgerEntryFlag = 0x01000000
+	LsAllowTrustLineClawback LedgerEntryFlag = 0x80000000
 
 	// Offer flags
 	LsPassive LedgerEntryFlag = 0x00010000
@@ -81,6 +94,9 @@ const (
 	LsHighNoRipple LedgerEntryFlag = 0x00200000
 	LsLowFreeze    LedgerEntryFlag = 0x00400000
 	LsHighFreeze   LedgerEntryFlag = 0x00800000
+
+	// NFTokenOffer flags
+	LsSellNFToken LedgerEntryFlag = 0x00000001
 )
 
 var txFlagNames = map[TransactionType][]struct {
@@ -119,6 +135,15 @@ var txFlagNames = map[TransactionType][]struct {
 		{TxSetFreeze, "SetFreeze"},
 		{TxClearFreeze, "ClearFreeze"},
 	},
+	NFTOKEN_MINT: {
+		{TxBurnable, "Burnable"},
+		{TxOnlyXRP, "OnlyXRP"},
+		{TxTrustLine, "TrustLine"},
+		{TxTransferable, "Transferable"},
+	},
+	NFTOKEN_CREATE_OFFER: {
+		{TxSellNFToken, "SellNFToken"},
+	},
 }
 
 var leFlagNames = map[LedgerEntryType][]struct {
@@ -132,11 +157,16 @@ var leFlagNames = map[LedgerEntryType][]struct {
 		{LsDisallowXRP, "DisallowXRP"},
 		{LsDisableMaster, "DisableMaster"},
 		{LsNoFreeze, "NoFreeze"},
+		{LsDepositAuth, "DepositAuth"},
+		{LsAllowTrustLineClawback, "AllowTrustLineClawback"},
 	},
 	OFFER: {
 		{LsPassive, "Passive"},
 		{LsSell, "Sell"},
 	},
+	NFTOKEN_OFFER: {
+		{LsSellNFToken, "SellNFToken"},
+	},
 	RIPPLE_STATE: {
 		{LsLowReserve, "LowReserve"},
 		{LsHighReserve, "HighReserve"},
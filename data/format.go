@@ -46,10 +46,13 @@ const (
 	NS_SKIP_LIST       LedgerNamespace = 's'
 	NS_AMENDMENT       LedgerNamespace = 'f'
 	NS_FEE             LedgerNamespace = 'e'
-	NS_SUSPAY          LedgerNamespace = 'u'
+	NS_SUSPAY          LedgerNamespace = 'u' // Escrow, formerly Suspended Payment
 	NS_TICKET          LedgerNamespace = 'T'
 	NS_SIGNER_LIST     LedgerNamespace = 'S'
 	NS_XRPU_CHANNEL    LedgerNamespace = 'x'
+	NS_NEGATIVE_UNL    LedgerNamespace = 'N'
+	NS_CHECK           LedgerNamespace = 'C'
+	NS_DEPOSIT_PREAUTH LedgerNamespace = 'p'
 )
 
 var nodeTypes = [...]string{
@@ -85,6 +88,8 @@ const (
 	ST_HASH160   uint8 = 17
 	ST_PATHSET   uint8 = 18
 	ST_VECTOR256 uint8 = 19
+	ST_ISSUE     uint8 = 24
+	ST_CURRENCY  uint8 = 26
 )
 
 // See rippled's SField.cpp for the strings and corresponding encoding values.
@@ -93,7 +98,9 @@ var encodings = map[enc]string{
 	enc{ST_UINT16, 1}: "LedgerEntryType",
 	enc{ST_UINT16, 2}: "TransactionType",
 	enc{ST_UINT16, 3}: "SignerWeight",
+	enc{ST_UINT16, 4}: "TransferFee",
 	// 32-bit unsigned integers (common)
+	enc{ST_UINT32, 1}:  "NetworkID",
 	enc{ST_UINT32, 2}:  "Flags",
 	enc{ST_UINT32, 3}:  "SourceTag",
 	enc{ST_UINT32, 4}:  "Sequence",
@@ -107,6 +114,7 @@ var encodings = map[enc]string{
 	enc{ST_UINT32, 12}: "WalletSize",
 	enc{ST_UINT32, 13}: "OwnerCount",
 	enc{ST_UINT32, 14}: "DestinationTag",
+	enc{ST_UINT32, 15}: "LastUpdateTime",
 	// 32-bit unsigned integers (uncommon)
 	enc{ST_UINT32, 16}: "HighQualityIn",
 	enc{ST_UINT32, 17}: "HighQualityOut",
@@ -132,27 +140,36 @@ var encodings = map[enc]string{
 	enc{ST_UINT32, 37}: "FinishAfter",
 	enc{ST_UINT32, 38}: "SignerListID",
 	enc{ST_UINT32, 39}: "SettleDelay",
+	enc{ST_UINT32, 40}: "TicketCount",
+	enc{ST_UINT32, 41}: "TicketSequence",
+	enc{ST_UINT32, 42}: "NFTokenTaxon",
+	enc{ST_UINT32, 51}: "OracleDocumentID",
 	// 64-bit unsigned integers (common)
-	enc{ST_UINT64, 1}: "IndexNext",
-	enc{ST_UINT64, 2}: "IndexPrevious",
-	enc{ST_UINT64, 3}: "BookNode",
-	enc{ST_UINT64, 4}: "OwnerNode",
-	enc{ST_UINT64, 5}: "BaseFee",
-	enc{ST_UINT64, 6}: "ExchangeRate",
-	enc{ST_UINT64, 7}: "LowNode",
-	enc{ST_UINT64, 8}: "HighNode",
+	enc{ST_UINT64, 1}:  "IndexNext",
+	enc{ST_UINT64, 2}:  "IndexPrevious",
+	enc{ST_UINT64, 3}:  "BookNode",
+	enc{ST_UINT64, 4}:  "OwnerNode",
+	enc{ST_UINT64, 5}:  "BaseFee",
+	enc{ST_UINT64, 6}:  "ExchangeRate",
+	enc{ST_UINT64, 7}:  "LowNode",
+	enc{ST_UINT64, 8}:  "HighNode",
+	enc{ST_UINT64, 9}:  "DestinationNode",
+	enc{ST_UINT64, 12}: "NFTokenOfferNode",
+	// 64-bit unsigned integers (uncommon)
+	enc{ST_UINT64, 23}: "AssetPrice",
 	// 128-bit (common)
 	enc{ST_HASH128, 1}: "EmailHash",
 	// 256-bit (common)
-	enc{ST_HASH256, 1}: "LedgerHash",
-	enc{ST_HASH256, 2}: "ParentHash",
-	enc{ST_HASH256, 3}: "TransactionHash",
-	enc{ST_HASH256, 4}: "AccountHash",
-	enc{ST_HASH256, 5}: "PreviousTxnID",
-	enc{ST_HASH256, 6}: "LedgerIndex",
-	enc{ST_HASH256, 7}: "WalletLocator",
-	enc{ST_HASH256, 8}: "RootIndex",
-	enc{ST_HASH256, 9}: "AccountTxnID",
+	enc{ST_HASH256, 1}:  "LedgerHash",
+	enc{ST_HASH256, 2}:  "ParentHash",
+	enc{ST_HASH256, 3}:  "TransactionHash",
+	enc{ST_HASH256, 4}:  "AccountHash",
+	enc{ST_HASH256, 5}:  "PreviousTxnID",
+	enc{ST_HASH256, 6}:  "LedgerIndex",
+	enc{ST_HASH256, 7}:  "WalletLocator",
+	enc{ST_HASH256, 8}:  "RootIndex",
+	enc{ST_HASH256, 9}:  "AccountTxnID",
+	enc{ST_HASH256, 10}: "NFTokenID",
 	// 256-bit (uncommon)
 	enc{ST_HASH256, 16}: "BookDirectory",
 	enc{ST_HASH256, 17}: "InvoiceID",
@@ -162,6 +179,10 @@ var encodings = map[enc]string{
 	enc{ST_HASH256, 21}: "Digest",
 	enc{ST_HASH256, 22}: "Channel",
 	enc{ST_HASH256, 24}: "CheckID",
+	enc{ST_HASH256, 26}: "PreviousPageMin",
+	enc{ST_HASH256, 27}: "NextPageMin",
+	enc{ST_HASH256, 28}: "NFTokenBuyOffer",
+	enc{ST_HASH256, 29}: "NFTokenSellOffer",
 	// currency amount (common)
 	enc{ST_AMOUNT, 1}:  "Amount",
 	enc{ST_AMOUNT, 2}:  "Balance",
@@ -173,16 +194,19 @@ var encodings = map[enc]string{
 	enc{ST_AMOUNT, 8}:  "Fee",
 	enc{ST_AMOUNT, 9}:  "SendMax",
 	enc{ST_AMOUNT, 10}: "DeliverMin",
+	enc{ST_AMOUNT, 12}: "BidMin",
+	enc{ST_AMOUNT, 13}: "BidMax",
 	// currency amount (uncommon)
 	enc{ST_AMOUNT, 16}: "MinimumOffer",
 	enc{ST_AMOUNT, 17}: "RippleEscrow",
 	enc{ST_AMOUNT, 18}: "DeliveredAmount",
+	enc{ST_AMOUNT, 19}: "NFTokenBrokerFee",
 	// variable length (common)
 	enc{ST_VL, 1}:  "PublicKey",
 	enc{ST_VL, 2}:  "MessageKey",
 	enc{ST_VL, 3}:  "SigningPubKey",
 	enc{ST_VL, 4}:  "TxnSignature",
-	enc{ST_VL, 5}:  "Generator",
+	enc{ST_VL, 5}:  "URI",
 	enc{ST_VL, 6}:  "Signature",
 	enc{ST_VL, 7}:  "Domain",
 	enc{ST_VL, 8}:  "FundCode",
@@ -196,11 +220,15 @@ var encodings = map[enc]string{
 	enc{ST_VL, 16}: "Fulfillment",
 	enc{ST_VL, 17}: "Condition",
 	enc{ST_VL, 18}: "MasterSignature",
+	enc{ST_VL, 28}: "AssetClass",
+	enc{ST_VL, 29}: "Provider",
 	// account
 	enc{ST_ACCOUNT, 1}: "Account",
 	enc{ST_ACCOUNT, 2}: "Owner",
 	enc{ST_ACCOUNT, 3}: "Destination",
 	enc{ST_ACCOUNT, 4}: "Issuer",
+	enc{ST_ACCOUNT, 5}: "Authorize",
+	enc{ST_ACCOUNT, 6}: "Unauthorize",
 	enc{ST_ACCOUNT, 7}: "Target",
 	enc{ST_ACCOUNT, 8}: "RegularKey",
 	// inner object
@@ -215,25 +243,32 @@ var encodings = map[enc]string{
 	enc{ST_OBJECT, 9}:  "TemplateEntry",
 	enc{ST_OBJECT, 10}: "Memo",
 	enc{ST_OBJECT, 11}: "SignerEntry",
+	enc{ST_OBJECT, 12}: "NFToken",
 	// inner object (uncommon)
 	enc{ST_OBJECT, 16}: "Signer",
 	enc{ST_OBJECT, 18}: "Majority",
+	enc{ST_OBJECT, 27}: "AuthAccount",
+	enc{ST_OBJECT, 32}: "PriceData",
 	// array of objects
-	enc{ST_ARRAY, 1}: "EndOfArray",
-	enc{ST_ARRAY, 2}: "SigningAccounts",
-	enc{ST_ARRAY, 3}: "Signers",
-	enc{ST_ARRAY, 4}: "SignerEntries",
-	enc{ST_ARRAY, 5}: "Template",
-	enc{ST_ARRAY, 6}: "Necessary",
-	enc{ST_ARRAY, 7}: "Sufficient",
-	enc{ST_ARRAY, 8}: "AffectedNodes",
-	enc{ST_ARRAY, 9}: "Memos",
+	enc{ST_ARRAY, 1}:  "EndOfArray",
+	enc{ST_ARRAY, 2}:  "SigningAccounts",
+	enc{ST_ARRAY, 3}:  "Signers",
+	enc{ST_ARRAY, 4}:  "SignerEntries",
+	enc{ST_ARRAY, 5}:  "Template",
+	enc{ST_ARRAY, 6}:  "Necessary",
+	enc{ST_ARRAY, 7}:  "Sufficient",
+	enc{ST_ARRAY, 8}:  "AffectedNodes",
+	enc{ST_ARRAY, 9}:  "Memos",
+	enc{ST_ARRAY, 10}: "NFTokens",
 	// array of objects (uncommon)
 	enc{ST_ARRAY, 16}: "Majorities",
+	enc{ST_ARRAY, 24}: "PriceDataSeries",
+	enc{ST_ARRAY, 25}: "AuthAccounts",
 	// 8-bit unsigned integers (common)
 	enc{ST_UINT8, 1}: "CloseResolution",
 	enc{ST_UINT8, 2}: "Method",
 	enc{ST_UINT8, 3}: "TransactionResult",
+	enc{ST_UINT8, 4}: "Scale",
 	// 8-bit unsigned integers (uncommon)
 	enc{ST_UINT8, 16}: "TickSize",
 	// 160-bit (common)
@@ -247,6 +282,13 @@ var encodings = map[enc]string{
 	enc{ST_VECTOR256, 1}: "Indexes",
 	enc{ST_VECTOR256, 2}: "Hashes",
 	enc{ST_VECTOR256, 3}: "Amendments",
+	enc{ST_VECTOR256, 4}: "NFTokenOffers",
+	// issue
+	enc{ST_ISSUE, 3}: "Asset",
+	enc{ST_ISSUE, 4}: "Asset2",
+	// currency
+	enc{ST_CURRENCY, 1}: "BaseAsset",
+	enc{ST_CURRENCY, 2}: "QuoteAsset",
 }
 
 var reverseEncodings map[string]enc
@@ -4,27 +4,22 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/atticlab/ripple/crypto"
 )
 
+// KeyType is crypto.KeyType, which it converts to, with text marshalling
 type KeyType int
 
 const (
-	ECDSA   KeyType = 0
-	Ed25519 KeyType = 1
+	ECDSA   = KeyType(crypto.ECDSA)
+	Ed25519 = KeyType(crypto.Ed25519)
 )
 
 func (keyType KeyType) String() string {
-	switch keyType {
-	case ECDSA:
-		return "ECDSA"
-	case Ed25519:
-		return "Ed25519"
-	default:
-		return "unknown key type"
-	}
+	return crypto.KeyType(keyType).String()
 }
 
 type Hash128 [16]byte
@@ -35,7 +30,7 @@ type VariableLength []byte
 type PublicKey [33]byte
 type Account [20]byte
 type RegularKey [20]byte
-type Seed [16]byte
+type Seed [16]byte // crypto.Seed, which derives its keys
 
 var zero256 Hash256
 var zeroAccount Account
@@ -121,6 +116,48 @@ func (h Hash256) Compare(x Hash256) int {
 	return bytes.Compare(h[:], x[:])
 }
 
+// Less orders hashes by their bytes, which is also the order of the
+// nibbles used to choose inner node children in a ledger tree
+func (h Hash256) Less(x Hash256) bool {
+	return h.Compare(x) < 0
+}
+
+// Hash256Set holds distinct hashes in ascending order.
+// The zero value is an empty set.
+type Hash256Set struct {
+	hashes []Hash256
+}
+
+func (s *Hash256Set) search(h Hash256) int {
+	return sort.Search(len(s.hashes), func(i int) bool { return !s.hashes[i].Less(h) })
+}
+
+// Add inserts h and returns false if it was already present
+func (s *Hash256Set) Add(h Hash256) bool {
+	i := s.search(h)
+	if i < len(s.hashes) && s.hashes[i] == h {
+		return false
+	}
+	s.hashes = append(s.hashes, Hash256{})
+	copy(s.hashes[i+1:], s.hashes[i:])
+	s.hashes[i] = h
+	return true
+}
+
+func (s *Hash256Set) Contains(h Hash256) bool {
+	i := s.search(h)
+	return i < len(s.hashes) && s.hashes[i] == h
+}
+
+func (s *Hash256Set) Len() int {
+	return len(s.hashes)
+}
+
+// Sorted returns a copy of the hashes in ascending order
+func (s *Hash256Set) Sorted() []Hash256 {
+	return append([]Hash256(nil), s.hashes...)
+}
+
 func (h *Hash256) Bytes() []byte {
 	if h == nil {
 		return nil
@@ -176,6 +213,26 @@ func (p PublicKey) IsZero() bool {
 	return p == zeroPublicKey
 }
 
+// KeyType returns the signing algorithm of the key, from its prefix byte:
+// 0x02 or 0x03 for a compressed secp256k1 key and 0xED for Ed25519
+func (p PublicKey) KeyType() (KeyType, bool) {
+	switch p[0] {
+	case 0x02, 0x03:
+		return ECDSA, true
+	case 0xED:
+		return Ed25519, true
+	default:
+		return 0, false
+	}
+}
+
+// Returns the account id the public key signs for as a master key
+func (p PublicKey) AccountId() Account {
+	var account Account
+	copy(account[:], crypto.Sha256RipeMD160(p[:]))
+	return account
+}
+
 func (p *PublicKey) Bytes() []byte {
 	if p != nil {
 		return p[:]
@@ -198,12 +255,28 @@ func (a Account) Hash() (crypto.Hash, error) {
 	return crypto.NewAccountId(a[:])
 }
 
+// Expects an X-address, returns the account, destination tag and whether
+// the address is for the test network
+func NewAccountFromXAddress(s string) (*Account, *uint32, bool, error) {
+	id, tag, test, err := crypto.DecodeXAddress(s)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	account := Account(id)
+	return &account, tag, test, nil
+}
+
+// Returns the X-address for the account with an optional destination tag
+func (a Account) XAddress(tag *uint32, test bool) (string, error) {
+	return crypto.EncodeXAddress(a, tag, test)
+}
+
 func (a Account) String() string {
-	address, err := a.Hash()
+	address, err := a.address()
 	if err != nil {
 		return fmt.Sprintf("Bad Address: %s", b2h(a[:]))
 	}
-	return address.String()
+	return address
 }
 
 func (a Account) IsZero() bool {
@@ -267,13 +340,11 @@ func (r *RegularKey) Bytes() []byte {
 
 // Expects address in base58 form
 func NewSeedFromAddress(s string) (*Seed, error) {
-	hash, err := crypto.NewRippleHashCheck(s, crypto.RIPPLE_FAMILY_SEED)
+	seed, err := crypto.NewSeed(s)
 	if err != nil {
 		return nil, err
 	}
-	var seed Seed
-	copy(seed[:], hash.Payload())
-	return &seed, nil
+	return (*Seed)(seed), nil
 }
 
 func (s Seed) Hash() (crypto.Hash, error) {
@@ -295,21 +366,13 @@ func (s *Seed) Bytes() []byte {
 	return []byte(nil)
 }
 
+// Key returns the root key of the seed, as derived by crypto.Seed.DeriveKeyPair
 func (s *Seed) Key(keyType KeyType) crypto.Key {
-	var (
-		key crypto.Key
-		err error
-	)
-	switch keyType {
-	case Ed25519:
-		key, err = crypto.NewEd25519Key(s[:])
-	case ECDSA:
-		key, err = crypto.NewECDSAKey(s[:])
-	}
+	keyPair, err := crypto.Seed(*s).DeriveKeyPair(crypto.KeyType(keyType), 0)
 	if err != nil {
 		panic(fmt.Sprintf("bad seed: %v", err))
 	}
-	return key
+	return keyPair.Key
 }
 
 func (s *Seed) AccountId(keyType KeyType, sequence *uint32) Account {
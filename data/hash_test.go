@@ -3,6 +3,8 @@ package data
 import (
 	"encoding/hex"
 	"testing"
+
+	. "gopkg.in/check.v1"
 )
 
 var txHashTests = []struct {
@@ -39,3 +41,82 @@ func decodeHex(s string, t *testing.T) []byte {
 // 		t.Log(tx)
 // 	}
 // }
+
+type AccountSuite struct{}
+
+var _ = Suite(&AccountSuite{})
+
+func (s *AccountSuite) TestXAddress(c *C) {
+	account, err := NewAccountFromAddress("r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59")
+	c.Assert(err, IsNil)
+	tag := uint32(11747)
+	x, err := account.XAddress(&tag, false)
+	c.Assert(err, IsNil)
+	c.Assert(x, Equals, "X7AcgcsBL6XDcUb289X4mJ8djcdyKaLFuhLRuNXPrDeJd9A")
+
+	decoded, decodedTag, test, err := NewAccountFromXAddress(x)
+	c.Assert(err, IsNil)
+	c.Assert(*decoded, Equals, *account)
+	c.Assert(*decodedTag, Equals, tag)
+	c.Assert(test, Equals, false)
+
+	x, err = account.XAddress(nil, true)
+	c.Assert(err, IsNil)
+	_, decodedTag, test, err = NewAccountFromXAddress(x)
+	c.Assert(err, IsNil)
+	c.Assert(decodedTag, IsNil)
+	c.Assert(test, Equals, true)
+}
+
+type Hash256Suite struct{}
+
+var _ = Suite(&Hash256Suite{})
+
+func hash256Check(c *C, s string) Hash256 {
+	h, err := NewHash256(s)
+	c.Assert(err, IsNil)
+	return *h
+}
+
+func (s *Hash256Suite) TestLess(c *C) {
+	low := hash256Check(c, "0FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF")
+	high := hash256Check(c, "1000000000000000000000000000000000000000000000000000000000000000")
+	c.Check(low.Less(high), Equals, true)
+	c.Check(high.Less(low), Equals, false)
+	c.Check(low.Less(low), Equals, false)
+	c.Check(Hash256{}.Less(low), Equals, true)
+}
+
+func (s *Hash256Suite) TestHash256Set(c *C) {
+	hashes := []Hash256{
+		hash256Check(c, "755ACEE97CA43148005F512F1F1DD1C9000D16830E3CA1127CB02D7205C49EDB"),
+		hash256Check(c, "055676FF2BBF796DE0692895D42623E8CAC64DB5AC0A69CC865AC183B7ADECE6"),
+		hash256Check(c, "8C6D0BDC568D30AFC028CF57245C70EF625AD1B7EF86242B2B6E1C3EFAECE731"),
+		hash256Check(c, "755ACEE97CA43148005F512F1F1DD1C9000D16830E3CA1127CB02D7205C49EDA"),
+	}
+	var set Hash256Set
+	c.Check(set.Contains(hashes[0]), Equals, false)
+	c.Check(set.Sorted(), HasLen, 0)
+	for _, h := range hashes {
+		c.Check(set.Add(h), Equals, true)
+	}
+	c.Check(set.Add(hashes[2]), Equals, false)
+	c.Check(set.Len(), Equals, 4)
+	for _, h := range hashes {
+		c.Check(set.Contains(h), Equals, true)
+	}
+	c.Check(set.Contains(Hash256{}), Equals, false)
+
+	sorted := set.Sorted()
+	c.Check(sorted, DeepEquals, []Hash256{hashes[1], hashes[3], hashes[0], hashes[2]})
+	// The result is a copy
+	sorted[0] = Hash256{}
+	c.Check(set.Contains(hashes[1]), Equals, true)
+
+	// The order doesn't depend on the order of insertion
+	var reversed Hash256Set
+	for i := len(hashes) - 1; i >= 0; i-- {
+		reversed.Add(hashes[i])
+	}
+	c.Check(reversed.Sorted(), DeepEquals, set.Sorted())
+}
@@ -33,19 +33,35 @@ func LedgerIndex(le LedgerEntry) (*Hash256, error) {
 		return GetRippleStateIndex(v.LowLimit.Issuer, v.HighLimit.Issuer, v.Balance.Currency)
 	case *Offer:
 		return GetOfferIndex(*v.Account, *v.Sequence)
+	case *Check:
+		return GetCheckIndex(*v.Account, *v.Sequence)
+	case *Ticket:
+		return GetTicketIndex(*v.Account, *v.TicketSequence)
+	case *DepositPreauth:
+		return GetDepositPreauthIndex(*v.Account, *v.Authorize)
 	case *LedgerHashes:
 		return GetLedgerHashIndex()
 	case *Directory:
 		return GetDirectoryNodeIndex(*v.RootIndex, v.IndexPrevious.Next())
 	case *FeeSettings:
-		return buildIndex([]interface{}{NS_FEE})
+		return GetFeeIndex()
 	case *Amendments:
-		return buildIndex([]interface{}{NS_AMENDMENT})
+		return GetAmendmentsIndex()
 	default:
 		return nil, fmt.Errorf("Unknown LedgerEntry")
 	}
 }
 
+// VerifyIndex recomputes the index of le from its type and key fields
+// and reports whether it matches index.
+func VerifyIndex(le LedgerEntry, index Hash256) (bool, error) {
+	computed, err := LedgerIndex(le)
+	if err != nil {
+		return false, err
+	}
+	return *computed == index, nil
+}
+
 func GetAccountRootIndex(account Account) (*Hash256, error) {
 	return buildIndex([]interface{}{NS_ACCOUNT, account.Bytes()})
 }
@@ -54,6 +70,30 @@ func GetOfferIndex(account Account, sequence uint32) (*Hash256, error) {
 	return buildIndex([]interface{}{NS_OFFER, account.Bytes(), sequence})
 }
 
+// GetEscrowIndex returns the index of the escrow created by owner
+// with the EscrowCreate transaction of the given sequence.
+func GetEscrowIndex(owner Account, sequence uint32) (*Hash256, error) {
+	return buildIndex([]interface{}{NS_SUSPAY, owner.Bytes(), sequence})
+}
+
+// GetCheckIndex returns the index of the check created by account
+// with the CheckCreate transaction of the given sequence.
+func GetCheckIndex(account Account, sequence uint32) (*Hash256, error) {
+	return buildIndex([]interface{}{NS_CHECK, account.Bytes(), sequence})
+}
+
+// GetTicketIndex returns the index of the ticket of account which can be
+// used as the given sequence
+func GetTicketIndex(account Account, ticketSequence uint32) (*Hash256, error) {
+	return buildIndex([]interface{}{NS_TICKET, account.Bytes(), ticketSequence})
+}
+
+// GetDepositPreauthIndex returns the index of the preauthorization of
+// payments from authorized to owner
+func GetDepositPreauthIndex(owner, authorized Account) (*Hash256, error) {
+	return buildIndex([]interface{}{NS_DEPOSIT_PREAUTH, owner.Bytes(), authorized.Bytes()})
+}
+
 func GetRippleStateIndex(a, b Account, c Currency) (*Hash256, error) {
 	if bytes.Compare(a.Bytes(), b.Bytes()) < 0 {
 		return buildIndex([]interface{}{NS_RIPPLE_STATE, a.Bytes(), b.Bytes(), c.Bytes()})
@@ -91,6 +131,10 @@ func GetAmendmentsIndex() (*Hash256, error) {
 	return buildIndex([]interface{}{NS_AMENDMENT})
 }
 
+func GetNegativeUNLIndex() (*Hash256, error) {
+	return buildIndex([]interface{}{NS_NEGATIVE_UNL})
+}
+
 func GetLedgerHashIndex() (*Hash256, error) {
 	return buildIndex([]interface{}{NS_SKIP_LIST})
 }
@@ -0,0 +1,59 @@
+package data
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type IndexSuite struct{}
+
+var _ = Suite(&IndexSuite{})
+
+func (s *IndexSuite) TestSingletonIndexes(c *C) {
+	for _, t := range []struct {
+		Name     string
+		Index    func() (*Hash256, error)
+		Expected string
+	}{
+		{"Amendments", GetAmendmentsIndex, "7DB0788C020F02780A673DC74757F23823FA3014C1866E72CC4CD8B226CD6EF4"},
+		{"FeeSettings", GetFeeIndex, "4BC50C9B0D8515D3EAAE1E74B29A95804346C491EE1A95BF25E4AAB854A6A651"},
+		{"NegativeUNL", GetNegativeUNLIndex, "2E8A59AA9D3B5B186B0B9E0F62E6C02587CA74A4D778938E957B6357D364B244"},
+		{"LedgerHashes", GetLedgerHashIndex, "B4979A36CDC7F3D3D5C31A4EAE2AC7D7209DDA877588B9AFC66799692AB0D66B"},
+	} {
+		index, err := t.Index()
+		c.Assert(err, IsNil)
+		c.Check(index.String(), Equals, t.Expected, Commentf(t.Name))
+	}
+
+	amendments, err := LedgerIndex(LedgerEntryFactory[AMENDMENTS]())
+	c.Assert(err, IsNil)
+	c.Check(amendments.String(), Equals, "7DB0788C020F02780A673DC74757F23823FA3014C1866E72CC4CD8B226CD6EF4")
+	fees, err := LedgerIndex(LedgerEntryFactory[FEE_SETTINGS]())
+	c.Assert(err, IsNil)
+	c.Check(fees.String(), Equals, "4BC50C9B0D8515D3EAAE1E74B29A95804346C491EE1A95BF25E4AAB854A6A651")
+}
+
+func (s *IndexSuite) TestEscrowIndex(c *C) {
+	owner, err := NewAccountFromAddress("rDx69ebzbowuqztksVDmZXjizTd12BVr4x")
+	c.Assert(err, IsNil)
+	index, err := GetEscrowIndex(*owner, 84)
+	c.Assert(err, IsNil)
+	c.Check(index.String(), Equals, "61E8E8ED53FA2CEBE192B23897071E9A75217BF5A410E9CB5B45AAB7AECA567A")
+}
+
+func (s *IndexSuite) TestCheckIndex(c *C) {
+	le, err := DecodeLedgerEntry([]byte(`{"Account":"rUn84CUYbNjRoTQ6mSW7BVJPSVJNLb1QLo","Destination":"rfkE1aSy9G8Upk4JssnwBxhEv5p4mn2KTy","DestinationNode":"0000000000000000","DestinationTag":1,"Expiration":570113521,"Flags":0,"InvoiceID":"46060241FABCF692D4D934BA2A6C4427CD4279083E38C77CBE642243E43BE291","LedgerEntryType":"Check","OwnerNode":"0000000000000000","PreviousTxnID":"5463C6E08862A1FAE5EDAC12D70ADB16546A1F674930521295BC082494B62924","PreviousTxnLgrSeq":6,"SendMax":"100000000","Sequence":2,"index":"49647F0D748DC3FE26BDACBC57F251AADEFFF391403EC9BF87C97F67E9977FB0"}`))
+	c.Assert(err, IsNil)
+	ok, err := VerifyIndex(le, *le.(*Check).LedgerIndex)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+}
+
+func (s *IndexSuite) TestDepositPreauthIndex(c *C) {
+	le, err := DecodeLedgerEntry([]byte(`{"LedgerEntryType":"DepositPreauth","Account":"rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8","Authorize":"rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de","Flags":0,"OwnerNode":"0000000000000000","PreviousTxnID":"3E8964D5A86B3CD6B9ECB33310D4E073D64C865A5B866200AD2B7E29F8326702","PreviousTxnLgrSeq":7,"index":"4A255038CC3ADCC1A9C91509279B59908251728D0DAADB248FFE297D0F7E068C"}`))
+	c.Assert(err, IsNil)
+	preauth := le.(*DepositPreauth)
+	ok, err := VerifyIndex(le, *preauth.LedgerIndex)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(preauth.Affects(*preauth.Authorize), Equals, true)
+}
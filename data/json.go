@@ -7,9 +7,13 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type ledgerJSON Ledger
@@ -42,118 +46,622 @@ func (l *Ledger) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// Wrapper types to enable second level of marshalling
-// when found in tx API call
-type txmNormal TransactionWithMetaData
-
-var (
-	txmSplitTypeRegex       = regexp.MustCompile(`"tx":`)
-	txmMetaDataRegex        = regexp.MustCompile(`"metaData":`)
-	txmTransactionTypeRegex = regexp.MustCompile(`"TransactionType"\s*:\s*"(\w+)"`)
-)
+// txmEnvelope is the first pass over a transaction with metadata, finding
+// the parts which are not fields of the transaction. rippled has the forms:
+// tx:         {...fields..., "meta":{...}, "date":..., "ledger_index":...}
+// account_tx: {"tx":{...fields...}, "meta":{...}, "validated":true}
+// binary:     {"tx_blob":"...", "meta":"...", "ledger_index":...}
+// ledger:     {...fields..., "metaData":{...}}
+type txmEnvelope struct {
+	Tx              json.RawMessage `json:"tx"`
+	TxBlob          *string         `json:"tx_blob"`
+	TransactionType *string
+	Meta            json.RawMessage `json:"meta"`
+	MetaData        json.RawMessage `json:"metaData"`
+	Date            *RippleTime     `json:"date"`
+	LedgerIndex     *uint32         `json:"ledger_index"`
+}
 
-// This function is a horrow show, demonstrating the huge
-// inconsistencies in the presentation of a transaction
-// by the rippled API.  Indeed.
 func (txm *TransactionWithMetaData) UnmarshalJSON(b []byte) error {
-	if txmSplitTypeRegex.Match(b) {
-		// Transaction has the form {"tx":{}, "meta":{}, "validated": true}
-		// i.e. returned from `account_tx` command.
-		var split struct {
-			Tx   json.RawMessage
-			Meta json.RawMessage
-		}
-		if err := json.Unmarshal(b, &split); err != nil {
+	return txm.unmarshalJSON(b, nil)
+}
+
+// d may be nil, in which case nothing is pooled
+func (txm *TransactionWithMetaData) unmarshalJSON(b []byte, d *TransactionDecoder) error {
+	var envelope txmEnvelope
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return err
+	}
+	switch {
+	case envelope.Tx != nil:
+		if err := txm.unmarshalJSON(envelope.Tx, d); err != nil {
 			return err
 		}
-		if err := json.Unmarshal(split.Tx, txm); err != nil {
+	case envelope.TxBlob != nil:
+		if err := txm.decodeTxBlob(*envelope.TxBlob); err != nil {
+			return err
+		}
+	case envelope.TransactionType == nil:
+		return fmt.Errorf("Not a valid transaction with metadata: Missing TransactionType")
+	default:
+		// The second pass decodes the fields into the transaction's own type
+		txm.Transaction = d.newTransaction(*envelope.TransactionType)
+		if err := json.Unmarshal(b, txm.Transaction); err != nil {
+			return err
+		}
+		if d != nil && d.strict {
+			if err := checkUnknownFields(b, txm.Transaction); err != nil {
+				return err
+			}
+		}
+	}
+	meta := envelope.Meta
+	if meta == nil {
+		meta = envelope.MetaData
+	}
+	if meta != nil {
+		if err := txm.unmarshalMetaData(meta); err != nil {
 			return err
 		}
-		return json.Unmarshal(split.Meta, &txm.MetaData)
 	}
+	if envelope.Date != nil {
+		txm.Date = *envelope.Date
+	}
+	if envelope.LedgerIndex != nil {
+		txm.LedgerSequence = *envelope.LedgerIndex
+	}
+	return nil
+}
 
-	// Sniff the transaction type, and allocate the appropriate type
-	txTypeMatch := txmTransactionTypeRegex.FindStringSubmatch(string(b))
-	if txTypeMatch == nil {
-		return fmt.Errorf("Not a valid transaction with metadata: Missing TransactionType")
+// unmarshalMetaData accepts metadata as JSON or, in binary responses,
+// as a hex string
+func (txm *TransactionWithMetaData) unmarshalMetaData(b json.RawMessage) error {
+	if len(b) == 0 || b[0] != '"' {
+		return json.Unmarshal(b, &txm.MetaData)
 	}
-	txType := txTypeMatch[1]
-	txm.Transaction = GetTxFactoryByType(txType)()
-	if err := json.Unmarshal(b, txm.Transaction); err != nil {
+	var hexMeta string
+	if err := json.Unmarshal(b, &hexMeta); err != nil {
 		return err
 	}
+	raw, err := hex.DecodeString(hexMeta)
+	if err != nil {
+		return fmt.Errorf("Bad meta: %s", err)
+	}
+	m := reflect.ValueOf(&txm.MetaData)
+	return readObject(bytes.NewReader(raw), &m)
+}
+
+// transactionTypeName returns the TransactionType of a transaction with
+// metadata in any of the forms accepted by UnmarshalJSON except binary
+func transactionTypeName(b []byte) (string, error) {
+	var sniff struct {
+		TransactionType *string
+		Tx              struct {
+			TransactionType *string
+		} `json:"tx"`
+	}
+	if err := json.Unmarshal(b, &sniff); err != nil {
+		return "", err
+	}
+	switch {
+	case sniff.TransactionType != nil:
+		return *sniff.TransactionType, nil
+	case sniff.Tx.TransactionType != nil:
+		return *sniff.Tx.TransactionType, nil
+	default:
+		return "", fmt.Errorf("Not a valid transaction with metadata: Missing TransactionType")
+	}
+}
+
+// TransactionDecoder is an opt-in alternative to TransactionWithMetaData.UnmarshalJSON
+// for hot paths. Transactions handed back with Release are reset and reused by later
+// decodes of the same type. It is safe for concurrent use.
+type TransactionDecoder struct {
+	transactions [len(TxFactory)]sync.Pool
+	strict       bool
+}
+
+func NewTransactionDecoder() *TransactionDecoder {
+	return &TransactionDecoder{}
+}
+
+// DisallowUnknownFields makes Decode return an error when a transaction has
+// fields which its type does not have, such as those of a newer protocol.
+// It must be called before the decoder is used.
+func (d *TransactionDecoder) DisallowUnknownFields() {
+	d.strict = true
+}
 
-	if txmMetaDataRegex.Match(b) {
-		// Transaction has the form {...fields..., "metaData":{...}}
-		// (no "validated" or ledger sequence or id)
-		// i.e. it comes from `ledger` command.
-		// Further, "metaData" for payments has "DeliveredAmount" instead of the expected "delivered_amount", so clean that up first.
-		b = bytes.Replace(b, []byte("\"DeliveredAmount\":"), []byte("\"delivered_amount\":"), 1)
+// Decode unmarshals b into txm in the same way as UnmarshalJSON
+func (d *TransactionDecoder) Decode(b []byte, txm *TransactionWithMetaData) error {
+	return txm.unmarshalJSON(b, d)
+}
 
-		// Parse the rest in one shot
-		extract := &struct {
-			*txmNormal
-			MetaData *MetaData `json:"metaData"`
-		}{
-			txmNormal: (*txmNormal)(txm),
-			MetaData:  &txm.MetaData,
+// Release makes the transaction of txm available to later decodes.
+// Neither it nor anything it references may be used afterwards.
+func (d *TransactionDecoder) Release(txm *TransactionWithMetaData) {
+	if txm.Transaction == nil {
+		return
+	}
+	if txType := txm.GetTransactionType(); int(txType) < len(d.transactions) {
+		d.transactions[txType].Put(txm.Transaction)
+	}
+	txm.Transaction = nil
+}
+
+// DecodeEach decodes a JSON array of transactions from r, calling fn with
+// each one whose type is among types, or every one if types is empty.
+// Other transactions are skipped after sniffing their TransactionType,
+// so no transaction is allocated for them. The transaction passed to fn
+// is released when fn returns and must not be retained.
+func (d *TransactionDecoder) DecodeEach(r io.Reader, fn func(*TransactionWithMetaData) error, types ...TransactionType) error {
+	var wanted [len(TxFactory)]bool
+	for _, txType := range types {
+		if int(txType) < len(wanted) {
+			wanted[txType] = true
+		}
+	}
+	dec := json.NewDecoder(r)
+	if token, err := dec.Token(); err != nil {
+		return err
+	} else if token != json.Delim('[') {
+		return fmt.Errorf("Expected an array of transactions: %v", token)
+	}
+	var raw json.RawMessage
+	for dec.More() {
+		raw = raw[:0]
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		if len(types) > 0 {
+			name, err := transactionTypeName(raw)
+			if err != nil {
+				return err
+			}
+			if txType, ok := txTypes[name]; !ok || !wanted[txType] {
+				continue
+			}
+		}
+		var txm TransactionWithMetaData
+		if err := d.Decode(raw, &txm); err != nil {
+			return err
+		}
+		err := fn(&txm)
+		d.Release(&txm)
+		if err != nil {
+			return err
 		}
-		return json.Unmarshal(b, extract)
 	}
+	_, err := dec.Token()
+	return err
+}
 
-	// Transaction has the form {...fields..., "metaData":{...}}
-	// i.e. it comes from `tx` command.
-	extract := &struct {
-		*txmNormal
-		Date     *RippleTime
-		MetaData *MetaData `json:"metaData"`
-	}{
-		txmNormal: (*txmNormal)(txm),
-		Date:      &txm.Date,
-		MetaData:  &txm.MetaData,
+func (d *TransactionDecoder) newTransaction(name string) Transaction {
+	txType := txTypes[name]
+	if d != nil {
+		if tx, ok := d.transactions[txType].Get().(Transaction); ok {
+			v := reflect.ValueOf(tx).Elem()
+			v.Set(reflect.Zero(v.Type()))
+			tx.GetBase().TransactionType = txType
+			return tx
+		}
 	}
-	return json.Unmarshal(b, extract)
+	return TxFactory[txType]()
 }
 
-func (txm TransactionWithMetaData) marshalJSON() ([]byte, []byte, error) {
-	tx, err := json.Marshal(txm.Transaction)
+// Returns the transaction fields in struct order followed by the hash, the
+// extras and the metadata keyed by metaKey
+func (txm TransactionWithMetaData) marshalJSON(metaKey string, extras ...jsonField) (jsonFields, error) {
+	fields, err := marshalTransaction(txm.Transaction)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	meta, err := json.Marshal(txm.MetaData)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	return tx, meta, nil
+	hash, err := json.Marshal(txm.GetHash())
+	if err != nil {
+		return nil, err
+	}
+	fields.set("hash", hash)
+	for _, extra := range extras {
+		fields.set(extra.Name, extra.Value)
+	}
+	fields.set(metaKey, meta)
+	return fields, nil
 }
 
-const txmFormat = `%s,"hash":"%s","inLedger":%d,"ledger_index":%d,"meta":%s}`
-
 func (txm TransactionWithMetaData) MarshalJSON() ([]byte, error) {
-	tx, meta, err := txm.marshalJSON()
+	ledger := json.RawMessage(strconv.FormatUint(uint64(txm.LedgerSequence), 10))
+	extras := []jsonField{{"inLedger", ledger}, {"ledger_index", ledger}}
+	if txm.Date.Uint32() != 0 {
+		date, err := json.Marshal(txm.Date)
+		if err != nil {
+			return nil, err
+		}
+		extras = append(extras, jsonField{"date", date})
+	}
+	fields, err := txm.marshalJSON("meta", extras...)
 	if err != nil {
 		return nil, err
 	}
-	out := fmt.Sprintf(txmFormat, string(tx[:len(tx)-1]), txm.GetHash().String(), txm.LedgerSequence, txm.LedgerSequence, string(meta))
-	return []byte(out), nil
+	return json.Marshal(fields)
 }
 
-const txmSliceFormat = `%s,"hash":"%s","metaData":%s}`
-
 func (s TransactionSlice) MarshalJSON() ([]byte, error) {
-	raw := make([]json.RawMessage, len(s))
-	var err error
-	var tx, meta []byte
+	raw := make([]jsonFields, len(s))
 	for i, txm := range s {
-		if tx, meta, err = txm.marshalJSON(); err != nil {
+		fields, err := txm.marshalJSON("metaData")
+		if err != nil {
 			return nil, err
 		}
-		extra := fmt.Sprintf(txmSliceFormat, string(tx[:len(tx)-1]), txm.GetHash().String(), meta)
-		raw[i] = json.RawMessage(extra)
+		raw[i] = fields
 	}
 	return json.Marshal(raw)
 }
 
+// DecodeTransactionsLenient decodes each element of a JSON array of
+// transactions independently, so one bad element, such as one of a type
+// unknown to TxFactory, does not lose the rest. Both slices are parallel to
+// the array: element i is decoded into txs[i] with a nil errs[i], or fails
+// with a nil txs[i] and an error naming its index in errs[i]. If b is not
+// an array the only error is that of decoding it.
+func DecodeTransactionsLenient(b []byte) (txs []*TransactionWithMetaData, errs []error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, []error{err}
+	}
+	txs, errs = make([]*TransactionWithMetaData, len(raw)), make([]error, len(raw))
+	for i, element := range raw {
+		txm := new(TransactionWithMetaData)
+		if err := json.Unmarshal(element, txm); err != nil {
+			errs[i] = fmt.Errorf("Transaction %d: %s", i, err)
+			continue
+		}
+		txs[i] = txm
+	}
+	return txs, errs
+}
+
+// OmitEmptyAccounts marshals a Transaction with all of its empty Account and
+// RegularKey fields removed. encoding/json only omits nil pointers, so a zero
+// value would otherwise be written as the zero account, which rippled reads
+// as a real address. For SetRegularKey an absent RegularKey removes the key.
+// Use it for transactions which are to be submitted. Historical transactions
+// are marshalled as is, since pseudo-transactions carry the zero account.
+type OmitEmptyAccounts struct {
+	Transaction
+}
+
+func (o OmitEmptyAccounts) MarshalJSON() ([]byte, error) {
+	fields, err := marshalOmitEmptyAccounts(o.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+func marshalOmitEmptyAccounts(tx Transaction) (jsonFields, error) {
+	fields, err := marshalTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range emptyAccounts(reflect.Indirect(reflect.ValueOf(tx)), nil) {
+		fields.delete(name)
+	}
+	return fields, nil
+}
+
+// XAddresses marshals a Transaction with its Account and Destination written
+// as X-addresses, into which SourceTag and DestinationTag are folded. Test
+// selects the test network prefix. Empty accounts are removed as with
+// OmitEmptyAccounts. Unmarshalling accepts classic and X-addresses, unfolding
+// the tags, and sets Test from the X-addresses.
+type XAddresses struct {
+	Transaction
+	Test bool
+}
+
+// The account fields of a transaction which may be X-addresses, and their tags
+var xAddressFields = []struct{ Account, Tag string }{
+	{"Account", "SourceTag"},
+	{"Destination", "DestinationTag"},
+}
+
+func (x XAddresses) MarshalJSON() ([]byte, error) {
+	fields, err := marshalOmitEmptyAccounts(x.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range xAddressFields {
+		raw, ok := fields.get(f.Account)
+		if !ok {
+			continue
+		}
+		var account Account
+		if err := json.Unmarshal(raw, &account); err != nil {
+			return nil, err
+		}
+		var tag *uint32
+		if raw, ok := fields.get(f.Tag); ok {
+			tag = new(uint32)
+			if err := json.Unmarshal(raw, tag); err != nil {
+				return nil, err
+			}
+			fields.delete(f.Tag)
+		}
+		address, err := account.XAddress(tag, x.Test)
+		if err != nil {
+			return nil, err
+		}
+		if raw, err = json.Marshal(address); err != nil {
+			return nil, err
+		}
+		fields.set(f.Account, raw)
+	}
+	return json.Marshal(fields)
+}
+
+func (x *XAddresses) UnmarshalJSON(b []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	x.Test = false
+	for _, f := range xAddressFields {
+		var address string
+		if raw, ok := fields[f.Account]; !ok || json.Unmarshal(raw, &address) != nil || !isXAddress(address) {
+			continue
+		}
+		account, tag, test, err := NewAccountFromXAddress(address)
+		if err != nil {
+			return err
+		}
+		x.Test = x.Test || test
+		if tag != nil {
+			if raw, ok := fields[f.Tag]; ok {
+				var explicit uint32
+				if err := json.Unmarshal(raw, &explicit); err != nil {
+					return err
+				}
+				if explicit != *tag {
+					return fmt.Errorf("%s of %d conflicts with the X-address: %s", f.Tag, explicit, address)
+				}
+			}
+			if fields[f.Tag], err = json.Marshal(*tag); err != nil {
+				return err
+			}
+		}
+		if fields[f.Account], err = json.Marshal(account); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	var txm TransactionWithMetaData
+	if err := json.Unmarshal(b, &txm); err != nil {
+		return err
+	}
+	x.Transaction = txm.Transaction
+	return nil
+}
+
+// isXAddress reports whether s is an X-address rather than a classic address,
+// which always begins with r
+func isXAddress(s string) bool {
+	return len(s) > 0 && (s[0] == 'X' || s[0] == 'T')
+}
+
+// Fields found alongside those of a transaction in rippled responses
+var txmExtraFields = map[string]bool{
+	"meta":           true,
+	"metadata":       true,
+	"date":           true,
+	"ledger_index":   true,
+	"ledger_hash":    true,
+	"inledger":       true,
+	"validated":      true,
+	"close_time_iso": true,
+	"ctid":           true,
+}
+
+// checkUnknownFields returns an error naming the keys of b which are
+// not fields of tx. As with encoding/json, keys match case-insensitively.
+func checkUnknownFields(b []byte, tx Transaction) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	known := jsonFieldNames(reflect.TypeOf(tx).Elem(), make(map[string]bool))
+	var unknown []string
+	for name := range fields {
+		if lower := strings.ToLower(name); !known[lower] && !txmExtraFields[lower] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("Unknown %s fields: %s", tx.GetTransactionType(), strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// jsonFieldNames adds the lower case JSON names of the fields of t,
+// including those of embedded structs, to names
+func jsonFieldNames(t reflect.Type, names map[string]bool) map[string]bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			jsonFieldNames(field.Type, names)
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
+
+// jsonFields are the members of a JSON object, which are marshalled in order
+type jsonFields []jsonField
+
+type jsonField struct {
+	Name  string
+	Value json.RawMessage
+}
+
+// readJSONFields returns the members of the JSON object b in the order they appear
+func readJSONFields(b []byte) (jsonFields, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if token, err := dec.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, fmt.Errorf("Expected an object: %v", token)
+	}
+	var fields jsonFields
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, jsonField{token.(string), value})
+	}
+	return fields, nil
+}
+
+func (f jsonFields) get(name string) (json.RawMessage, bool) {
+	for _, field := range f {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// set replaces the value of the named field in place, or appends it
+func (f *jsonFields) set(name string, value json.RawMessage) {
+	for i := range *f {
+		if (*f)[i].Name == name {
+			(*f)[i].Value = value
+			return
+		}
+	}
+	*f = append(*f, jsonField{name, value})
+}
+
+func (f *jsonFields) delete(name string) {
+	for i := range *f {
+		if (*f)[i].Name == name {
+			*f = append((*f)[:i], (*f)[i+1:]...)
+			return
+		}
+	}
+}
+
+func (f jsonFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(field.Value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalTransaction returns the JSON fields of tx in struct order without
+// any optional hashes which are set to zero, as rippled rejects them
+func marshalTransaction(tx Transaction) (jsonFields, error) {
+	b, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := readJSONFields(b)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range zeroOptionalHashes(reflect.Indirect(reflect.ValueOf(tx)), nil) {
+		fields.delete(name)
+	}
+	return fields, nil
+}
+
+var (
+	accountType    = reflect.TypeOf(Account{})
+	regularKeyType = reflect.TypeOf(RegularKey{})
+	hash256PtrType = reflect.TypeOf((*Hash256)(nil))
+)
+
+// zeroOptionalHashes returns the JSON names of the *Hash256 fields of v,
+// including those of embedded structs, which point to a zero hash
+func zeroOptionalHashes(v reflect.Value, names []string) []string {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if field.Anonymous && value.Kind() == reflect.Struct {
+			names = zeroOptionalHashes(value, names)
+			continue
+		}
+		if value.Type() != hash256PtrType || value.IsNil() || !value.CanInterface() || !value.Interface().(*Hash256).IsZero() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// emptyAccounts returns the JSON names of the zero Account and
+// RegularKey fields of v, including those of embedded structs
+func emptyAccounts(v reflect.Value, names []string) []string {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if field.Anonymous && value.Kind() == reflect.Struct {
+			names = emptyAccounts(value, names)
+			continue
+		}
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		if value.Type() != accountType && value.Type() != regularKeyType {
+			continue
+		}
+		if value.Interface() == reflect.Zero(value.Type()).Interface() {
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = field.Name
+			}
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 var (
 	leTypeRegex  = regexp.MustCompile(`"LedgerEntryType"\s*:\s*"(\w+)"`)
 	leIndexRegex = regexp.MustCompile(`"index"\s*:\s*"(\w+)"`)
@@ -182,6 +690,29 @@ func (l *LedgerEntrySlice) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// DecodeLedgerEntry unmarshals a ledger entry of any type from JSON,
+// such as an item of the state of a ledger or an account_objects result
+func DecodeLedgerEntry(b []byte) (LedgerEntry, error) {
+	var typed struct {
+		LedgerEntryType *LedgerEntryType
+	}
+	if err := json.Unmarshal(b, &typed); err != nil {
+		return nil, err
+	}
+	if typed.LedgerEntryType == nil {
+		return nil, fmt.Errorf("Missing LedgerEntryType")
+	}
+	factory, err := GetLedgerEntryFactory(*typed.LedgerEntryType)
+	if err != nil {
+		return nil, err
+	}
+	le := factory()
+	if err := json.Unmarshal(b, le); err != nil {
+		return nil, err
+	}
+	return le, nil
+}
+
 // const leSliceFormat = `%s,"LedgerEntryType":"%s"}`
 
 // func (s LedgerEntrySlice) MarshalJSON() ([]byte, error) {
@@ -197,6 +728,48 @@ func (l *LedgerEntrySlice) UnmarshalJSON(b []byte) error {
 // 	return json.Marshal(raw)
 // }
 
+type metaDataJSON MetaData
+
+var deliveredUnavailable = []byte(`"unavailable"`)
+
+// UnmarshalJSON accepts the delivered amount under either of the keys
+// rippled has used and the "unavailable" placeholder
+func (m *MetaData) UnmarshalJSON(b []byte) error {
+	extract := struct {
+		*metaDataJSON
+		Delivered       json.RawMessage `json:"delivered_amount"`
+		DeliveredAmount json.RawMessage
+	}{metaDataJSON: (*metaDataJSON)(m)}
+	if err := json.Unmarshal(b, &extract); err != nil {
+		return err
+	}
+	delivered := extract.Delivered
+	if delivered == nil {
+		delivered = extract.DeliveredAmount
+	}
+	m.DeliveredAmount, m.deliveredUnavailable = nil, false
+	switch {
+	case delivered == nil || bytes.Equal(delivered, []byte("null")):
+		return nil
+	case bytes.Equal(delivered, deliveredUnavailable):
+		m.deliveredUnavailable = true
+		return nil
+	default:
+		m.DeliveredAmount = new(Amount)
+		return json.Unmarshal(delivered, m.DeliveredAmount)
+	}
+}
+
+func (m MetaData) MarshalJSON() ([]byte, error) {
+	if !m.deliveredUnavailable {
+		return json.Marshal(metaDataJSON(m))
+	}
+	return json.Marshal(struct {
+		metaDataJSON
+		Delivered json.RawMessage `json:"delivered_amount"`
+	}{metaDataJSON(m), deliveredUnavailable})
+}
+
 type affectedNodeJSON struct {
 	LedgerEntryType   LedgerEntryType
 	LedgerIndex       *Hash256
@@ -322,7 +895,7 @@ func (t *RippleTime) UnmarshalJSON(b []byte) error {
 }
 
 func (t rippleHumanTime) MarshalJSON() ([]byte, error) {
-	return []byte(`"` + t.String() + `"`), nil
+	return []byte(`"` + t.Human() + `"`), nil
 }
 
 func (t *rippleHumanTime) UnmarshalJSON(b []byte) error {
@@ -372,6 +945,9 @@ func (a Amount) MarshalJSON() ([]byte, error) {
 	if a.Value == nil {
 		return nil, fmt.Errorf("Value has a nil Value")
 	}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
 	if a.IsNative() {
 		return []byte(`"` + strconv.FormatUint(a.num, 10) + `"`), nil
 	}
@@ -391,6 +967,30 @@ func (a *Amount) UnmarshalJSON(b []byte) (err error) {
 	return nil
 }
 
+type issueJSON struct {
+	Currency Currency `json:"currency"`
+	Issuer   *Account `json:"issuer,omitempty"`
+}
+
+func (i Issue) MarshalJSON() ([]byte, error) {
+	if i.Currency.IsNative() {
+		return json.Marshal(issueJSON{Currency: i.Currency})
+	}
+	return json.Marshal(issueJSON{i.Currency, &i.Issuer})
+}
+
+func (i *Issue) UnmarshalJSON(b []byte) error {
+	var dummy issueJSON
+	if err := json.Unmarshal(b, &dummy); err != nil {
+		return err
+	}
+	i.Currency, i.Issuer = dummy.Currency, Account{}
+	if dummy.Issuer != nil {
+		i.Issuer = *dummy.Issuer
+	}
+	return nil
+}
+
 func (c Currency) MarshalText() ([]byte, error) {
 	return []byte(c.Machine()), nil
 }
@@ -429,11 +1029,11 @@ func (h *Hash256) UnmarshalText(b []byte) error {
 }
 
 func (a Account) MarshalText() ([]byte, error) {
-	address, err := a.Hash()
+	address, err := a.address()
 	if err != nil {
 		return nil, err
 	}
-	return address.MarshalText()
+	return []byte(address), nil
 }
 
 // Expects base58-encoded account id
@@ -500,10 +1100,24 @@ func (p PublicKey) MarshalText() ([]byte, error) {
 	return b2h(p[:]), nil
 }
 
-// Expects public key hex
+// Expects public key hex, or an empty string for multi-signed transactions
 func (p *PublicKey) UnmarshalText(b []byte) error {
-	_, err := hex.Decode(p[:], b)
-	return err
+	if len(b) == 0 {
+		*p = zeroPublicKey
+		return nil
+	}
+	if len(b) != hex.EncodedLen(len(p)) {
+		return fmt.Errorf("Bad PublicKey: %s", b)
+	}
+	var key PublicKey
+	if _, err := hex.Decode(key[:], b); err != nil {
+		return err
+	}
+	if _, ok := key.KeyType(); !ok {
+		return fmt.Errorf("Unknown PublicKey prefix: %02X", key[0])
+	}
+	*p = key
+	return nil
 }
 
 // A uint64 which gets represented as a hex string in json
@@ -1,10 +1,16 @@
 package data
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"path/filepath"
+	"strings"
+	"testing"
 
+	internal "github.com/atticlab/ripple/testing"
 	"github.com/juju/testing/checkers"
 	. "gopkg.in/check.v1"
 )
@@ -51,3 +57,437 @@ func (s *JSONSuite) TestLedgersJSON(c *C) {
 		compare(c, f, b, out)
 	}
 }
+
+func checkRoundTrip(c *C, txm *TransactionWithMetaData, msg CommentInterface) {
+	first, err := json.Marshal(txm)
+	c.Assert(err, IsNil, msg)
+	var decoded TransactionWithMetaData
+	c.Assert(json.Unmarshal(first, &decoded), IsNil, msg)
+	c.Assert(decoded.GetTransactionType(), Equals, txm.GetTransactionType(), msg)
+	c.Assert(decoded.Date, Equals, txm.Date, msg)
+	second, err := json.Marshal(decoded)
+	c.Assert(err, IsNil, msg)
+	c.Assert(string(second), Equals, string(first), msg)
+}
+
+func (s *JSONSuite) TestTransactionsRoundTrip(c *C) {
+	files, err := filepath.Glob("testdata/transaction_*.json")
+	c.Assert(err, IsNil)
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		c.Assert(err, IsNil)
+		var txm TransactionWithMetaData
+		c.Assert(json.Unmarshal(b, &txm), IsNil)
+		checkRoundTrip(c, &txm, Commentf(f))
+	}
+	for _, test := range internal.Transactions {
+		tx, err := ReadTransaction(test.Reader())
+		c.Assert(err, IsNil)
+		txm := &TransactionWithMetaData{Transaction: tx, Date: RippleTime{500000000}}
+		checkRoundTrip(c, txm, Commentf(test.Description))
+	}
+	for _, test := range internal.Nodes {
+		nodeId, err := NewHash256(test.NodeId())
+		c.Assert(err, IsNil)
+		n, err := ReadPrefix(test.Reader(), *nodeId)
+		if txm, ok := n.(*TransactionWithMetaData); ok && err == nil {
+			checkRoundTrip(c, txm, Commentf(test.Description))
+		}
+	}
+}
+
+func (s *JSONSuite) TestAdversarialMemosRoundTrip(c *C) {
+	hostile := []string{
+		`}`, `"}`, `\"`, `{"tx":{}, "meta":{}}`, `"metaData":{}`, `"TransactionType":"OfferCreate"`,
+		`"DeliveredAmount":"1"`, "\x00\xff\xfe", "\u2028\u2029", "}\n}\n", `</script>`,
+	}
+	account, err := NewAccountFromAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Assert(err, IsNil)
+	destination, err := NewAccountFromAddress("rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(err, IsNil)
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		txm := NewTransactionWithMetadata(PAYMENT)
+		payment := txm.Transaction.(*Payment)
+		payment.Account, payment.Destination = *account, *destination
+		payment.Amount, payment.Fee = *amountCheck("1000"), *amountCheck("12").Value
+		for j := random.Intn(4); j >= 0; j-- {
+			var fields [3]string
+			for k := range fields {
+				if random.Intn(2) == 0 {
+					fields[k] = hostile[random.Intn(len(hostile))]
+				} else {
+					b := make([]byte, random.Intn(32))
+					random.Read(b)
+					fields[k] = string(b)
+				}
+			}
+			payment.Memos = append(payment.Memos, NewMemo(fields[0], fields[1], fields[2]))
+		}
+		txm.LedgerSequence = random.Uint32()
+		txm.Date = RippleTime{random.Uint32()}
+		txm.MetaData.TransactionResult = tesSUCCESS
+		txm.MetaData.DeliveredAmount = amountCheck("1000")
+		msg := Commentf("%d", i)
+		checkRoundTrip(c, txm, msg)
+
+		b, err := json.Marshal(txm)
+		c.Assert(err, IsNil, msg)
+		var decoded TransactionWithMetaData
+		c.Assert(json.Unmarshal(b, &decoded), IsNil, msg)
+		c.Assert(decoded.Transaction.(*Payment).Memos, DeepEquals, payment.Memos, msg)
+		c.Assert(decoded.LedgerSequence, Equals, txm.LedgerSequence, msg)
+		c.Assert(decoded.MetaData.DeliveredAmount.String(), Equals, "0.001/XRP", msg)
+	}
+}
+
+func (s *JSONSuite) TestTransactionDecoder(c *C) {
+	files, err := filepath.Glob("testdata/transaction_*.json")
+	c.Assert(err, IsNil)
+	d := NewTransactionDecoder()
+	// Twice so the second pass decodes into released transactions
+	for i := 0; i < 2; i++ {
+		for _, f := range files {
+			b, err := ioutil.ReadFile(f)
+			c.Assert(err, IsNil)
+			var expected, obtained TransactionWithMetaData
+			c.Assert(json.Unmarshal(b, &expected), IsNil)
+			c.Assert(d.Decode(b, &obtained), IsNil, Commentf(f))
+			want, err := json.Marshal(expected)
+			c.Assert(err, IsNil)
+			got, err := json.Marshal(obtained)
+			c.Assert(err, IsNil)
+			c.Assert(string(got), Equals, string(want), Commentf(f))
+			d.Release(&obtained)
+			c.Assert(obtained.Transaction, IsNil)
+		}
+	}
+}
+
+func (s *JSONSuite) TestTransactionDecoderReuse(c *C) {
+	full, err := ioutil.ReadFile("testdata/transaction_payment_with_rippling.json")
+	c.Assert(err, IsNil)
+	bare := []byte(`{"TransactionType":"Payment","Account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","Destination":"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B","Amount":"1000","Fee":"10","Sequence":1}`)
+	d := NewTransactionDecoder()
+	for i := 0; i < 10; i++ {
+		var txm TransactionWithMetaData
+		c.Assert(d.Decode(full, &txm), IsNil)
+		c.Assert(*txm.Transaction.(*Payment).DestinationTag, Equals, uint32(54025705))
+		d.Release(&txm)
+
+		c.Assert(d.Decode(bare, &txm), IsNil)
+		payment := txm.Transaction.(*Payment)
+		c.Assert(payment.TransactionType, Equals, PAYMENT)
+		c.Assert(payment.DestinationTag, IsNil)
+		c.Assert(payment.Paths, IsNil)
+		c.Assert(payment.SendMax, IsNil)
+		c.Assert(payment.TxnSignature, IsNil)
+		c.Assert(payment.Hash, Equals, zero256)
+		c.Assert(payment.Amount.String(), Equals, "0.001/XRP")
+		c.Assert(payment.Sequence, Equals, uint32(1))
+		d.Release(&txm)
+	}
+}
+
+func (s *JSONSuite) TestOmitEmptyAccounts(c *C) {
+	account, err := NewAccountFromAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Assert(err, IsNil)
+	tx := TxFactory[SET_REGULAR_KEY]().(*SetRegularKey)
+	tx.Account = *account
+	tx.RegularKey = &RegularKey{}
+
+	plain, err := json.Marshal(tx)
+	c.Assert(err, IsNil)
+	c.Check(string(plain), Matches, `.*"RegularKey":"rrrrrrrrrrrrrrrrrrrrrhoLvTp".*`)
+
+	var fields map[string]interface{}
+	b, err := json.Marshal(OmitEmptyAccounts{tx})
+	c.Assert(err, IsNil)
+	c.Assert(json.Unmarshal(b, &fields), IsNil)
+	c.Check(fields["RegularKey"], IsNil)
+	c.Check(fields["Account"], Equals, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+
+	tx.Account, tx.RegularKey = Account{}, (*RegularKey)(account)
+	b, err = json.Marshal(OmitEmptyAccounts{tx})
+	c.Assert(err, IsNil)
+	fields = nil
+	c.Assert(json.Unmarshal(b, &fields), IsNil)
+	c.Check(fields["Account"], IsNil)
+	c.Check(fields["RegularKey"], Equals, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+}
+
+func (s *JSONSuite) TestXAddresses(c *C) {
+	const classic = `{"TransactionType":"Payment","Account":"r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59","SourceTag":1,"Destination":"rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf","Amount":"1000","Fee":"10","Sequence":1,"SigningPubKey":""}`
+	var txm TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(classic), &txm), IsNil)
+
+	b, err := json.Marshal(XAddresses{Transaction: txm.Transaction})
+	c.Assert(err, IsNil)
+	var fields map[string]interface{}
+	c.Assert(json.Unmarshal(b, &fields), IsNil)
+	c.Check(fields["Account"], Equals, "X7AcgcsBL6XDcUb289X4mJ8djcdyKaGZMhc9YTE92ehJ2Fu")
+	c.Check(fields["Destination"], Equals, "XVLhHMPHU98es4dbozjVtdWzVrDjtV5fdx1mHp98tDMoQXb")
+	c.Check(fields["SourceTag"], IsNil)
+	c.Check(fields["DestinationTag"], IsNil)
+
+	var decoded XAddresses
+	c.Assert(json.Unmarshal(b, &decoded), IsNil)
+	c.Check(decoded.Test, Equals, false)
+	c.Check(decoded.Transaction, DeepEquals, txm.Transaction)
+
+	b, err = json.Marshal(XAddresses{Transaction: txm.Transaction, Test: true})
+	c.Assert(err, IsNil)
+	c.Assert(json.Unmarshal(b, &decoded), IsNil)
+	c.Check(decoded.Test, Equals, true)
+	c.Check(decoded.Transaction, DeepEquals, txm.Transaction)
+
+	// Classic addresses are read as is
+	c.Assert(json.Unmarshal([]byte(classic), &decoded), IsNil)
+	c.Check(decoded.Transaction, DeepEquals, txm.Transaction)
+
+	conflict := `{"TransactionType":"Payment","Account":"X7AcgcsBL6XDcUb289X4mJ8djcdyKaGZMhc9YTE92ehJ2Fu","SourceTag":2,"Destination":"rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf","Amount":"1000","Fee":"10","Sequence":1}`
+	c.Check(json.Unmarshal([]byte(conflict), &decoded), ErrorMatches, "SourceTag of 2 conflicts with the X-address: X7Acg.*")
+}
+
+func benchmarkTransactionJSON(b *testing.B, decode func([]byte, *TransactionWithMetaData) error, release func(*TransactionWithMetaData)) {
+	bites, err := ioutil.ReadFile("testdata/transaction_payment_with_rippling.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var txm TransactionWithMetaData
+		if err := decode(bites, &txm); err != nil {
+			b.Fatal(err)
+		}
+		release(&txm)
+	}
+}
+
+func BenchmarkTransactionUnmarshalJSON(b *testing.B) {
+	benchmarkTransactionJSON(b, func(bites []byte, txm *TransactionWithMetaData) error {
+		return json.Unmarshal(bites, txm)
+	}, func(*TransactionWithMetaData) {})
+}
+
+func transactionArray(files []string) ([]byte, error) {
+	var raw []json.RawMessage
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, b)
+	}
+	return json.Marshal(raw)
+}
+
+func (s *JSONSuite) TestTransactionDecoderDecodeEach(c *C) {
+	files, err := filepath.Glob("testdata/transaction_*.json")
+	c.Assert(err, IsNil)
+	array, err := transactionArray(files)
+	c.Assert(err, IsNil)
+	d := NewTransactionDecoder()
+
+	count := func(types ...TransactionType) map[TransactionType]int {
+		counts := make(map[TransactionType]int)
+		c.Assert(d.DecodeEach(bytes.NewReader(array), func(txm *TransactionWithMetaData) error {
+			counts[txm.GetTransactionType()]++
+			return nil
+		}, types...), IsNil)
+		return counts
+	}
+	c.Check(count(PAYMENT, OFFER_CREATE), DeepEquals, map[TransactionType]int{PAYMENT: 1, OFFER_CREATE: 2})
+	c.Check(count(TRUST_SET), HasLen, 0)
+	c.Check(count(), DeepEquals, map[TransactionType]int{PAYMENT: 1, OFFER_CREATE: 2, ACCOUNT_SET: 1, SET_FEE: 1})
+
+	stop := fmt.Errorf("Stop")
+	calls := 0
+	c.Check(d.DecodeEach(bytes.NewReader(array), func(*TransactionWithMetaData) error {
+		calls++
+		return stop
+	}), Equals, stop)
+	c.Check(calls, Equals, 1)
+	c.Check(d.DecodeEach(strings.NewReader(`{"TransactionType":"Payment"}`), nil), ErrorMatches, "Expected an array of transactions: .*")
+	c.Check(d.DecodeEach(strings.NewReader(`[{"Account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}]`), nil, PAYMENT), ErrorMatches, ".*Missing TransactionType")
+}
+
+func (s *JSONSuite) TestDecodeTransactionsLenient(c *C) {
+	payment, err := ioutil.ReadFile("testdata/transaction_payment_with_rippling.json")
+	c.Assert(err, IsNil)
+	accountSet, err := ioutil.ReadFile("testdata/transaction_account_set.json")
+	c.Assert(err, IsNil)
+	unknown := `{"TransactionType":"Batch","Account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","Fee":"10","Sequence":1}`
+	array := []byte(`[` + string(payment) + `,` + unknown + `,` + string(accountSet) + `,{"Account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}]`)
+
+	txs, errs := DecodeTransactionsLenient(array)
+	c.Assert(txs, HasLen, 4)
+	c.Assert(errs, HasLen, 4)
+	c.Check(txs[0].GetTransactionType(), Equals, PAYMENT)
+	c.Check(errs[0], IsNil)
+	c.Check(txs[1], IsNil)
+	c.Check(errs[1], ErrorMatches, "Transaction 1: Unknown TransactionType: Batch")
+	c.Check(txs[2].GetTransactionType(), Equals, ACCOUNT_SET)
+	c.Check(errs[2], IsNil)
+	c.Check(txs[3], IsNil)
+	c.Check(errs[3], ErrorMatches, "Transaction 3: .*Missing TransactionType")
+
+	txs, errs = DecodeTransactionsLenient(array[:len(array)-1])
+	c.Check(txs, HasLen, 0)
+	c.Check(errs, HasLen, 1)
+}
+
+func benchmarkDecodeEach(b *testing.B, types ...TransactionType) {
+	var files []string
+	for i := 0; i < 100; i++ {
+		files = append(files, "testdata/transaction_payment_with_rippling.json")
+	}
+	array, err := transactionArray(files)
+	if err != nil {
+		b.Fatal(err)
+	}
+	d := NewTransactionDecoder()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := d.DecodeEach(bytes.NewReader(array), func(*TransactionWithMetaData) error { return nil }, types...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeEachAll(b *testing.B) { benchmarkDecodeEach(b) }
+
+func BenchmarkDecodeEachSkip(b *testing.B) { benchmarkDecodeEach(b, OFFER_CREATE) }
+
+func (s *JSONSuite) TestTransactionDecoderUnknownFields(c *C) {
+	files, err := filepath.Glob("testdata/transaction_*.json")
+	c.Assert(err, IsNil)
+	d := NewTransactionDecoder()
+	d.DisallowUnknownFields()
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		c.Assert(err, IsNil)
+		var txm TransactionWithMetaData
+		c.Check(d.Decode(b, &txm), IsNil, Commentf(f))
+	}
+
+	payment := `{"TransactionType":"Payment","Account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","Destination":"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B","Amount":"1000","Fee":"10","Sequence":1,"DeliverMin":"900","CredentialIDs":[]}`
+	var txm TransactionWithMetaData
+	c.Check(d.Decode([]byte(payment), &txm), ErrorMatches, "Unknown Payment fields: CredentialIDs")
+	split := `{"tx":` + payment + `,"meta":{},"validated":true}`
+	c.Check(d.Decode([]byte(split), &txm), ErrorMatches, "Unknown Payment fields: CredentialIDs")
+	c.Check(NewTransactionDecoder().Decode([]byte(payment), &txm), IsNil)
+	c.Check(json.Unmarshal([]byte(payment), &txm), IsNil)
+}
+
+func BenchmarkTransactionDecoder(b *testing.B) {
+	d := NewTransactionDecoder()
+	benchmarkTransactionJSON(b, d.Decode, d.Release)
+}
+
+func rippledResult(c *C, path string, result interface{}) {
+	b, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+	c.Assert(json.Unmarshal(b, &struct{ Result interface{} }{result}), IsNil)
+}
+
+func (s *JSONSuite) TestRippledResponses(c *C) {
+	var tx json.RawMessage
+	rippledResult(c, "../websockets/testdata/tx.json", &tx)
+	var accountTx struct{ Transactions []json.RawMessage }
+	rippledResult(c, "../websockets/testdata/account_tx.json", &accountTx)
+	var ledger struct {
+		Ledger struct{ Transactions []json.RawMessage }
+	}
+	rippledResult(c, "../websockets/testdata/ledger.json", &ledger)
+
+	for _, test := range []struct {
+		path         string
+		transactions []json.RawMessage
+		dated        bool
+	}{
+		{"tx.json", []json.RawMessage{tx}, true},
+		{"account_tx.json", accountTx.Transactions, true},
+		{"ledger.json", ledger.Ledger.Transactions, false},
+	} {
+		c.Assert(test.transactions, Not(HasLen), 0, Commentf(test.path))
+		for i, b := range test.transactions {
+			msg := Commentf("%s %d", test.path, i)
+			var txm TransactionWithMetaData
+			c.Assert(json.Unmarshal(b, &txm), IsNil, msg)
+			c.Check(txm.Transaction, NotNil, msg)
+			c.Check(txm.GetHash().IsZero(), Equals, false, msg)
+			c.Check(txm.MetaData.AffectedNodes, Not(HasLen), 0, msg)
+			c.Check(txm.LedgerSequence != 0, Equals, test.dated, msg)
+			c.Check(txm.Date.Uint32() != 0, Equals, test.dated, msg)
+			checkRoundTrip(c, &txm, msg)
+		}
+	}
+}
+
+func (s *JSONSuite) TestTransactionWithMetaDataForms(c *C) {
+	b, err := ioutil.ReadFile("testdata/transaction_offercreate.json")
+	c.Assert(err, IsNil)
+	var expected TransactionWithMetaData
+	c.Assert(json.Unmarshal(b, &expected), IsNil)
+	var fields map[string]json.RawMessage
+	c.Assert(json.Unmarshal(b, &fields), IsNil)
+	meta, date, ledgerIndex := fields["meta"], fields["date"], fields["ledger_index"]
+	delete(fields, "meta")
+	delete(fields, "date")
+	delete(fields, "ledger_index")
+	txType := fields["TransactionType"]
+	delete(fields, "TransactionType")
+	txFields, err := json.Marshal(fields)
+	c.Assert(err, IsNil)
+	// The decoys are strings which the decoder must not mistake for keys
+	decoys := `"ctid":"\"tx\":{\"TransactionType\":\"Payment\"}","close_time_iso":"\"metaData\":{}"`
+
+	hash, raw, err := Raw(expected.Transaction)
+	c.Assert(err, IsNil)
+	var metaRaw bytes.Buffer
+	c.Assert(encode(&metaRaw, &expected.MetaData, false), IsNil)
+	blob := fmt.Sprintf(`{"tx_blob":"%X","meta":"%X","date":%s,"ledger_index":%s}`, raw, metaRaw.Bytes(), date, ledgerIndex)
+
+	flat := string(txFields[:len(txFields)-1])
+	for name, form := range map[string]string{
+		"reordered":  fmt.Sprintf(`{"meta":%s,%s,"date":%s,"TransactionType":%s,"ledger_index":%s,%s`, meta, decoys, date, txType, ledgerIndex, txFields[1:]),
+		"account_tx": fmt.Sprintf(`{"meta":%s,%s,"tx":%s,"TransactionType":%s},"date":%s,"ledger_index":%s,"validated":true}`, meta, decoys, flat, txType, date, ledgerIndex),
+		"metaData":   fmt.Sprintf(`%s,%s,"metaData":%s,"TransactionType":%s,"date":%s,"ledger_index":%s}`, flat, decoys, meta, txType, date, ledgerIndex),
+		"binary":     blob,
+	} {
+		msg := Commentf(name)
+		var txm TransactionWithMetaData
+		c.Assert(json.Unmarshal([]byte(form), &txm), IsNil, msg)
+		c.Check(txm.GetTransactionType(), Equals, OFFER_CREATE, msg)
+		c.Check(*txm.GetHash(), Equals, hash, msg)
+		c.Check(txm.Date, Equals, expected.Date, msg)
+		c.Check(txm.LedgerSequence, Equals, expected.LedgerSequence, msg)
+		c.Check(txm.MetaData.AffectedNodes, HasLen, len(expected.MetaData.AffectedNodes), msg)
+		c.Check(txm.MetaData.TransactionIndex, Equals, expected.MetaData.TransactionIndex, msg)
+	}
+
+	var txm TransactionWithMetaData
+	err = json.Unmarshal(txFields, &txm)
+	c.Check(err, ErrorMatches, "Not a valid transaction with metadata: Missing TransactionType")
+	c.Check(json.Unmarshal([]byte(`{"tx_blob":"XYZ"}`), &txm), ErrorMatches, "Bad tx_blob: .*")
+}
+
+func (s *JSONSuite) TestTransactionKeyOrder(c *C) {
+	bare := []byte(`{"TransactionType":"Payment","Account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","Destination":"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B","Amount":"1000","Fee":"10","Sequence":1}`)
+	var txm TransactionWithMetaData
+	c.Assert(json.Unmarshal(bare, &txm), IsNil)
+	txm.LedgerSequence = 10
+	b, err := json.Marshal(txm)
+	c.Assert(err, IsNil)
+	fields, err := readJSONFields(b)
+	c.Assert(err, IsNil)
+	var keys []string
+	for _, f := range fields {
+		keys = append(keys, f.Name)
+	}
+	// The transaction fields in struct order, then the extras
+	c.Check(keys, DeepEquals, []string{"TransactionType", "Account", "Sequence", "Fee", "hash", "Destination", "Amount", "inLedger", "ledger_index", "meta"})
+}
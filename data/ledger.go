@@ -1,5 +1,11 @@
 package data
 
+import (
+	"io"
+
+	"github.com/atticlab/ripple/crypto"
+)
+
 type LedgerHeader struct {
 	LedgerSequence  uint32     `json:"ledger_index,string"`
 	TotalXRP        uint64     `json:"total_coins,string"`
@@ -35,3 +41,23 @@ func (l Ledger) NodeType() NodeType { return NT_LEDGER }
 func (l Ledger) Ledger() uint32     { return l.LedgerSequence }
 func (l Ledger) NodeId() *Hash256   { return &l.Hash }
 func (l Ledger) GetHash() *Hash256  { return &l.Hash }
+
+// Marshal writes the canonical binary layout of the header, as found in
+// the ledger_data field of a binary ledger response
+func (h *LedgerHeader) Marshal(w io.Writer) error {
+	return write(w, h)
+}
+
+func (h *LedgerHeader) Unmarshal(r Reader) error {
+	return read(r, h)
+}
+
+// Hash computes the ledger hash from the header. Ledger shadows this with
+// its stored hash, so use ledger.LedgerHeader.Hash() to verify it.
+func (h LedgerHeader) Hash() (Hash256, error) {
+	hasher := crypto.NewHasher(uint32(HP_LEDGER_MASTER))
+	if err := h.Marshal(hasher); err != nil {
+		return zero256, err
+	}
+	return Hash256(hasher.Sum256()), nil
+}
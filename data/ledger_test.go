@@ -0,0 +1,37 @@
+package data
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	. "gopkg.in/check.v1"
+)
+
+type LedgerSuite struct{}
+
+var _ = Suite(&LedgerSuite{})
+
+// Mainnet ledger 32570 as returned by the ledger command with binary set
+const ledgerHeader32570 = "00007F3A016345785D89F1A060A01EBF11537D8394EA1235253293508BDA7131D5F8710EFE9413AA129653A200000000000000000000000000000000000000000000000000000000000000003806AF8F22037DE598D30D38C8861FADF391171D26F7DE34ACFA038996EA6BEB1875129C187512A60A00"
+
+func (s *LedgerSuite) TestLedgerHeader(c *C) {
+	b, err := hex.DecodeString(ledgerHeader32570)
+	c.Assert(err, IsNil)
+	var header LedgerHeader
+	c.Assert(header.Unmarshal(bytes.NewReader(b)), IsNil)
+	c.Check(header.LedgerSequence, Equals, uint32(32570))
+	c.Check(header.TotalXRP, Equals, uint64(99999999999996320))
+	c.Check(header.PreviousLedger.String(), Equals, "60A01EBF11537D8394EA1235253293508BDA7131D5F8710EFE9413AA129653A2")
+	c.Check(header.StateHash.String(), Equals, "3806AF8F22037DE598D30D38C8861FADF391171D26F7DE34ACFA038996EA6BEB")
+	c.Check(header.ParentCloseTime.Uint32(), Equals, uint32(410325660))
+	c.Check(header.CloseTime.Uint32(), Equals, uint32(410325670))
+	c.Check(header.CloseResolution, Equals, uint8(10))
+
+	var buf bytes.Buffer
+	c.Assert(header.Marshal(&buf), IsNil)
+	c.Check(buf.Bytes(), DeepEquals, b)
+
+	hash, err := header.Hash()
+	c.Assert(err, IsNil)
+	c.Check(hash.String(), Equals, "4109C6F2045FC7EFF4CDE8F9905D19C28820D86304080FF886B299F0206E42B5")
+}
@@ -1,5 +1,14 @@
 package data
 
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/atticlab/ripple/crypto"
+)
+
 type LedgerEntrySlice []LedgerEntry
 
 type leBase struct {
@@ -103,16 +112,17 @@ type FeeSettings struct {
 
 type Escrow struct {
 	leBase
-	Flags          *LedgerEntryFlag `json:",omitempty"`
-	Account        Account          `json:",omitempty"`
-	Destination    Account          `json:",omitempty"`
-	Amount         Amount           `json:",omitempty"`
-	Condition      *VariableLength  `json:",omitempty"`
-	CancelAfter    *uint32          `json:",omitempty"`
-	FinishAfter    *uint32          `json:",omitempty"`
-	SourceTag      *uint32          `json:",omitempty"`
-	DestinationTag *uint32          `json:",omitempty"`
-	OwnerNode      *NodeIndex       `json:",omitempty"`
+	Flags           *LedgerEntryFlag `json:",omitempty"`
+	Account         Account          `json:",omitempty"`
+	Destination     Account          `json:",omitempty"`
+	Amount          Amount           `json:",omitempty"`
+	Condition       *VariableLength  `json:",omitempty"`
+	CancelAfter     *uint32          `json:",omitempty"`
+	FinishAfter     *uint32          `json:",omitempty"`
+	SourceTag       *uint32          `json:",omitempty"`
+	DestinationTag  *uint32          `json:",omitempty"`
+	OwnerNode       *NodeIndex       `json:",omitempty"`
+	DestinationNode *NodeIndex       `json:",omitempty"`
 }
 
 type SignerEntry struct {
@@ -134,6 +144,15 @@ type Signer struct {
 
 type Signers []Signer
 
+func (s Signers) Len() int      { return len(s) }
+func (s Signers) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s Signers) Less(i, j int) bool {
+	a, b := s[i].Signer.Account, s[j].Signer.Account
+	return a == nil && b != nil || a != nil && b != nil && a.Less(*b)
+}
+
+func (s Signers) Sort() { sort.Sort(s) }
+
 type SignerList struct {
 	leBase
 	Flags         *LedgerEntryFlag `json:",omitempty"`
@@ -144,38 +163,110 @@ type SignerList struct {
 }
 
 type Ticket struct {
-	leBase
-	Flags      *LedgerEntryFlag `json:",omitempty"`
-	Account    *Account         `json:",omitempty"`
-	Sequence   *uint32          `json:",omitempty"`
-	OwnerNode  *NodeIndex       `json:",omitempty"`
-	Target     *Account         `json:",omitempty"`
-	Expiration *uint32          `json:",omitempty"`
-}
-
-type PayChannel struct {
 	leBase
 	Flags          *LedgerEntryFlag `json:",omitempty"`
 	Account        *Account         `json:",omitempty"`
-	Destination    *Account         `json:",omitempty"`
-	Amount         *Amount          `json:",omitempty"`
-	Balance        *Amount          `json:",omitempty"`
-	PublicKey      *PublicKey       `json:",omitempty"`
-	SettleDelay    *uint32          `json:",omitempty"`
+	Sequence       *uint32          `json:",omitempty"`
+	TicketSequence *uint32          `json:",omitempty"`
 	OwnerNode      *NodeIndex       `json:",omitempty"`
+	Target         *Account         `json:",omitempty"`
 	Expiration     *uint32          `json:",omitempty"`
-	CancelAfter    *uint32          `json:",omitempty"`
-	DestinationTag *uint32          `json:",omitempty"`
-	SourceTag      *uint32          `json:",omitempty"`
+}
+
+// DepositPreauth allows Authorize to send payments to Account while
+// the latter requires deposit authorization
+type DepositPreauth struct {
+	leBase
+	Flags     *LedgerEntryFlag `json:",omitempty"`
+	Account   *Account         `json:",omitempty"`
+	Authorize *Account         `json:",omitempty"`
+	OwnerNode *NodeIndex       `json:",omitempty"`
+}
+
+type PayChannel struct {
+	leBase
+	Flags           *LedgerEntryFlag `json:",omitempty"`
+	Account         *Account         `json:",omitempty"`
+	Destination     *Account         `json:",omitempty"`
+	Amount          *Amount          `json:",omitempty"`
+	Balance         *Amount          `json:",omitempty"`
+	PublicKey       *PublicKey       `json:",omitempty"`
+	SettleDelay     *uint32          `json:",omitempty"`
+	OwnerNode       *NodeIndex       `json:",omitempty"`
+	Expiration      *uint32          `json:",omitempty"`
+	CancelAfter     *uint32          `json:",omitempty"`
+	DestinationTag  *uint32          `json:",omitempty"`
+	SourceTag       *uint32          `json:",omitempty"`
+	DestinationNode *NodeIndex       `json:",omitempty"`
 }
 
 type Check struct {
 	leBase
-	Account     *Account `json:",omitempty"`
-	Destination *Account `json:",omitempty"`
-	Expiration  *uint32  `json:",omitempty"`
-	SendMax     *Amount  `json:",omitempty"`
-	Sequence    *uint32  `json:",omitempty"`
+	Flags           *LedgerEntryFlag `json:",omitempty"`
+	Account         *Account         `json:",omitempty"`
+	Destination     *Account         `json:",omitempty"`
+	Expiration      *uint32          `json:",omitempty"`
+	SendMax         *Amount          `json:",omitempty"`
+	Sequence        *uint32          `json:",omitempty"`
+	SourceTag       *uint32          `json:",omitempty"`
+	DestinationTag  *uint32          `json:",omitempty"`
+	InvoiceID       *Hash256         `json:",omitempty"`
+	OwnerNode       *NodeIndex       `json:",omitempty"`
+	DestinationNode *NodeIndex       `json:",omitempty"`
+}
+
+// PriceData is a price reported by an Oracle. AssetPrice is scaled by
+// 10^-Scale and is absent when the pair is being removed.
+type PriceData struct {
+	PriceData struct {
+		BaseAsset  Currency
+		QuoteAsset Currency
+		AssetPrice *Uint64Hex `json:",omitempty"`
+		Scale      *uint8     `json:",omitempty"`
+	}
+}
+
+type Oracle struct {
+	leBase
+	Flags            *LedgerEntryFlag `json:",omitempty"`
+	Owner            *Account         `json:",omitempty"`
+	OracleDocumentID *uint32          `json:",omitempty"`
+	Provider         *VariableLength  `json:",omitempty"`
+	AssetClass       *VariableLength  `json:",omitempty"`
+	URI              *VariableLength  `json:",omitempty"`
+	LastUpdateTime   *uint32          `json:",omitempty"`
+	PriceDataSeries  []PriceData      `json:",omitempty"`
+	OwnerNode        *NodeIndex       `json:",omitempty"`
+}
+
+type NFToken struct {
+	NFToken struct {
+		NFTokenID Hash256
+		URI       *VariableLength `json:",omitempty"`
+	}
+}
+
+// NFTokenPage holds up to 32 of an owner's tokens, sorted by id. The index
+// of a page is the owner's account followed by the low 96 bits of the
+// largest token id which could be on it.
+type NFTokenPage struct {
+	leBase
+	Flags           *LedgerEntryFlag `json:",omitempty"`
+	PreviousPageMin *Hash256         `json:",omitempty"`
+	NextPageMin     *Hash256         `json:",omitempty"`
+	NFTokens        []NFToken        `json:",omitempty"`
+}
+
+type NFTokenOffer struct {
+	leBase
+	Flags            *LedgerEntryFlag `json:",omitempty"`
+	Owner            *Account         `json:",omitempty"`
+	NFTokenID        *Hash256         `json:",omitempty"`
+	Amount           *Amount          `json:",omitempty"`
+	Destination      *Account         `json:",omitempty"`
+	Expiration       *uint32          `json:",omitempty"`
+	OwnerNode        *NodeIndex       `json:",omitempty"`
+	NFTokenOfferNode *NodeIndex       `json:",omitempty"`
 }
 
 func (a *AccountRoot) Affects(account Account) bool {
@@ -192,6 +283,13 @@ func (f *FeeSettings) Affects(account Account) bool  { return false }
 func (s *Escrow) Affects(account Account) bool {
 	return s.Account.Equals(account) || s.Destination.Equals(account)
 }
+func (o *Oracle) Affects(account Account) bool { return o.Owner != nil && o.Owner.Equals(account) }
+func (p *NFTokenPage) Affects(account Account) bool {
+	return p.LedgerIndex != nil && bytes.Equal(p.LedgerIndex[:len(account)], account[:])
+}
+func (o *NFTokenOffer) Affects(account Account) bool {
+	return (o.Owner != nil && o.Owner.Equals(account)) || (o.Destination != nil && o.Destination.Equals(account))
+}
 func (s *SignerList) Affects(account Account) bool {
 	for _, entry := range s.SignerEntries {
 		if entry.SignerEntry.Account != nil && entry.SignerEntry.Account.Equals(account) {
@@ -201,6 +299,9 @@ func (s *SignerList) Affects(account Account) bool {
 	return false
 }
 func (t *Ticket) Affects(account Account) bool { return t.Account != nil && t.Account.Equals(account) }
+func (d *DepositPreauth) Affects(account Account) bool {
+	return (d.Account != nil && d.Account.Equals(account)) || (d.Authorize != nil && d.Authorize.Equals(account))
+}
 func (p *PayChannel) Affects(account Account) bool {
 	return (p.Account != nil && p.Account.Equals(account)) || (p.Destination != nil && p.Destination.Equals(account))
 }
@@ -218,6 +319,88 @@ func (le *leBase) NodeId() *Hash256                    { return &le.Id }
 func (le *leBase) GetLedgerIndex() *Hash256            { return le.LedgerIndex }
 func (le *leBase) GetPreviousTxnId() *Hash256          { return le.PreviousTxnID }
 
+// IsBookDirectory distinguishes the directories of an order book, which
+// have the currencies and issuers of the book, from owner directories
+func (d Directory) IsBookDirectory() bool {
+	return d.TakerPaysCurrency != nil
+}
+
+// MaxWeight is the combined weight of all the signers in the list
+func (l SignerList) MaxWeight() uint32 {
+	var weight uint32
+	for _, entry := range l.SignerEntries {
+		if entry.SignerEntry.SignerWeight != nil {
+			weight += uint32(*entry.SignerEntry.SignerWeight)
+		}
+	}
+	return weight
+}
+
+// QuorumReachable is true when the quorum is non-zero and the signers
+// can meet it by signing together
+func (l SignerList) QuorumReachable() bool {
+	return l.SignerQuorum != nil && *l.SignerQuorum > 0 && l.MaxWeight() >= *l.SignerQuorum
+}
+
+// CanFinish reports whether an EscrowFinish with the given fulfillment would
+// release the escrow in a ledger whose parent closed at closeTime. The time must
+// be past FinishAfter and not past CancelAfter, and a conditional escrow needs
+// a fulfillment which satisfies the condition. An error is returned for a
+// malformed fulfillment or one supplied to an escrow without a condition.
+func (s Escrow) CanFinish(closeTime RippleTime, fulfillment []byte) (bool, error) {
+	now := closeTime.Uint32()
+	if s.FinishAfter != nil && now <= *s.FinishAfter {
+		return false, nil
+	}
+	if s.CancelAfter != nil && now > *s.CancelAfter {
+		return false, nil
+	}
+	switch {
+	case s.Condition == nil && fulfillment != nil:
+		return false, fmt.Errorf("Fulfillment supplied for escrow without condition")
+	case s.Condition == nil:
+		return true, nil
+	case fulfillment == nil:
+		return false, nil
+	default:
+		return crypto.ValidateFulfillment(*s.Condition, fulfillment)
+	}
+}
+
+func (a AccountRoot) hasFlag(flag LedgerEntryFlag) bool {
+	return a.Flags != nil && *a.Flags&flag != 0
+}
+
+// GlobalFreeze is true when the account has frozen all the trust lines
+// for the currencies it issues
+func (a AccountRoot) GlobalFreeze() bool { return a.hasFlag(LsGlobalFreeze) }
+
+// NoFreeze is true when the account has permanently given up the ability
+// to freeze trust lines
+func (a AccountRoot) NoFreeze() bool { return a.hasFlag(LsNoFreeze) }
+
+// DepositAuth is true when the account only accepts payments from itself
+// and from the accounts it has preauthorized
+func (a AccountRoot) DepositAuth() bool { return a.hasFlag(LsDepositAuth) }
+
+// AllowTrustLineClawback is true when the account can claw back the
+// tokens it issues
+func (a AccountRoot) AllowTrustLineClawback() bool { return a.hasFlag(LsAllowTrustLineClawback) }
+
 func (o *Offer) Ratio() *Value {
 	return o.TakerPays.Ratio(*o.TakerGets)
 }
+
+// Quality returns the exchange rate by which rippled orders the offer in
+// its book. It is the last 8 bytes of the BookDirectory when known, which
+// is fixed when the offer is placed, and otherwise computed from the
+// remaining TakerPays and TakerGets.
+func (o *Offer) Quality() (ExchangeRate, error) {
+	if o.BookDirectory != nil {
+		return ExchangeRate(binary.BigEndian.Uint64(o.BookDirectory[24:])), nil
+	}
+	if o.TakerPays == nil || o.TakerGets == nil {
+		return 0, fmt.Errorf("Offer has no TakerPays or TakerGets")
+	}
+	return NewExchangeRate(o.TakerPays, o.TakerGets)
+}
@@ -0,0 +1,237 @@
+package data
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	internal "github.com/atticlab/ripple/testing"
+	. "gopkg.in/check.v1"
+)
+
+type LedgerEntrySuite struct{}
+
+var _ = Suite(&LedgerEntrySuite{})
+
+func (s *LedgerEntrySuite) TestFreezeFlags(c *C) {
+	flag := func(f LedgerEntryFlag) *LedgerEntryFlag { return &f }
+	for _, t := range []struct {
+		Flags        *LedgerEntryFlag
+		GlobalFreeze bool
+		NoFreeze     bool
+	}{
+		{nil, false, false},
+		{flag(0), false, false},
+		{flag(LsDefaultRipple | LsRequireAuth), false, false},
+		{flag(LsGlobalFreeze), true, false},
+		{flag(LsNoFreeze), false, true},
+		{flag(LsGlobalFreeze | LsNoFreeze | LsDefaultRipple), true, true},
+	} {
+		account := AccountRoot{Flags: t.Flags}
+		c.Check(account.GlobalFreeze(), Equals, t.GlobalFreeze, Commentf("%v", t.Flags))
+		c.Check(account.NoFreeze(), Equals, t.NoFreeze, Commentf("%v", t.Flags))
+	}
+}
+
+func (s *LedgerEntrySuite) TestIsBookDirectory(c *C) {
+	const (
+		owner = `{"Flags":0,"Indexes":["AD7EAE148287EF12D213A251015F86E6D4BD34B3C4A0A1ED9A17198373F908AD"],"LedgerEntryType":"DirectoryNode","Owner":"rpR95n1iFkTqpoy1e878f4Z1pVHVtWKMNQ","RootIndex":"193C591BF62482468422313F9D3274B5927CA80B4DD3707E5E1BC1E57BD8E121","index":"193C591BF62482468422313F9D3274B5927CA80B4DD3707E5E1BC1E57BD8E121"}`
+		book  = `{"ExchangeRate":"4F069BA8FF484000","Flags":0,"Indexes":["AD7EAE148287EF12D213A251015F86E6D4BD34B3C4A0A1ED9A17198373F908AD"],"LedgerEntryType":"DirectoryNode","RootIndex":"1BBEF97EDE88D40CEE2ADE6FEF121166AFE80D99EBADB01A4F069BA8FF484000","TakerGetsCurrency":"0000000000000000000000000000000000000000","TakerGetsIssuer":"0000000000000000000000000000000000000000","TakerPaysCurrency":"0000000000000000000000004A50590000000000","TakerPaysIssuer":"5BBC0F22F61D9224A110650CFE21CC0C4BE13098","index":"1BBEF97EDE88D40CEE2ADE6FEF121166AFE80D99EBADB01A4F069BA8FF484000"}`
+	)
+	for _, t := range []struct {
+		JSON string
+		Book bool
+	}{
+		{owner, false},
+		{book, true},
+	} {
+		var dir Directory
+		c.Assert(json.Unmarshal([]byte(t.JSON), &dir), IsNil)
+		c.Check(dir.IsBookDirectory(), Equals, t.Book)
+	}
+}
+
+func signerList(quorum *uint32, weights ...uint16) SignerList {
+	var list SignerList
+	list.SignerQuorum = quorum
+	for i := range weights {
+		var entry SignerEntries
+		entry.SignerEntry.SignerWeight = &weights[i]
+		list.SignerEntries = append(list.SignerEntries, entry)
+	}
+	return list
+}
+
+func (s *LedgerEntrySuite) TestQuorumReachable(c *C) {
+	quorum := func(q uint32) *uint32 { return &q }
+	for _, t := range []struct {
+		List      SignerList
+		MaxWeight uint32
+		Reachable bool
+	}{
+		{signerList(quorum(3), 1, 1, 1), 3, true},
+		{signerList(quorum(2), 1, 2), 3, true},
+		{signerList(quorum(4), 1, 1, 1), 3, false},
+		{signerList(quorum(0), 1), 1, false},
+		{signerList(nil, 1), 1, false},
+		{signerList(quorum(1)), 0, false},
+		{signerList(quorum(131070), 65535, 65535), 131070, true},
+	} {
+		c.Check(t.List.MaxWeight(), Equals, t.MaxWeight)
+		c.Check(t.List.QuorumReachable(), Equals, t.Reachable, Commentf("%d", t.MaxWeight))
+	}
+}
+
+func (s *LedgerEntrySuite) TestEscrowCanFinish(c *C) {
+	at := func(n uint32) *uint32 { return &n }
+	condition, err := hex.DecodeString("A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810100")
+	c.Assert(err, IsNil)
+	fulfillment, wrong := []byte{0xA0, 0x02, 0x80, 0x00}, []byte{0xA0, 0x03, 0x80, 0x01, 0x61}
+	timed := Escrow{FinishAfter: at(1000), CancelAfter: at(2000)}
+	conditional := Escrow{Condition: (*VariableLength)(&condition)}
+	both := Escrow{Condition: (*VariableLength)(&condition), FinishAfter: at(1000)}
+	for i, t := range []struct {
+		Escrow      Escrow
+		CloseTime   uint32
+		Fulfillment []byte
+		CanFinish   bool
+	}{
+		{timed, 999, nil, false},
+		{timed, 1000, nil, false},
+		{timed, 1001, nil, true},
+		{timed, 2000, nil, true},
+		{timed, 2001, nil, false},
+		{conditional, 0, nil, false},
+		{conditional, 0, fulfillment, true},
+		{conditional, 0, wrong, false},
+		{both, 1000, fulfillment, false},
+		{both, 1001, nil, false},
+		{both, 1001, fulfillment, true},
+	} {
+		ok, err := t.Escrow.CanFinish(RippleTime{t.CloseTime}, t.Fulfillment)
+		c.Assert(err, IsNil, Commentf("%d", i))
+		c.Check(ok, Equals, t.CanFinish, Commentf("%d", i))
+	}
+
+	_, err = timed.CanFinish(RippleTime{1001}, fulfillment)
+	c.Check(err, ErrorMatches, "Fulfillment supplied for escrow without condition")
+	_, err = conditional.CanFinish(RippleTime{0}, []byte{0xA0})
+	c.Check(err, ErrorMatches, "Malformed fulfillment: .*")
+}
+
+func (s *LedgerEntrySuite) TestVerifyIndex(c *C) {
+	const accountRoot = `{"LedgerEntryType":"AccountRoot","Flags":131072,"OwnerCount":0,"Account":"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B","index":"B7D526FDDF9E3B3F95C3DC97C353065B0482302500BBB8051A5C090B596C6133","Balance":"10321199422233","Sequence":546}`
+	var account AccountRoot
+	c.Assert(json.Unmarshal([]byte(accountRoot), &account), IsNil)
+
+	ok, err := VerifyIndex(&account, *account.LedgerIndex)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+
+	wrong := *account.LedgerIndex
+	wrong[0] ^= 0xFF
+	ok, err = VerifyIndex(&account, wrong)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+
+	other, err := NewAccountFromAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Assert(err, IsNil)
+	account.Account = other
+	ok, err = VerifyIndex(&account, *account.LedgerIndex)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+
+	_, err = VerifyIndex(&SignerList{}, zero256)
+	c.Check(err, ErrorMatches, "Unknown LedgerEntry")
+}
+
+func (s *LedgerEntrySuite) TestDecodeLedgerEntry(c *C) {
+	// Captured from ledger_data
+	const (
+		accountRoot = `{"Account": "rKKzk9ghA2iuy3imqMXUHJqdRPMtNDGf4c", "Balance": "601382104", "Flags": 0, "LedgerEntryType": "AccountRoot", "OwnerCount": 0, "PreviousTxnID": "DB4DC693960DAB6030772C3B2CE976839EBD00F6C76EF9A00F9C8CBE91F006A5", "PreviousTxnLgrSeq": 6270804, "Sequence": 1, "index": "00001A2969BE1FC85F1D7A55282FA2E6D95C71D2E4B9C0FDD3D9994F3C00FF8F"}`
+		offer       = `{"Account": "rGryPmNWFognBgMtr9k4quqPbbEcCrhNmD", "BookDirectory": "71633D7DE1B6AEB32F87F1A73258B13FC8CC32942D53A66D4F038D7EA4C68000", "BookNode": "0000000000000000", "Flags": 0, "LedgerEntryType": "Offer", "OwnerNode": "0000000000000000", "PreviousTxnID": "555B93628BF3EC318892BB7C7CDCB6732FF53D12B6EEC4FAF60DD1AEE1C6101F", "PreviousTxnLgrSeq": 3504261, "Sequence": 3, "TakerGets": "1000000", "TakerPays": {"currency": "BTC", "issuer": "rnuF96W4SZoCJmbHYBFoJZpR8eCaxNvekK", "value": "1"}, "index": "000037C6659BB98F8D09F2F4CFEB27DE8EFEAFE54DD9E1C13AECDF5794B0C0F5"}`
+	)
+	le, err := DecodeLedgerEntry([]byte(accountRoot))
+	c.Assert(err, IsNil)
+	account, ok := le.(*AccountRoot)
+	c.Assert(ok, Equals, true)
+	c.Check(account.Account.String(), Equals, "rKKzk9ghA2iuy3imqMXUHJqdRPMtNDGf4c")
+	c.Check(account.Balance.String(), Equals, "601.382104")
+	c.Check(*account.Sequence, Equals, uint32(1))
+
+	le, err = DecodeLedgerEntry([]byte(offer))
+	c.Assert(err, IsNil)
+	o, ok := le.(*Offer)
+	c.Assert(ok, Equals, true)
+	c.Check(o.GetLedgerEntryType(), Equals, OFFER)
+	c.Check(o.TakerPays.String(), Equals, "1/BTC/rnuF96W4SZoCJmbHYBFoJZpR8eCaxNvekK")
+	c.Check(o.TakerGets.String(), Equals, "1/XRP")
+	c.Check(*o.Sequence, Equals, uint32(3))
+	for _, le := range []LedgerEntry{account, o} {
+		index, err := LedgerIndex(le)
+		c.Assert(err, IsNil)
+		c.Check(*index, Equals, *le.GetLedgerIndex())
+	}
+
+	_, err = DecodeLedgerEntry([]byte(`{"LedgerEntryType": "Bridge"}`))
+	c.Check(err, ErrorMatches, "Unknown LedgerEntryType: Bridge")
+	_, err = DecodeLedgerEntry([]byte(`{"LedgerEntryType": 96}`))
+	c.Check(err, NotNil)
+	_, err = DecodeLedgerEntry([]byte(`{"Account": "rKKzk9ghA2iuy3imqMXUHJqdRPMtNDGf4c"}`))
+	c.Check(err, ErrorMatches, "Missing LedgerEntryType")
+	_, err = GetLedgerEntryFactory(LedgerEntryType(0x7FFF))
+	c.Check(err, ErrorMatches, "Unknown LedgerEntryType: 0x7FFF")
+	factory, err := GetLedgerEntryFactory(RIPPLE_STATE)
+	c.Assert(err, IsNil)
+	c.Check(factory().GetLedgerEntryType(), Equals, RIPPLE_STATE)
+}
+
+func (s *LedgerEntrySuite) TestZeroOptionalHashRoundTrip(c *C) {
+	// The zero hashes of ledger entries are encoded, unlike those of transactions
+	const accountRoot = `{"Account": "rKKzk9ghA2iuy3imqMXUHJqdRPMtNDGf4c", "AccountTxnID": "0000000000000000000000000000000000000000000000000000000000000000", "Balance": "601382104", "Flags": 0, "LedgerEntryType": "AccountRoot", "OwnerCount": 0, "PreviousTxnID": "DB4DC693960DAB6030772C3B2CE976839EBD00F6C76EF9A00F9C8CBE91F006A5", "PreviousTxnLgrSeq": 6270804, "Sequence": 1, "index": "00001A2969BE1FC85F1D7A55282FA2E6D95C71D2E4B9C0FDD3D9994F3C00FF8F"}`
+	le, err := DecodeLedgerEntry([]byte(accountRoot))
+	c.Assert(err, IsNil)
+	_, raw, err := Raw(le)
+	c.Assert(err, IsNil)
+	decoded, err := ReadLedgerEntry(bytes.NewReader(raw), *le.GetLedgerIndex())
+	c.Assert(err, IsNil)
+	c.Assert(decoded.(*AccountRoot).AccountTxnID, NotNil)
+	c.Check(decoded.(*AccountRoot).AccountTxnID.IsZero(), Equals, true)
+	_, again, err := Raw(decoded)
+	c.Assert(err, IsNil)
+	c.Check(again, DeepEquals, raw)
+}
+
+func (s *LedgerEntrySuite) TestIndexNotEncoded(c *C) {
+	// rippled JSON always carries the index, which is not part of the entry
+	entries := map[string]bool{"AccountRoot": true, "Directory": true, "BookDirectory": true, "LedgerHashes": true, "Offer": true, "Ripple State": true, "Fee": true}
+	for _, test := range internal.Nodes {
+		if !entries[test.Description] {
+			continue
+		}
+		msg := Commentf(test.Description)
+		nodeId, err := NewHash256(test.NodeId())
+		c.Assert(err, IsNil)
+		n, err := ReadPrefix(test.Reader(), *nodeId)
+		c.Assert(err, IsNil, msg)
+		b, err := json.Marshal(n)
+		c.Assert(err, IsNil, msg)
+		var fields map[string]interface{}
+		c.Assert(json.Unmarshal(b, &fields), IsNil, msg)
+		fields["index"] = nodeId.String()
+		b, err = json.Marshal(fields)
+		c.Assert(err, IsNil, msg)
+		le, err := DecodeLedgerEntry(b)
+		c.Assert(err, IsNil, msg)
+		c.Assert(le.GetLedgerIndex(), NotNil, msg)
+		_, raw, err := Raw(le)
+		c.Assert(err, IsNil, msg)
+		// The node header is the ledger sequence twice, the node type and the hash prefix
+		c.Check(string(b2h(raw)), Equals, test.Encoded[26:], msg)
+		id, err := NodeId(le)
+		c.Assert(err, IsNil, msg)
+		c.Check(id.String(), Equals, nodeId.String(), msg)
+		delete(entries, test.Description)
+	}
+	c.Check(entries, HasLen, 0)
+}
@@ -3,6 +3,7 @@ package data
 import (
 	"fmt"
 	"github.com/willf/bitset"
+	"math"
 	"sort"
 	"time"
 )
@@ -20,12 +21,108 @@ type LedgerRange struct {
 	Max   uint32
 }
 
+// RangeByTime returns the ledgers of known which closed between start and
+// end inclusive. The bounds are found by bisection using closeTime, which
+// returns the close time of a ledger, as close times never decrease.
+// Times outside the close times of known are clamped to it.
+func RangeByTime(start, end time.Time, known LedgerRange, closeTime func(uint32) (time.Time, error)) (*LedgerRange, error) {
+	if end.Before(start) || known.End < known.Start {
+		return nil, fmt.Errorf("Bad time range: %s to %s", start, end)
+	}
+	first, err := searchLedgers(known, closeTime, func(t time.Time) bool { return !t.Before(start) })
+	if err != nil {
+		return nil, err
+	}
+	after, err := searchLedgers(known, closeTime, func(t time.Time) bool { return t.After(end) })
+	if err != nil {
+		return nil, err
+	}
+	if first >= after {
+		return nil, fmt.Errorf("No ledgers closed between %s and %s", start, end)
+	}
+	// A range of every ledger holds one more than Max can count
+	count := after - first
+	if count > math.MaxUint32 {
+		count = math.MaxUint32
+	}
+	return &LedgerRange{Start: uint32(first), End: uint32(after - 1), Max: uint32(count)}, nil
+}
+
+// searchLedgers returns the first ledger of r whose close time satisfies f,
+// or r.End+1 if there is none, which is why it is a uint64
+func searchLedgers(r LedgerRange, closeTime func(uint32) (time.Time, error), f func(time.Time) bool) (uint64, error) {
+	low, high := uint64(r.Start), uint64(r.End)+1
+	for low < high {
+		middle := low + (high-low)/2
+		t, err := closeTime(uint32(middle))
+		if err != nil {
+			return 0, err
+		}
+		if f(t) {
+			high = middle
+		} else {
+			low = middle + 1
+		}
+	}
+	return low, nil
+}
+
 type Work struct {
 	*LedgerRange
 	MissingLedgers LedgerSlice
 	MissingNodes   []Hash256
 }
 
+// PartitionStrategy controls how Work.Partition distributes ledgers and nodes
+type PartitionStrategy uint8
+
+const (
+	RoundRobin PartitionStrategy = iota // Item i goes to partition i%n
+	Contiguous                          // Each partition gets a contiguous block
+)
+
+// Partition splits the missing ledgers and nodes across at most n Works
+// which share the LedgerRange. No Work in the result is empty, so fewer
+// than n are returned when there is less work than consumers.
+func (w *Work) Partition(n int, strategy PartitionStrategy) []*Work {
+	size := len(w.MissingLedgers)
+	if len(w.MissingNodes) > size {
+		size = len(w.MissingNodes)
+	}
+	if n < 1 {
+		n = 1
+	}
+	if n > size {
+		n = size
+	}
+	work := make([]*Work, n)
+	for i := range work {
+		work[i] = &Work{LedgerRange: w.LedgerRange}
+	}
+	for i, ledger := range w.MissingLedgers {
+		p := work[partitionIndex(i, len(w.MissingLedgers), n, strategy)]
+		p.MissingLedgers = append(p.MissingLedgers, ledger)
+	}
+	for i, node := range w.MissingNodes {
+		p := work[partitionIndex(i, len(w.MissingNodes), n, strategy)]
+		p.MissingNodes = append(p.MissingNodes, node)
+	}
+	return work
+}
+
+// Returns the partition for item i of length items split n ways
+func partitionIndex(i, length, n int, strategy PartitionStrategy) int {
+	if strategy == RoundRobin {
+		return i % n
+	}
+	// The first length%n blocks hold one extra item
+	block, extra := length/n, length%n
+	if i < extra*(block+1) {
+		return i / (block + 1)
+	}
+	return extra + (i-extra*(block+1))/block
+}
+
 type LedgerSet struct {
 	ledgers  *bitset.BitSet
 	start    uint32
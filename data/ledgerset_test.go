@@ -1,6 +1,10 @@
 package data
 
 import (
+	"fmt"
+	"math"
+	"time"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -58,3 +62,111 @@ func (s *LedgerSetSuite) TestLedgerSetMiddle(c *C) {
 // 	}
 // 	fmt.Println(l.String())
 // }
+
+func checkPartition(c *C, work *Work, n int, strategy PartitionStrategy) []*Work {
+	partitions := work.Partition(n, strategy)
+	seen := make(map[uint32]bool)
+	nodes := make(map[Hash256]bool)
+	for _, p := range partitions {
+		c.Assert(p.LedgerRange, Equals, work.LedgerRange)
+		c.Assert(len(p.MissingLedgers)+len(p.MissingNodes) > 0, Equals, true)
+		for _, ledger := range p.MissingLedgers {
+			c.Assert(seen[ledger], Equals, false, Commentf("Duplicate ledger: %d", ledger))
+			seen[ledger] = true
+		}
+		for _, node := range p.MissingNodes {
+			c.Assert(nodes[node], Equals, false, Commentf("Duplicate node: %s", node))
+			nodes[node] = true
+		}
+	}
+	c.Assert(seen, HasLen, len(work.MissingLedgers))
+	c.Assert(nodes, HasLen, len(work.MissingNodes))
+	return partitions
+}
+
+func (s *LedgerSetSuite) TestWorkPartition(c *C) {
+	work := &Work{
+		LedgerRange:    &LedgerRange{Start: 100, End: 110, Max: 10},
+		MissingLedgers: LedgerSlice{100, 101, 102, 103, 104, 105, 106},
+		MissingNodes:   []Hash256{{1}, {2}, {3}},
+	}
+	roundRobin := checkPartition(c, work, 3, RoundRobin)
+	c.Assert(roundRobin, HasLen, 3)
+	c.Assert(roundRobin[0].MissingLedgers, DeepEquals, LedgerSlice{100, 103, 106})
+	c.Assert(roundRobin[1].MissingLedgers, DeepEquals, LedgerSlice{101, 104})
+	c.Assert(roundRobin[2].MissingNodes, DeepEquals, []Hash256{{3}})
+
+	contiguous := checkPartition(c, work, 3, Contiguous)
+	c.Assert(contiguous, HasLen, 3)
+	c.Assert(contiguous[0].MissingLedgers, DeepEquals, LedgerSlice{100, 101, 102})
+	c.Assert(contiguous[1].MissingLedgers, DeepEquals, LedgerSlice{103, 104})
+	c.Assert(contiguous[2].MissingLedgers, DeepEquals, LedgerSlice{105, 106})
+
+	for _, strategy := range []PartitionStrategy{RoundRobin, Contiguous} {
+		c.Assert(checkPartition(c, work, 20, strategy), HasLen, 7)
+		c.Assert(checkPartition(c, work, 0, strategy), HasLen, 1)
+		c.Assert(checkPartition(c, &Work{}, 4, strategy), HasLen, 0)
+	}
+}
+
+func (s *LedgerSetSuite) TestRangeByTime(c *C) {
+	// Ledgers 100 to 199 close every 10 seconds, with 150 and 151 together
+	epoch := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	known := LedgerRange{Start: 100, End: 199}
+	lookups := 0
+	closeTime := func(sequence uint32) (time.Time, error) {
+		lookups++
+		if sequence < known.Start || sequence > known.End {
+			return time.Time{}, fmt.Errorf("Unknown ledger: %d", sequence)
+		}
+		if sequence > 150 {
+			sequence--
+		}
+		return epoch.Add(time.Duration(sequence-100) * 10 * time.Second), nil
+	}
+	at := func(seconds int) time.Time { return epoch.Add(time.Duration(seconds) * time.Second) }
+	for _, t := range []struct {
+		Start, End int
+		Expected   LedgerRange
+	}{
+		{100, 200, LedgerRange{110, 120, 11}},
+		{101, 199, LedgerRange{111, 119, 9}},
+		{500, 500, LedgerRange{150, 151, 2}},
+		{0, 0, LedgerRange{100, 100, 1}},
+		{-100, 5, LedgerRange{100, 100, 1}},
+		{975, 5000, LedgerRange{199, 199, 1}},
+		{-1000, 5000, LedgerRange{100, 199, 100}},
+	} {
+		lookups = 0
+		r, err := RangeByTime(at(t.Start), at(t.End), known, closeTime)
+		c.Assert(err, IsNil)
+		c.Check(*r, Equals, t.Expected, Commentf("%d-%d", t.Start, t.End))
+		c.Check(lookups <= 16, Equals, true)
+	}
+	for _, t := range []struct {
+		Start, End int
+		Error      string
+	}{
+		{-100, -1, "No ledgers closed between .*"},
+		{981, 5000, "No ledgers closed between .*"},
+		{101, 109, "No ledgers closed between .*"},
+		{200, 100, "Bad time range: .*"},
+	} {
+		_, err := RangeByTime(at(t.Start), at(t.End), known, closeTime)
+		c.Check(err, ErrorMatches, t.Error, Commentf("%d-%d", t.Start, t.End))
+	}
+	_, err := RangeByTime(at(0), at(100), LedgerRange{Start: 100, End: 300}, closeTime)
+	c.Check(err, ErrorMatches, "Unknown ledger: .*")
+
+	// The last ledger does not wrap around
+	everyLedger := LedgerRange{Start: 0, End: math.MaxUint32}
+	byLedger := func(sequence uint32) (time.Time, error) { return at(int(sequence)), nil }
+	r, err := RangeByTime(at(math.MaxUint32-10), at(math.MaxUint32+10), everyLedger, byLedger)
+	c.Assert(err, IsNil)
+	c.Check(*r, Equals, LedgerRange{math.MaxUint32 - 10, math.MaxUint32, 11})
+	r, err = RangeByTime(at(-1), at(math.MaxUint32), everyLedger, byLedger)
+	c.Assert(err, IsNil)
+	c.Check(*r, Equals, LedgerRange{0, math.MaxUint32, math.MaxUint32})
+	_, err = RangeByTime(at(math.MaxUint32+1), at(math.MaxUint32+10), everyLedger, byLedger)
+	c.Check(err, ErrorMatches, "No ledgers closed between .*")
+}
@@ -1,11 +1,42 @@
 package data
 
+import "unicode/utf8"
+
 type Memo struct {
 	Memo struct {
-		MemoType   VariableLength
-		MemoData   VariableLength
-		MemoFormat VariableLength
+		MemoType   VariableLength `json:",omitempty"`
+		MemoData   VariableLength `json:",omitempty"`
+		MemoFormat VariableLength `json:",omitempty"`
 	}
 }
 
 type Memos []Memo
+
+// NewMemo creates a Memo from plain strings, empty strings are omitted
+func NewMemo(memoType, memoData, memoFormat string) Memo {
+	var m Memo
+	m.Memo.MemoType = memoField(memoType)
+	m.Memo.MemoData = memoField(memoData)
+	m.Memo.MemoFormat = memoField(memoFormat)
+	return m
+}
+
+func memoField(s string) VariableLength {
+	if len(s) == 0 {
+		return nil
+	}
+	return VariableLength(s)
+}
+
+func memoString(v VariableLength) (string, bool) {
+	return string(v), utf8.Valid(v)
+}
+
+// TypeString returns the MemoType as a string and whether it is valid UTF-8
+func (m Memo) TypeString() (string, bool) { return memoString(m.Memo.MemoType) }
+
+// DataString returns the MemoData as a string and whether it is valid UTF-8
+func (m Memo) DataString() (string, bool) { return memoString(m.Memo.MemoData) }
+
+// FormatString returns the MemoFormat as a string and whether it is valid UTF-8
+func (m Memo) FormatString() (string, bool) { return memoString(m.Memo.MemoFormat) }
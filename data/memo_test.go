@@ -0,0 +1,50 @@
+package data
+
+import (
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+)
+
+type MemoSuite struct{}
+
+var _ = Suite(&MemoSuite{})
+
+func (s *MemoSuite) TestMemoStrings(c *C) {
+	memo := NewMemo("text/plain", "héllo wörld", "")
+	typ, ok := memo.TypeString()
+	c.Assert(typ, Equals, "text/plain")
+	c.Assert(ok, Equals, true)
+	data, ok := memo.DataString()
+	c.Assert(data, Equals, "héllo wörld")
+	c.Assert(ok, Equals, true)
+	format, ok := memo.FormatString()
+	c.Assert(format, Equals, "")
+	c.Assert(ok, Equals, true)
+
+	memo.Memo.MemoData = VariableLength{0xFF, 0xFE, 0x00}
+	_, ok = memo.DataString()
+	c.Assert(ok, Equals, false)
+}
+
+func (s *MemoSuite) TestMemoJSON(c *C) {
+	var binary Memo
+	binary.Memo.MemoData = VariableLength{0xFF, 0x00, 0x01}
+	tests := []struct {
+		memo Memo
+		json string
+	}{
+		{NewMemo("text/plain", "héllo", ""), `{"Memo":{"MemoType":"746578742F706C61696E","MemoData":"68C3A96C6C6F"}}`},
+		{binary, `{"Memo":{"MemoData":"FF0001"}}`},
+		{Memo{}, `{"Memo":{}}`},
+	}
+
+	for _, test := range tests {
+		b, err := json.Marshal(test.memo)
+		c.Assert(err, IsNil)
+		c.Assert(string(b), Equals, test.json)
+		var memo Memo
+		c.Assert(json.Unmarshal(b, &memo), IsNil)
+		c.Assert(memo, DeepEquals, test.memo)
+	}
+}
@@ -36,6 +36,19 @@ type MetaData struct {
 	TransactionIndex  uint32
 	TransactionResult TransactionResult
 	DeliveredAmount   *Amount `json:"delivered_amount,omitempty"`
+	// rippled reports "unavailable" for partial payments made before
+	// the delivered amount was recorded
+	deliveredUnavailable bool
+}
+
+// Delivered returns the amount delivered by a payment and whether it is
+// known, which it is not for failed transactions, non-payments and
+// partial payments from before DeliveredAmount was added to metadata
+func (m *MetaData) Delivered() (*Amount, bool) {
+	if m.DeliveredAmount == nil || m.deliveredUnavailable {
+		return nil, false
+	}
+	return m.DeliveredAmount, true
 }
 
 type TransactionSlice []*TransactionWithMetaData
@@ -104,3 +117,108 @@ func (effect *NodeEffect) AffectedNode() (*AffectedNode, LedgerEntry, LedgerEntr
 	}
 	return node, final, previous, state
 }
+
+// State returns whether the node was created, modified or deleted
+func (effect *NodeEffect) State() LedgerEntryState {
+	switch {
+	case effect.CreatedNode != nil:
+		return Created
+	case effect.DeletedNode != nil:
+		return Deleted
+	default:
+		return Modified
+	}
+}
+
+func (m *MetaData) filter(state LedgerEntryState) NodeEffects {
+	var effects NodeEffects
+	for _, effect := range m.AffectedNodes {
+		if effect.State() == state {
+			effects = append(effects, effect)
+		}
+	}
+	return effects
+}
+
+// Created returns the ledger entries created by the transaction in
+// metadata order. Use AffectedNode for the typed fields of each.
+func (m *MetaData) Created() NodeEffects { return m.filter(Created) }
+
+// Modified returns the ledger entries modified by the transaction
+func (m *MetaData) Modified() NodeEffects { return m.filter(Modified) }
+
+// Deleted returns the ledger entries deleted by the transaction
+func (m *MetaData) Deleted() NodeEffects { return m.filter(Deleted) }
+
+type issuerSet map[Account]struct{}
+
+func (s issuerSet) add(amounts ...*Amount) {
+	for _, amount := range amounts {
+		if amount != nil && amount.Value != nil && !amount.IsNative() && !amount.Issuer.IsZero() {
+			s[amount.Issuer] = struct{}{}
+		}
+	}
+}
+
+func (s issuerSet) addPaths(paths PathSet) {
+	for _, path := range paths {
+		for _, elem := range path {
+			if elem.Issuer != nil && !elem.Issuer.IsZero() {
+				s[*elem.Issuer] = struct{}{}
+			}
+		}
+	}
+}
+
+func (s issuerSet) addLedgerEntry(le LedgerEntry) {
+	switch v := le.(type) {
+	case *Offer:
+		s.add(v.TakerPays, v.TakerGets)
+	case *RippleState:
+		// Balance has a neutral issuer, the side which owes is the issuer
+		if v.Balance == nil || v.Balance.Value == nil || v.Balance.IsZero() {
+			return
+		}
+		if v.Balance.IsNegative() {
+			s.add(v.LowLimit)
+		} else {
+			s.add(v.HighLimit)
+		}
+	case *Check:
+		s.add(v.SendMax)
+	}
+}
+
+// Issuers returns the sorted set of issuers referenced by the amounts
+// and paths of the transaction and the ledger entries in its metadata.
+func (txm *TransactionWithMetaData) Issuers() []Account {
+	issuers := make(issuerSet)
+	switch tx := txm.Transaction.(type) {
+	case *Payment:
+		issuers.add(&tx.Amount, tx.SendMax, tx.DeliverMin)
+	case *OfferCreate:
+		issuers.add(&tx.TakerPays, &tx.TakerGets)
+	case *TrustSet:
+		issuers.add(&tx.LimitAmount)
+	case *CheckCreate:
+		issuers.add(&tx.SendMax)
+	case *CheckCash:
+		issuers.add(tx.Amount, tx.DeliverMin)
+	case *Clawback:
+		// The issuer of Amount is the holder
+		issuers[tx.Account] = struct{}{}
+	}
+	issuers.addPaths(txm.PathSet())
+	issuers.add(txm.MetaData.DeliveredAmount)
+	for _, effect := range txm.MetaData.AffectedNodes {
+		_, final, previous, _ := effect.AffectedNode()
+		issuers.addLedgerEntry(final)
+		issuers.addLedgerEntry(previous)
+	}
+	var accounts []Account
+	for issuer := range issuers {
+		accounts = append(accounts, issuer)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Less(accounts[j]) })
+	return accounts
+}
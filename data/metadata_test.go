@@ -0,0 +1,286 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+type MetaDataSuite struct{}
+
+var _ = Suite(&MetaDataSuite{})
+
+func readTransactionWithMetaData(c *C, filename string) *TransactionWithMetaData {
+	b, err := ioutil.ReadFile(filename)
+	c.Assert(err, IsNil)
+	var txm TransactionWithMetaData
+	c.Assert(json.Unmarshal(b, &txm), IsNil)
+	return &txm
+}
+
+func (s *MetaDataSuite) TestIssuers(c *C) {
+	txm := readTransactionWithMetaData(c, "testdata/transaction_payment_with_rippling.json")
+	var issuers []string
+	for _, issuer := range txm.Issuers() {
+		issuers = append(issuers, issuer.String())
+	}
+	c.Assert(issuers, DeepEquals, []string{
+		"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B",  // Amount
+		"rpDMez6pm6dBve2TJsmDpv7Yae6V5Pyvy2", // Rippled through
+		"rnziParaNb8nsU4aruQdwYE3j5jUcqjzFm", // Rippled through
+		"rGgj3GurcrAqgBXGVoS9wvQG3Hjkj5oCbj", // SendMax
+		"rMwjYedjc7qqtKYVLiAccJSmCwih4LnE2q", // Paths
+	})
+
+	txm = readTransactionWithMetaData(c, "testdata/transaction_account_set.json")
+	c.Assert(txm.Issuers(), HasLen, 0)
+}
+
+// A constructed payment which consumes an offer, creates a trust line
+// and debits the sender
+const paymentWithAllNodeKinds = `{
+	"TransactionType": "Payment",
+	"Account": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+	"Destination": "rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK",
+	"Amount": {"currency": "USD", "issuer": "rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK", "value": "10"},
+	"SendMax": "20000000",
+	"Fee": "12",
+	"Sequence": 7,
+	"hash": "1B0A5C2EE589FB0E99F79F8B2F2AB804D38C2E8B473FD7F4FD958788B3FA5A6F",
+	"meta": {
+		"AffectedNodes": [
+			{"CreatedNode": {
+				"LedgerEntryType": "RippleState",
+				"LedgerIndex": "707A646A7846185F1294E3AF0522457418BBEC1DFBF4704434BFC2ABAA01F2BF",
+				"NewFields": {
+					"Balance": {"currency": "USD", "issuer": "rrrrrrrrrrrrrrrrrrrrBZbvji", "value": "10"},
+					"HighLimit": {"currency": "USD", "issuer": "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B", "value": "0"},
+					"LowLimit": {"currency": "USD", "issuer": "rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK", "value": "100"}
+				}
+			}},
+			{"ModifiedNode": {
+				"LedgerEntryType": "AccountRoot",
+				"LedgerIndex": "2B6AC232AA4C4BE41BF49D2459FA4A0347E1B543A4C92FCEE0821C0201E2E9A8",
+				"FinalFields": {"Account": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "Balance": "99979999988", "Flags": 0, "OwnerCount": 0, "Sequence": 8},
+				"PreviousFields": {"Balance": "100000000000", "Sequence": 7},
+				"PreviousTxnID": "D3C0C5E2A6A2FF56C0F0D3EF8E3A76D3E1E83D7D7D2F1F0B3D0B1D0E4E3C2B1A",
+				"PreviousTxnLgrSeq": 1000
+			}},
+			{"DeletedNode": {
+				"LedgerEntryType": "Offer",
+				"LedgerIndex": "531369675187BF584F1BDCD7CFCC16965BD1DE35F4B9B1EDB95539FAFE4D9FB9",
+				"FinalFields": {
+					"Account": "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B",
+					"BookDirectory": "4627DFFCFF8B5A265EDBD8AE8C14A52325DBFEDAF4F5C32E5D038D7EA4C68000",
+					"BookNode": "0000000000000000",
+					"Flags": 0,
+					"OwnerNode": "0000000000000000",
+					"Sequence": 5,
+					"TakerGets": {"currency": "USD", "issuer": "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B", "value": "0"},
+					"TakerPays": "0"
+				},
+				"PreviousFields": {
+					"TakerGets": {"currency": "USD", "issuer": "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B", "value": "10"},
+					"TakerPays": "20000000"
+				}
+			}}
+		],
+		"TransactionIndex": 0,
+		"TransactionResult": "tesSUCCESS"
+	}
+}`
+
+func (s *MetaDataSuite) TestNodeStates(c *C) {
+	var txm TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(paymentWithAllNodeKinds), &txm), IsNil)
+	meta := &txm.MetaData
+	for _, t := range []struct {
+		Effects NodeEffects
+		State   LedgerEntryState
+		Type    LedgerEntryType
+	}{
+		{meta.Created(), Created, RIPPLE_STATE},
+		{meta.Modified(), Modified, ACCOUNT_ROOT},
+		{meta.Deleted(), Deleted, OFFER},
+	} {
+		c.Assert(t.Effects, HasLen, 1)
+		node, final, previous, state := t.Effects[0].AffectedNode()
+		c.Check(state, Equals, t.State)
+		c.Check(t.Effects[0].State(), Equals, t.State)
+		c.Check(node.LedgerEntryType, Equals, t.Type)
+		c.Check(final.GetLedgerEntryType(), Equals, t.Type)
+		c.Check(previous.GetLedgerEntryType(), Equals, t.Type)
+		ok, err := VerifyIndex(final, *node.LedgerIndex)
+		c.Assert(err, IsNil)
+		c.Check(ok, Equals, true, Commentf("%s", t.Type))
+	}
+
+	_, final, previous, _ := meta.Modified()[0].AffectedNode()
+	c.Check(previous.(*AccountRoot).Balance.String(), Equals, "100000")
+	c.Check(final.(*AccountRoot).Balance.String(), Equals, "99979.999988")
+
+	_, final, previous, _ = meta.Deleted()[0].AffectedNode()
+	c.Check(final.(*Offer).TakerGets.IsZero(), Equals, true)
+	c.Check(previous.(*Offer).TakerPays.String(), Equals, "20/XRP")
+
+	var empty MetaData
+	c.Check(empty.Created(), HasLen, 0)
+}
+
+func balanceChanges(c *C, txm *TransactionWithMetaData) map[string][]string {
+	changes, err := txm.BalanceChanges()
+	c.Assert(err, IsNil)
+	result := make(map[string][]string)
+	for account, amounts := range changes {
+		for _, amount := range amounts {
+			result[account.String()] = append(result[account.String()], amount.String())
+		}
+	}
+	return result
+}
+
+func (s *MetaDataSuite) TestBalanceChanges(c *C) {
+	txm := readTransactionWithMetaData(c, "testdata/transaction_payment_with_rippling.json")
+	c.Check(balanceChanges(c, txm), DeepEquals, map[string][]string{
+		"rGgj3GurcrAqgBXGVoS9wvQG3Hjkj5oCbj": {"-0.52717390895/USD/rpDMez6pm6dBve2TJsmDpv7Yae6V5Pyvy2", "-0.000012/XRP", "-19.515000003766/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"},
+		"rpDMez6pm6dBve2TJsmDpv7Yae6V5Pyvy2": {"0.52717390895/USD/rGgj3GurcrAqgBXGVoS9wvQG3Hjkj5oCbj", "-0.52717390895/USD/rnziParaNb8nsU4aruQdwYE3j5jUcqjzFm"},
+		"rnziParaNb8nsU4aruQdwYE3j5jUcqjzFm": {"0.52717390895/USD/rpDMez6pm6dBve2TJsmDpv7Yae6V5Pyvy2", "-0.484999996234/USD/r3v6QzgkBq9hM75XGThKS1NM9gchcTrBHL"},
+		"r3v6QzgkBq9hM75XGThKS1NM9gchcTrBHL": {"-0.484999996234/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B", "0.484999996234/USD/rnziParaNb8nsU4aruQdwYE3j5jUcqjzFm"},
+		"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B":  {"0.484999996234/USD/r3v6QzgkBq9hM75XGThKS1NM9gchcTrBHL", "19.515000003766/USD/rGgj3GurcrAqgBXGVoS9wvQG3Hjkj5oCbj"},
+	})
+
+	// Crosses ten offers and creates a trust line for the taker
+	txm = readTransactionWithMetaData(c, "testdata/transaction_offercreate.json")
+	changes := balanceChanges(c, txm)
+	c.Check(changes, HasLen, 10)
+	c.Check(changes["rhQ69TqAvwqcQRrjE1t5D8CFRczrgaPXiz"], DeepEquals, []string{"8/BTC/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B", "-516418.508798/XRP"})
+	c.Check(changes["rNAAy9xnjuU6McAjVFtMyFbDNKzTXQ9wbV"], DeepEquals, []string{"-0.1692520000000001/BTC/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B", "10810.169158/XRP"})
+	c.Check(changes["rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"], HasLen, 9)
+
+	var constructed TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(paymentWithAllNodeKinds), &constructed), IsNil)
+	c.Check(balanceChanges(c, &constructed), DeepEquals, map[string][]string{
+		"rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK": {"10/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"},
+		"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B":  {"-10/USD/rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK"},
+		"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh": {"-20.000012/XRP"},
+	})
+}
+
+func (s *MetaDataSuite) TestBalanceSummary(c *C) {
+	txm := readTransactionWithMetaData(c, "testdata/transaction_payment_with_rippling.json")
+	summary, err := txm.BalanceSummary()
+	c.Assert(err, IsNil)
+	c.Check(summary.Fee.String(), Equals, "0.000012/XRP")
+	c.Assert(summary.Delivered, NotNil)
+	c.Check(summary.Delivered.String(), Equals, txm.Transaction.(*Payment).Amount.String())
+	sender := txm.GetBase().Account
+	usd, err := NewAsset("USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(err, IsNil)
+	c.Check(summary.Change(sender, usd).String(), Equals, "-19.515000003766/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Check(summary.Change(sender, &Asset{Currency: "XRP"}).String(), Equals, "-0.000012/XRP")
+	c.Check(summary.Change(txm.Transaction.(*Payment).Destination, &Asset{Currency: "XRP"}), IsNil)
+
+	var constructed TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(paymentWithAllNodeKinds), &constructed), IsNil)
+	partial := TxPartialPayment
+	constructed.GetBase().Flags = &partial
+	summary, err = constructed.BalanceSummary()
+	c.Assert(err, IsNil)
+	c.Check(summary.Delivered, IsNil)
+
+	txm = readTransactionWithMetaData(c, "testdata/transaction_offercreate.json")
+	summary, err = txm.BalanceSummary()
+	c.Assert(err, IsNil)
+	c.Check(summary.Fee.String(), Equals, "0.000015/XRP")
+	c.Check(summary.Delivered, IsNil)
+}
+
+func (s *MetaDataSuite) TestDelivered(c *C) {
+	const prefix = `{"AffectedNodes":[],"TransactionIndex":3,"TransactionResult":"tesSUCCESS"`
+	for _, t := range []struct {
+		JSON      string
+		Delivered string
+		Available bool
+		Encoded   string
+	}{
+		{prefix + `,"delivered_amount":"1000"}`, "0.001/XRP", true, `"delivered_amount":"1000"`},
+		{prefix + `,"DeliveredAmount":{"currency":"USD","issuer":"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B","value":"12.5"}}`, "12.5/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B", true, `"delivered_amount":{"value":"12.5","currency":"USD","issuer":"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"}`},
+		{prefix + `,"delivered_amount":"unavailable"}`, "", false, `"delivered_amount":"unavailable"`},
+		{prefix + `}`, "", false, ``},
+	} {
+		var meta MetaData
+		c.Assert(json.Unmarshal([]byte(t.JSON), &meta), IsNil, Commentf(t.JSON))
+		c.Check(meta.TransactionIndex, Equals, uint32(3))
+		delivered, ok := meta.Delivered()
+		c.Check(ok, Equals, t.Available, Commentf(t.JSON))
+		if ok {
+			c.Check(delivered.String(), Equals, t.Delivered)
+		}
+		b, err := json.Marshal(meta)
+		c.Assert(err, IsNil)
+		c.Check(strings.Contains(string(b), t.Encoded), Equals, true, Commentf(string(b)))
+		c.Check(strings.Contains(string(b), "DeliveredAmount"), Equals, false)
+		var again MetaData
+		c.Assert(json.Unmarshal(b, &again), IsNil)
+		c.Check(again, DeepEquals, meta)
+	}
+}
+
+// A constructed clawback of 314 FOO issued by rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8
+func (s *MetaDataSuite) TestClawback(c *C) {
+	var txm TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(`{
+	"TransactionType": "Clawback",
+	"Account": "rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8",
+	"Amount": {"currency": "FOO", "issuer": "rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de", "value": "314"},
+	"Fee": "10",
+	"Sequence": 5,
+	"meta": {
+		"AffectedNodes": [
+			{"ModifiedNode": {
+				"LedgerEntryType": "RippleState",
+				"LedgerIndex": "C2B8D9B6B4D8F7D1FA0B2B3B49D5C14B572C2B1D7D2C536F0492E58A0A3A1E3E",
+				"FinalFields": {
+					"Balance": {"currency": "FOO", "issuer": "rrrrrrrrrrrrrrrrrrrrBZbvji", "value": "686"},
+					"Flags": 131072,
+					"HighLimit": {"currency": "FOO", "issuer": "rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8", "value": "0"},
+					"LowLimit": {"currency": "FOO", "issuer": "rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de", "value": "1000"}
+				},
+				"PreviousFields": {
+					"Balance": {"currency": "FOO", "issuer": "rrrrrrrrrrrrrrrrrrrrBZbvji", "value": "1000"}
+				}
+			}}
+		],
+		"TransactionIndex": 0,
+		"TransactionResult": "tesSUCCESS"
+	}
+}`), &txm), IsNil)
+	clawback, ok := txm.Transaction.(*Clawback)
+	c.Assert(ok, Equals, true)
+	c.Check(clawback.Holder().String(), Equals, "rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de")
+	c.Assert(txm.Issuers(), HasLen, 1)
+	c.Check(txm.Issuers()[0].String(), Equals, "rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8")
+	c.Check(balanceChanges(c, &txm), DeepEquals, map[string][]string{
+		"rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de": {"-314/FOO/rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8"},
+		"rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8": {"314/FOO/rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de"},
+	})
+	balances, err := txm.Balances()
+	c.Assert(err, IsNil)
+	c.Check(balances, HasLen, 2)
+	_, final, _, _ := txm.MetaData.AffectedNodes[0].AffectedNode()
+	state := final.(*RippleState)
+	c.Check(state.Flags.Explain(state), DeepEquals, []string{"HighReserve"})
+
+	_, raw, err := Raw(clawback)
+	c.Assert(err, IsNil)
+	decoded, err := ReadTransaction(bytes.NewReader(raw))
+	c.Assert(err, IsNil)
+	c.Check(decoded, DeepEquals, txm.Transaction)
+
+	account := AccountRoot{Flags: new(LedgerEntryFlag)}
+	*account.Flags = LsAllowTrustLineClawback
+	c.Check(account.AllowTrustLineClawback(), Equals, true)
+}
@@ -0,0 +1,47 @@
+package data
+
+import "encoding/binary"
+
+// NFTokenID is the decoded form of the 256-bit id of a non-fungible token:
+// flags (16 bits), transfer fee (16), issuer (160), scrambled taxon (32)
+// and the issuer's mint sequence (32).
+type NFTokenID struct {
+	Flags       uint16
+	TransferFee uint16
+	Issuer      Account
+	Taxon       uint32
+	Sequence    uint32
+}
+
+// The taxon is stored xored with a function of the sequence, so that the
+// tokens of one taxon do not sort together into the same pages
+func nftokenTaxonMask(sequence uint32) uint32 {
+	return 384160001*sequence + 2459
+}
+
+// NewNFTokenID decodes the fields packed into a token id
+func NewNFTokenID(h Hash256) NFTokenID {
+	id := NFTokenID{
+		Flags:       binary.BigEndian.Uint16(h[0:2]),
+		TransferFee: binary.BigEndian.Uint16(h[2:4]),
+		Sequence:    binary.BigEndian.Uint32(h[28:32]),
+	}
+	copy(id.Issuer[:], h[4:24])
+	id.Taxon = binary.BigEndian.Uint32(h[24:28]) ^ nftokenTaxonMask(id.Sequence)
+	return id
+}
+
+// Hash returns the token id which NewNFTokenID decodes back into id
+func (id NFTokenID) Hash() Hash256 {
+	var h Hash256
+	binary.BigEndian.PutUint16(h[0:2], id.Flags)
+	binary.BigEndian.PutUint16(h[2:4], id.TransferFee)
+	copy(h[4:24], id.Issuer[:])
+	binary.BigEndian.PutUint32(h[24:28], id.Taxon^nftokenTaxonMask(id.Sequence))
+	binary.BigEndian.PutUint32(h[28:32], id.Sequence)
+	return h
+}
+
+func (id NFTokenID) String() string {
+	return id.Hash().String()
+}
@@ -0,0 +1,68 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	. "gopkg.in/check.v1"
+)
+
+type NFTokenSuite struct{}
+
+var _ = Suite(&NFTokenSuite{})
+
+func (s *NFTokenSuite) TestNFTokenID(c *C) {
+	for _, test := range []struct {
+		id          string
+		flags       uint16
+		transferFee uint16
+		issuer      string
+		taxon       uint32
+		sequence    uint32
+	}{
+		{"000B0539C35B55AA096BA6D87A6E6C965A6534150DC56E5E12C5D09E0000000C", 11, 1337, "rJoxBSzpXhPtAuqFmqxQtGKjA13jUJWthE", 1337, 12},
+		{"000100001E962F495F07A990F4ED55ACCFEEF365DBAA76B6A048C0A200000007", 1, 0, "rs8jBmmfpwgmrSPgwMsh7CvKRmRt1JTVSX", 0, 7},
+	} {
+		hash, err := NewHash256(test.id)
+		c.Assert(err, IsNil)
+		id := NewNFTokenID(*hash)
+		c.Check(id.Flags, Equals, test.flags, Commentf(test.id))
+		c.Check(id.TransferFee, Equals, test.transferFee, Commentf(test.id))
+		c.Check(id.Issuer.String(), Equals, test.issuer, Commentf(test.id))
+		c.Check(id.Taxon, Equals, test.taxon, Commentf(test.id))
+		c.Check(id.Sequence, Equals, test.sequence, Commentf(test.id))
+		c.Check(id.Hash(), Equals, *hash)
+		c.Check(id.String(), Equals, test.id)
+	}
+}
+
+func (s *NFTokenSuite) TestNFTokenLedgerEntries(c *C) {
+	le, err := DecodeLedgerEntry([]byte(`{"LedgerEntryType":"NFTokenPage","Flags":0,"NFTokens":[{"NFToken":{"NFTokenID":"000B0539C35B55AA096BA6D87A6E6C965A6534150DC56E5E12C5D09E0000000C","URI":"697066733A2F2F"}}],"PreviousPageMin":"C35B55AA096BA6D87A6E6C965A6534150DC56E5E00000000000000000000000B","PreviousTxnID":"95C8761B22894E328646F7A70035E9DFBECC90EDD83E43B7B973F626D21A0822","PreviousTxnLgrSeq":42891441,"index":"C35B55AA096BA6D87A6E6C965A6534150DC56E5EFFFFFFFFFFFFFFFFFFFFFFFF"}`))
+	c.Assert(err, IsNil)
+	page, ok := le.(*NFTokenPage)
+	c.Assert(ok, Equals, true)
+	c.Assert(page.NFTokens, HasLen, 1)
+	c.Check(string(*page.NFTokens[0].NFToken.URI), Equals, "ipfs://")
+	issuer, err := NewAccountFromAddress("rJoxBSzpXhPtAuqFmqxQtGKjA13jUJWthE")
+	c.Assert(err, IsNil)
+	c.Check(page.Affects(*issuer), Equals, true)
+	c.Check(NewNFTokenID(page.NFTokens[0].NFToken.NFTokenID).Issuer, Equals, *issuer)
+
+	le, err = DecodeLedgerEntry([]byte(`{"LedgerEntryType":"NFTokenOffer","Amount":"1000000","Flags":1,"NFTokenID":"000B0539C35B55AA096BA6D87A6E6C965A6534150DC56E5E12C5D09E0000000C","NFTokenOfferNode":"0000000000000000","Owner":"rJoxBSzpXhPtAuqFmqxQtGKjA13jUJWthE","OwnerNode":"0000000000000000","PreviousTxnID":"BFA9BE27383FA315651E26FDE1FA30815C5A5D0544EE10EC33D3E92532993769","PreviousTxnLgrSeq":75443565,"index":"AEBABA4FAC212BF28E0F9A9C7A2BE8CA5032391C4EDA43E3E3A14FF8B786E8D2"}`))
+	c.Assert(err, IsNil)
+	offer, ok := le.(*NFTokenOffer)
+	c.Assert(ok, Equals, true)
+	c.Check(offer.Affects(*issuer), Equals, true)
+	c.Check(offer.Flags.Explain(offer), DeepEquals, []string{"SellNFToken"})
+
+	// The metadata of the mint creating the page
+	var meta MetaData
+	c.Assert(json.Unmarshal([]byte(`{"AffectedNodes":[{"CreatedNode":{"LedgerEntryType":"NFTokenPage","LedgerIndex":"C35B55AA096BA6D87A6E6C965A6534150DC56E5EFFFFFFFFFFFFFFFFFFFFFFFF","NewFields":{"NFTokens":[{"NFToken":{"NFTokenID":"000B0539C35B55AA096BA6D87A6E6C965A6534150DC56E5E12C5D09E0000000C","URI":"697066733A2F2F"}}]}}}],"TransactionIndex":0,"TransactionResult":"tesSUCCESS"}`), &meta), IsNil)
+	var raw bytes.Buffer
+	c.Assert(encode(&raw, &meta, false), IsNil)
+	var decoded MetaData
+	v := reflect.ValueOf(&decoded)
+	c.Assert(readObject(bytes.NewReader(raw.Bytes()), &v), IsNil)
+	c.Assert(decoded.AffectedNodes[0].CreatedNode.NewFields, DeepEquals, meta.AffectedNodes[0].CreatedNode.NewFields)
+}
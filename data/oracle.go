@@ -0,0 +1,90 @@
+package data
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// The trim is a percentage removed from each end of the sorted prices
+const OracleMaxTrim uint32 = 25
+
+// AggregatePrice summarises the prices reported by a set of oracles for
+// one asset pair, as returned by rippled's get_aggregate_price.
+type AggregatePrice struct {
+	Mean        Value
+	Median      Value
+	Size        int
+	TrimmedMean *Value // nil unless a trim was requested
+	TrimmedSize int
+}
+
+// Price returns the price of base in quote reported by the oracle
+// and whether the oracle reports one
+func (o Oracle) Price(base, quote Currency) (*big.Rat, bool) {
+	for _, entry := range o.PriceDataSeries {
+		p := entry.PriceData
+		if !p.BaseAsset.Equals(base) || !p.QuoteAsset.Equals(quote) || p.AssetPrice == nil {
+			continue
+		}
+		price := new(big.Rat).SetInt(new(big.Int).SetUint64(uint64(*p.AssetPrice)))
+		if p.Scale != nil {
+			scale := new(big.Int).Exp(bigTen, big.NewInt(int64(*p.Scale)), nil)
+			price.Quo(price, new(big.Rat).SetInt(scale))
+		}
+		return price, true
+	}
+	return nil, false
+}
+
+// AggregateOraclePrices computes the mean and median of the prices of base
+// in quote across the oracles which report one. A non-zero trim, between 1
+// and OracleMaxTrim, also computes the mean after removing trim percent of
+// the prices from each end. Oracles without the pair are ignored.
+func AggregateOraclePrices(oracles []Oracle, base, quote Currency, trim uint32) (*AggregatePrice, error) {
+	if trim > OracleMaxTrim {
+		return nil, fmt.Errorf("Oracle trim out of range: %d", trim)
+	}
+	var prices []*big.Rat
+	for _, oracle := range oracles {
+		if price, ok := oracle.Price(base, quote); ok {
+			prices = append(prices, price)
+		}
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("No oracle prices for %s/%s", base, quote)
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+	mean, err := oracleMean(prices)
+	if err != nil {
+		return nil, err
+	}
+	middle := len(prices) / 2
+	median := new(big.Rat).Set(prices[middle])
+	if len(prices)%2 == 0 {
+		median.Add(median, prices[middle-1])
+		median.Quo(median, big.NewRat(2, 1))
+	}
+	medianValue, err := newValueFromRat(median, false)
+	if err != nil {
+		return nil, err
+	}
+	aggregate := &AggregatePrice{Mean: *mean, Median: *medianValue, Size: len(prices)}
+	if trim > 0 {
+		cut := len(prices) * int(trim) / 100
+		trimmed := prices[cut : len(prices)-cut]
+		if aggregate.TrimmedMean, err = oracleMean(trimmed); err != nil {
+			return nil, err
+		}
+		aggregate.TrimmedSize = len(trimmed)
+	}
+	return aggregate, nil
+}
+
+func oracleMean(prices []*big.Rat) (*Value, error) {
+	sum := new(big.Rat)
+	for _, price := range prices {
+		sum.Add(sum, price)
+	}
+	return newValueFromRat(sum.Quo(sum, big.NewRat(int64(len(prices)), 1)), false)
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	. "gopkg.in/check.v1"
+)
+
+type OracleSuite struct{}
+
+var _ = Suite(&OracleSuite{})
+
+// oracleCheck makes an Oracle reporting an XRP/USD price, which is removed
+// when price is empty, and an unrelated XRP/EUR price
+func oracleCheck(c *C, price string) Oracle {
+	usd := `{"PriceData": {"BaseAsset": "XRP", "QuoteAsset": "USD"}}`
+	if price != "" {
+		usd = fmt.Sprintf(`{"PriceData": {"BaseAsset": "XRP", "QuoteAsset": "USD", "AssetPrice": "%s", "Scale": 3}}`, price)
+	}
+	var oracle Oracle
+	c.Assert(json.Unmarshal([]byte(`{
+		"LedgerEntryType": "Oracle",
+		"Owner": "rNZ9m6AP9K7z3EVg6GhPMx36V4QmZKeWds",
+		"Provider": "70726F7669646572",
+		"AssetClass": "63757272656E6379",
+		"LastUpdateTime": 1724871860,
+		"PriceDataSeries": [
+			{"PriceData": {"BaseAsset": "XRP", "QuoteAsset": "EUR", "AssetPrice": "1"}},
+			`+usd+`
+		]
+	}`), &oracle), IsNil)
+	return oracle
+}
+
+func currencyCheck(c *C, s string) Currency {
+	currency, err := NewCurrency(s)
+	c.Assert(err, IsNil)
+	return currency
+}
+
+func (s *OracleSuite) TestAggregateOraclePrices(c *C) {
+	xrp, usd := zeroCurrency, currencyCheck(c, "USD")
+	// 0.740, 0.750, 0.762, 0.755 and 0.900
+	oracles := []Oracle{
+		oracleCheck(c, "2E4"),
+		oracleCheck(c, "2EE"),
+		oracleCheck(c, "2FA"),
+		oracleCheck(c, ""),
+		oracleCheck(c, "2F3"),
+		oracleCheck(c, "384"),
+	}
+	aggregate, err := AggregateOraclePrices(oracles, xrp, usd, 20)
+	c.Assert(err, IsNil)
+	c.Check(aggregate.Size, Equals, 5)
+	c.Check(aggregate.Mean.String(), Equals, "0.7814")
+	c.Check(aggregate.Median.String(), Equals, "0.755")
+	c.Check(aggregate.TrimmedSize, Equals, 3)
+	c.Check(aggregate.TrimmedMean.String(), Equals, "0.7556666666666666")
+
+	aggregate, err = AggregateOraclePrices(oracles[:5], xrp, usd, 0)
+	c.Assert(err, IsNil)
+	c.Check(aggregate.Size, Equals, 4)
+	c.Check(aggregate.Median.String(), Equals, "0.7525")
+	c.Check(aggregate.TrimmedMean, IsNil)
+
+	aggregate, err = AggregateOraclePrices(oracles, xrp, currencyCheck(c, "EUR"), 25)
+	c.Assert(err, IsNil)
+	c.Check(aggregate.Mean.String(), Equals, "1")
+	c.Check(aggregate.TrimmedSize, Equals, 4)
+
+	_, err = AggregateOraclePrices(oracles, xrp, currencyCheck(c, "BTC"), 0)
+	c.Check(err, ErrorMatches, "No oracle prices for XRP/BTC")
+	_, err = AggregateOraclePrices(oracles, xrp, usd, 26)
+	c.Check(err, ErrorMatches, "Oracle trim out of range: 26")
+}
+
+func (s *OracleSuite) TestOracleBinary(c *C) {
+	oracle := oracleCheck(c, "2E4")
+	var b bytes.Buffer
+	c.Assert(encode(&b, &oracle, false), IsNil)
+	var decoded Oracle
+	v := reflect.ValueOf(&decoded)
+	c.Assert(readObject(bytes.NewReader(b.Bytes()), &v), IsNil)
+	c.Check(decoded.Owner.String(), Equals, "rNZ9m6AP9K7z3EVg6GhPMx36V4QmZKeWds")
+	c.Check(*decoded.LastUpdateTime, Equals, uint32(1724871860))
+	c.Check(decoded.PriceDataSeries, DeepEquals, oracle.PriceDataSeries)
+	price, ok := decoded.Price(zeroCurrency, currencyCheck(c, "USD"))
+	c.Assert(ok, Equals, true)
+	c.Check(price.FloatString(3), Equals, "0.740")
+}
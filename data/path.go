@@ -91,6 +91,15 @@ func (p PathElem) pathEntry() pathEntry {
 	return entry
 }
 
+// IsAccount is true when the step rips through an account
+func (p PathElem) IsAccount() bool { return p.pathEntry()&PATH_ACCOUNT != 0 }
+
+// IsCurrency is true when the step changes currency
+func (p PathElem) IsCurrency() bool { return p.pathEntry()&PATH_CURRENCY != 0 }
+
+// IsIssuer is true when the step changes issuer
+func (p PathElem) IsIssuer() bool { return p.pathEntry()&PATH_ISSUER != 0 }
+
 func (p Path) Signature() (uint32, error) {
 	checksum := crc32.NewIEEE()
 	for _, path := range p {
@@ -140,3 +149,21 @@ func (p PathElem) MarshalJSON() ([]byte, error) {
 		fmt.Sprintf("%016X", uint64(typ)),
 	})
 }
+
+// The type is optional, but if present must agree with the fields
+func (p *PathElem) UnmarshalJSON(b []byte) error {
+	var dummy struct {
+		Account  *Account   `json:"account"`
+		Currency *Currency  `json:"currency"`
+		Issuer   *Account   `json:"issuer"`
+		Type     *pathEntry `json:"type"`
+	}
+	if err := json.Unmarshal(b, &dummy); err != nil {
+		return err
+	}
+	*p = PathElem{dummy.Account, dummy.Currency, dummy.Issuer}
+	if dummy.Type != nil && *dummy.Type != p.pathEntry() {
+		return fmt.Errorf("PathElem type %d does not match fields: %s", *dummy.Type, p)
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package data
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -48,3 +52,106 @@ func (s *PathSuite) TestPathError(c *C) {
 	_, err := NewPath("Foo")
 	c.Assert(err.Error(), Equals, "Base58 string too short: Foo")
 }
+
+func (s *PathSuite) TestPaymentPaths(c *C) {
+	txm := readTransactionWithMetaData(c, "testdata/transaction_payment_with_rippling.json")
+	payment := txm.Transaction.(*Payment)
+	paths := payment.PathSet()
+	c.Assert(paths, HasLen, 4)
+	var lengths []int
+	for _, path := range paths {
+		lengths = append(lengths, len(path))
+	}
+	c.Assert(lengths, DeepEquals, []int{3, 2, 5, 3})
+	c.Assert(paths[2].String(), Equals, "rpDMez6pm6dBve2TJsmDpv7Yae6V5Pyvy2 => XRP => USD/rMwjYedjc7qqtKYVLiAccJSmCwih4LnE2q => rMwjYedjc7qqtKYVLiAccJSmCwih4LnE2q => rnziParaNb8nsU4aruQdwYE3j5jUcqjzFm")
+
+	for _, t := range []struct {
+		Elem                            PathElem
+		IsAccount, IsCurrency, IsIssuer bool
+	}{
+		{paths[2][0], true, false, false},
+		{paths[2][1], false, true, false},
+		{paths[2][2], false, true, true},
+	} {
+		c.Check(t.Elem.IsAccount(), Equals, t.IsAccount, Commentf(t.Elem.String()))
+		c.Check(t.Elem.IsCurrency(), Equals, t.IsCurrency, Commentf(t.Elem.String()))
+		c.Check(t.Elem.IsIssuer(), Equals, t.IsIssuer, Commentf(t.Elem.String()))
+	}
+
+	// Binary round trip
+	_, raw, err := Raw(payment)
+	c.Assert(err, IsNil)
+	decoded, err := ReadTransaction(bytes.NewReader(raw))
+	c.Assert(err, IsNil)
+	c.Assert(decoded.PathSet(), DeepEquals, paths)
+
+	// JSON round trip matches rippled's representation
+	b, err := ioutil.ReadFile("testdata/transaction_payment_with_rippling.json")
+	c.Assert(err, IsNil)
+	var original struct{ Paths interface{} }
+	c.Assert(json.Unmarshal(b, &original), IsNil)
+	out, err := json.Marshal(paths)
+	c.Assert(err, IsNil)
+	var marshalled interface{}
+	c.Assert(json.Unmarshal(out, &marshalled), IsNil)
+	c.Assert(marshalled, DeepEquals, original.Paths)
+}
+
+func (s *PathSuite) TestPathSetJSON(c *C) {
+	var empty PathSet
+	c.Assert(json.Unmarshal([]byte(`[]`), &empty), IsNil)
+	c.Assert(empty, HasLen, 0)
+
+	// Redundant type fields are checked against the step
+	var paths PathSet
+	c.Assert(json.Unmarshal([]byte(`[[{"account":"rpDMez6pm6dBve2TJsmDpv7Yae6V5Pyvy2"},{"currency":"XRP","type":16}]]`), &paths), IsNil)
+	c.Assert(paths[0][0].IsAccount(), Equals, true)
+	c.Assert(paths[0][1].Currency.IsNative(), Equals, true)
+	err := json.Unmarshal([]byte(`[[{"account":"rpDMez6pm6dBve2TJsmDpv7Yae6V5Pyvy2","type":48}]]`), &paths)
+	c.Assert(err, ErrorMatches, "PathElem type 48 does not match fields: rpDMez6pm6dBve2TJsmDpv7Yae6V5Pyvy2")
+
+	// An empty path set is omitted when serializing
+	payment := partialPayment("10/XRP", "", 0)
+	_, without, err := Raw(payment)
+	c.Assert(err, IsNil)
+	payment.Paths = &empty
+	_, with, err := Raw(payment)
+	c.Assert(err, IsNil)
+	c.Assert(with, DeepEquals, without)
+}
+
+func (s *PathSuite) TestPathSetRoundTrip(c *C) {
+	first, err := NewPath("BTC/rNPRNzBB92BVpAhhZr4iXDTveCgV5Pofm9 => r3ADD8kXSUKHd6zTCKfnKT3zV9EZHjzp1S")
+	c.Assert(err, IsNil)
+	second, err := NewPath("rpDMez6pm6dBve2TJsmDpv7Yae6V5Pyvy2")
+	c.Assert(err, IsNil)
+	issuer, err := NewAccountFromAddress("rMwjYedjc7qqtKYVLiAccJSmCwih4LnE2q")
+	c.Assert(err, IsNil)
+	// A change of issuer alone
+	second = append(second, PathElem{Issuer: issuer})
+
+	for _, paths := range []PathSet{{first}, {first, second}, {}} {
+		var b bytes.Buffer
+		c.Assert(paths.Marshal(&b), IsNil)
+		var decoded PathSet
+		c.Assert(decoded.Unmarshal(bytes.NewReader(b.Bytes())), IsNil)
+		c.Check(decoded, HasLen, len(paths))
+		if len(paths) > 0 {
+			c.Check(decoded, DeepEquals, paths)
+		}
+
+		out, err := json.Marshal(paths)
+		c.Assert(err, IsNil)
+		decoded = nil
+		c.Assert(json.Unmarshal(out, &decoded), IsNil)
+		c.Check(decoded, HasLen, len(paths))
+		if len(paths) > 0 {
+			c.Check(decoded, DeepEquals, paths)
+		}
+	}
+
+	var b bytes.Buffer
+	c.Assert((&PathSet{}).Marshal(&b), IsNil)
+	c.Check(b.Bytes(), DeepEquals, []byte{byte(PATH_END)})
+	c.Check((&PathSet{{PathElem{}}}).Marshal(&b), ErrorMatches, "Empty PathElem in path: .*")
+}
@@ -2,6 +2,33 @@ package data
 
 type TransactionResult int16
 
+// ResultClass is the category of a TransactionResult given by its prefix
+type ResultClass uint8
+
+const (
+	ResultUnknown   ResultClass = iota
+	ResultSuccess               // tes
+	ResultClaimed               // tec
+	ResultLocal                 // tel
+	ResultMalformed             // tem
+	ResultFailure               // tef
+	ResultRetry                 // ter
+)
+
+var resultClassNames = [...]string{
+	ResultUnknown:   "unknown",
+	ResultSuccess:   "tes",
+	ResultClaimed:   "tec",
+	ResultLocal:     "tel",
+	ResultMalformed: "tem",
+	ResultFailure:   "tef",
+	ResultRetry:     "ter",
+}
+
+func (c ResultClass) String() string {
+	return resultClassNames[c]
+}
+
 const (
 	// 0: S Success (success)
 	// Causes:
@@ -317,6 +344,61 @@ func (r TransactionResult) Queued() bool {
 	return r == terQUEUED
 }
 
+func (r TransactionResult) Class() ResultClass {
+	switch {
+	case r == tesSUCCESS:
+		return ResultSuccess
+	case r >= 100 && r <= 255:
+		return ResultClaimed
+	case r >= -399 && r <= -300:
+		return ResultLocal
+	case r >= -299 && r <= -200:
+		return ResultMalformed
+	case r >= -199 && r <= -100:
+		return ResultFailure
+	case r >= -99 && r <= -1:
+		return ResultRetry
+	default:
+		return ResultUnknown
+	}
+}
+
+// Applied is true for results which are included in a ledger
+func (r TransactionResult) Applied() bool {
+	class := r.Class()
+	return class == ResultSuccess || class == ResultClaimed
+}
+
+// ClaimedFee is true for results which only claim the fee
+func (r TransactionResult) ClaimedFee() bool {
+	return r.Class() == ResultClaimed
+}
+
+// IsRetryable is true for submission results which might succeed if the
+// transaction is submitted again later:
+//
+//	ter                      Always, the transaction may apply once an earlier one has
+//	telINSUF_FEE_P           The local load fee will drop
+//	telCAN_NOT_QUEUE*        The queue will drain
+//	telFAILED_PROCESSING     The server was briefly unable to process it
+//	tefPAST_SEQ              Only after re-signing with the current Sequence
+//	tefMAX_LEDGER            Only after re-signing with a later LastLedgerSequence
+//	tes, tec, tem and others Never, the result is final
+//
+// Before re-signing, check that the earlier submission was not validated,
+// as tefPAST_SEQ is also the result of resubmitting an applied transaction.
+func (r TransactionResult) IsRetryable() bool {
+	switch r {
+	case telINSUF_FEE_P, telFAILED_PROCESSING,
+		telCAN_NOT_QUEUE, telCAN_NOT_QUEUE_BALANCE, telCAN_NOT_QUEUE_BLOCKS,
+		telCAN_NOT_QUEUE_BLOCKED, telCAN_NOT_QUEUE_FEE, telCAN_NOT_QUEUE_FULL,
+		tefPAST_SEQ, tefMAX_LEDGER:
+		return true
+	default:
+		return r.Class() == ResultRetry
+	}
+}
+
 func (r TransactionResult) Symbol() string {
 	switch r {
 	case tesSUCCESS, tecCLAIM:
@@ -0,0 +1,55 @@
+package data
+
+import . "gopkg.in/check.v1"
+
+type ResultSuite struct{}
+
+var _ = Suite(&ResultSuite{})
+
+func (s *ResultSuite) TestResultClass(c *C) {
+	tests := []struct {
+		result     TransactionResult
+		class      ResultClass
+		applied    bool
+		claimedFee bool
+	}{
+		{tesSUCCESS, ResultSuccess, true, false},
+		{tecPATH_DRY, ResultClaimed, true, true},
+		{tecINVARIANT_FAILED, ResultClaimed, true, true},
+		{telINSUF_FEE_P, ResultLocal, false, false},
+		{temBAD_AMOUNT, ResultMalformed, false, false},
+		{tefPAST_SEQ, ResultFailure, false, false},
+		{terQUEUED, ResultRetry, false, false},
+		{TransactionResult(-400), ResultUnknown, false, false},
+	}
+	for _, test := range tests {
+		msg := Commentf("%s", test.result)
+		c.Check(test.result.Class(), Equals, test.class, msg)
+		c.Check(test.result.Applied(), Equals, test.applied, msg)
+		c.Check(test.result.ClaimedFee(), Equals, test.claimedFee, msg)
+	}
+	c.Check(tecNO_DST.Class().String(), Equals, "tec")
+	c.Check(tefFAILURE.Class().String(), Equals, "tef")
+}
+
+func (s *ResultSuite) TestIsRetryable(c *C) {
+	for _, test := range []struct {
+		result    TransactionResult
+		retryable bool
+	}{
+		{tesSUCCESS, false},
+		{terQUEUED, true},
+		{terPRE_SEQ, true},
+		{telINSUF_FEE_P, true},
+		{telCAN_NOT_QUEUE_FULL, true},
+		{telBAD_PUBLIC_KEY, false},
+		{tefPAST_SEQ, true},
+		{tefMAX_LEDGER, true},
+		{tefALREADY, false},
+		{temBAD_FEE, false},
+		{tecPATH_DRY, false},
+		{TransactionResult(-400), false},
+	} {
+		c.Check(test.result.IsRetryable(), Equals, test.retryable, Commentf("%s", test.result))
+	}
+}
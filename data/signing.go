@@ -1,7 +1,15 @@
 package data
 
-import "github.com/atticlab/ripple/crypto"
+import (
+	"bytes"
+	"fmt"
 
+	"github.com/atticlab/ripple/crypto"
+)
+
+// Sign single-signs s with the key at sequence in its account family, nil
+// for Ed25519 keys. It sets SigningPubKey, signs the canonical serialization
+// with the signing prefix and sets TxnSignature and the hash.
 func Sign(s SignerAgent, key crypto.Key, sequence *uint32) error {
 	s.InitialiseForSigning()
 	copy(s.GetPublicKey().Bytes(), key.Public(sequence))
@@ -22,6 +30,8 @@ func Sign(s SignerAgent, key crypto.Key, sequence *uint32) error {
 	return nil
 }
 
+// SignFor adds the signature of the key's account to the Signers of s,
+// making it a multi-signed transaction, and updates the hash.
 func SignFor(s SignerAgent, key crypto.Key, sequence *uint32) error {
 	s.InitialiseForMultiSigning()
 	hash, msg, err := SigningHash(s, key.Id(sequence))
@@ -55,10 +65,172 @@ func SignFor(s SignerAgent, key crypto.Key, sequence *uint32) error {
 	return nil
 }
 
+// Sign signs the transaction with the key pair, setting SigningPubKey,
+// TxnSignature and the hash. A transaction which is already signed, or
+// multi-signed, is only re-signed when force is set, which discards the
+// signatures of any Signers.
+func (txm *TransactionWithMetaData) Sign(keyPair *crypto.KeyPair, force bool) error {
+	base := txm.GetBase()
+	signed := (base.TxnSignature != nil && len(*base.TxnSignature) > 0) || len(base.Signers) > 0
+	if signed && !force {
+		return fmt.Errorf("Transaction is already signed: %s", base.Hash)
+	}
+	base.Signers = nil
+	return Sign(txm.Transaction, keyPair.Key, keyPair.Sequence)
+}
+
+// MultisignFee returns the fee in drops for a transaction with numSigners
+// signatures, each of which costs the same as the transaction itself
+func MultisignFee(baseFee uint64, numSigners int) uint64 {
+	return baseFee * uint64(1+numSigners)
+}
+
+// NewSigner returns an entry for the Signers array of a multi-signed transaction
+func NewSigner(account Account, publicKey PublicKey, signature VariableLength) Signer {
+	var signer Signer
+	signer.Signer.Account = &account
+	signer.Signer.SigningPubKey = &publicKey
+	signer.Signer.TxnSignature = &signature
+	return signer
+}
+
+// MultiSign replaces the signatures of s with the supplied signers,
+// sorted by account as required for submission.
+func MultiSign(s Transaction, signers ...Signer) error {
+	if len(signers) == 0 {
+		return fmt.Errorf("MultiSign requires at least one signer")
+	}
+	s.InitialiseForMultiSigning()
+	base := s.GetBase()
+	base.Signers = append(Signers(nil), signers...)
+	base.Signers.Sort()
+	for i, signer := range base.Signers {
+		if signer.Signer.Account == nil || signer.Signer.SigningPubKey == nil || signer.Signer.TxnSignature == nil {
+			return fmt.Errorf("Incomplete signer at position %d", i)
+		}
+		if i > 0 && signer.Signer.Account.Equals(*base.Signers[i-1].Signer.Account) {
+			return fmt.Errorf("Duplicate signer: %s", signer.Signer.Account)
+		}
+	}
+	hash, _, err := Raw(s)
+	if err != nil {
+		return err
+	}
+	copy(s.GetHash().Bytes(), hash.Bytes())
+	return nil
+}
+
+// Combine merges copies of a transaction, each multi-signed with SignFor by
+// some of the signers, and returns the tx_blob of the transaction with all
+// their signatures. The copies must differ only in their signatures.
+func Combine(txs ...Transaction) (string, error) {
+	if len(txs) == 0 {
+		return "", fmt.Errorf("Combine requires at least one transaction")
+	}
+	first, _, err := SigningHash(txs[0], nil)
+	if err != nil {
+		return "", err
+	}
+	var signers []Signer
+	for i, tx := range txs {
+		hash, _, err := SigningHash(tx, nil)
+		if err != nil {
+			return "", err
+		}
+		if hash != first {
+			return "", fmt.Errorf("Transaction %d differs from the first: %s expected: %s", i, hash, first)
+		}
+		signers = append(signers, tx.GetBase().Signers...)
+	}
+	// Leave the inputs untouched
+	_, raw, err := Raw(txs[0])
+	if err != nil {
+		return "", err
+	}
+	combined, err := ReadTransaction(bytes.NewReader(raw))
+	if err != nil {
+		return "", err
+	}
+	if err := MultiSign(combined, signers...); err != nil {
+		return "", err
+	}
+	_, raw, err = Raw(combined)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%X", raw), nil
+}
+
+// CheckSignature reports whether TxnSignature is a valid signature of s by
+// SigningPubKey. As in rippled before RequireFullyCanonicalSig, the ECDSA
+// signature of a transaction flagged CanonicalSignature must be fully canonical.
 func CheckSignature(s SignerAgent) (bool, error) {
+	publicKey := s.GetPublicKey()
+	if publicKey == nil || publicKey.IsZero() {
+		return false, fmt.Errorf("No SigningPubKey to check")
+	}
+	if requiresCanonicalSignature(s) && !crypto.IsFullyCanonical(s.GetSignature().Bytes()) {
+		return false, nil
+	}
 	hash, msg, err := SigningHash(s, nil)
 	if err != nil {
 		return false, err
 	}
-	return crypto.Verify(s.GetPublicKey().Bytes(), hash.Bytes(), msg, s.GetSignature().Bytes())
+	// Ed25519 signs the prefixed message, as in Sign
+	return crypto.Verify(s.GetPublicKey().Bytes(), hash.Bytes(), append(s.SigningPrefix().Bytes(), msg...), s.GetSignature().Bytes())
+}
+
+func requiresCanonicalSignature(s SignerAgent) bool {
+	tx, ok := s.(Transaction)
+	if !ok {
+		return false
+	}
+	base := tx.GetBase()
+	if keyType, _ := base.SigningPubKey.KeyType(); keyType != ECDSA {
+		return false
+	}
+	return base.Flags != nil && *base.Flags&TxCanonicalSignature > 0
+}
+
+// CheckClaim reports whether Signature is a claim by PublicKey of Balance
+// from the channel, as required for a claim by the destination
+func (p *PaymentChannelClaim) CheckClaim() (bool, error) {
+	switch {
+	case p.Balance == nil || p.Signature == nil || p.PublicKey == nil:
+		return false, fmt.Errorf("PaymentChannelClaim has no signed claim")
+	case !p.Balance.IsNative():
+		return false, fmt.Errorf("PaymentChannelClaim Balance must be XRP: %s", p.Balance)
+	}
+	return crypto.VerifyClaim(p.PublicKey.Bytes(), p.Channel.Bytes(), p.Balance.Drops(), p.Signature.Bytes())
+}
+
+// ComputeHash returns the hash of the canonical serialization of the transaction
+func (txm *TransactionWithMetaData) ComputeHash() (Hash256, error) {
+	hash, _, err := Raw(txm.Transaction)
+	return hash, err
+}
+
+// VerifyHash returns an error if the stored hash is not the hash of the transaction
+func (txm *TransactionWithMetaData) VerifyHash() error {
+	hash, err := txm.ComputeHash()
+	if err != nil {
+		return err
+	}
+	if hash != *txm.GetHash() {
+		return fmt.Errorf("Bad transaction hash: %s expected: %s", txm.GetHash(), hash)
+	}
+	return nil
+}
+
+// PrecheckSigning returns an error if the transaction is signed with the
+// master key of its account when the master key is disabled.
+func (txm *TransactionWithMetaData) PrecheckSigning(masterDisabled bool) error {
+	base := txm.GetBase()
+	if !masterDisabled || base.SigningPubKey == nil || base.SigningPubKey.IsZero() {
+		return nil
+	}
+	if base.SigningPubKey.AccountId().Equals(base.Account) {
+		return fmt.Errorf("Master key is disabled for %s, sign with the regular key", base.Account)
+	}
+	return nil
 }
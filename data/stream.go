@@ -0,0 +1,109 @@
+package data
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Decoder reads the nodes of a ledger or nodestore dump from an io.Reader
+// one at a time, so the memory used does not grow with the size of the
+// dump. Each node is written as its 32 byte node id, the length of its
+// value as a big endian uint32 and the value in the prefix format of the
+// nodestore, which is read with ReadPrefix.
+//
+//	d := NewDecoder(r)
+//	for d.Next() {
+//		node := d.Node()
+//	}
+//	if err := d.Err(); err != nil {
+type Decoder struct {
+	r      streamReader
+	nodeId Hash256
+	node   Storer
+	err    error
+}
+
+// streamReader satisfies Reader for a buffered stream. Its length is
+// unknown, so it is only read through a LimitByteReader, which tracks the
+// length of the value being read.
+type streamReader struct {
+	*bufio.Reader
+}
+
+func (streamReader) Len() int { return 0 }
+
+// Read fills p, as the decoder expects of a bytes.Reader, where bufio
+// would stop at the end of its buffer
+func (s streamReader) Read(p []byte) (int, error) {
+	return io.ReadFull(s.Reader, p)
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: streamReader{bufio.NewReader(r)}}
+}
+
+// Next reads the next node, returning false at the end of the stream or
+// on the first error, which Err returns
+func (d *Decoder) Next() bool {
+	if d.err != nil {
+		return false
+	}
+	d.node = nil
+	if _, err := io.ReadFull(d.r, d.nodeId[:]); err != nil {
+		if err != io.EOF {
+			d.err = fmt.Errorf("Bad node id: %s", err)
+		}
+		return false
+	}
+	var length uint32
+	if err := binary.Read(d.r, binary.BigEndian, &length); err != nil {
+		d.err = fmt.Errorf("Bad node length for %s: %s", d.nodeId, err)
+		return false
+	}
+	value := LimitedByteReader(d.r, int64(length))
+	node, err := ReadPrefix(value, d.nodeId)
+	if err != nil {
+		d.err = fmt.Errorf("Bad node %s: %s", d.nodeId, err)
+		return false
+	}
+	// Skip anything the node did not read, such as its trailing index
+	if _, err := io.Copy(ioutil.Discard, value); err != nil {
+		d.err = err
+		return false
+	}
+	d.node = node
+	return true
+}
+
+// Node returns the node read by the last call to Next
+func (d *Decoder) Node() Storer { return d.node }
+
+// NodeId returns the id of the node read by the last call to Next
+func (d *Decoder) NodeId() Hash256 { return d.nodeId }
+
+// Err returns the error which stopped Next, or nil at the end of the stream
+func (d *Decoder) Err() error { return d.err }
+
+// NodeWriter writes nodes in the format read by Decoder
+type NodeWriter struct {
+	w io.Writer
+}
+
+func NewNodeWriter(w io.Writer) *NodeWriter {
+	return &NodeWriter{w: w}
+}
+
+// Write writes the value of a node in the nodestore's prefix format
+func (n *NodeWriter) Write(nodeId Hash256, value []byte) error {
+	if _, err := n.w.Write(nodeId[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(n.w, binary.BigEndian, uint32(len(value))); err != nil {
+		return err
+	}
+	_, err := n.w.Write(value)
+	return err
+}
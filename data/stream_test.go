@@ -0,0 +1,67 @@
+package data
+
+import (
+	"bytes"
+	"io"
+
+	internal "github.com/atticlab/ripple/testing"
+	. "gopkg.in/check.v1"
+)
+
+type StreamSuite struct{}
+
+var _ = Suite(&StreamSuite{})
+
+// oneByteReader returns a byte at a time, the worst case for buffering
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (o oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return o.r.Read(p[:1])
+}
+
+func (s *StreamSuite) TestDecoder(c *C) {
+	var (
+		stream bytes.Buffer
+		ids    []Hash256
+		hashes []Hash256
+	)
+	w := NewNodeWriter(&stream)
+	for _, test := range internal.Nodes {
+		nodeId, err := NewHash256(test.NodeId())
+		c.Assert(err, IsNil)
+		node, err := ReadPrefix(test.Reader(), *nodeId)
+		if err != nil {
+			continue
+		}
+		c.Assert(w.Write(*nodeId, test.Bytes()), IsNil)
+		ids = append(ids, *nodeId)
+		hashes = append(hashes, *node.GetHash())
+	}
+	c.Assert(len(ids) > 1, Equals, true)
+
+	d := NewDecoder(oneByteReader{bytes.NewReader(stream.Bytes())})
+	var i int
+	for ; d.Next(); i++ {
+		c.Assert(i < len(ids), Equals, true)
+		c.Check(d.NodeId(), Equals, ids[i])
+		c.Check(*d.Node().GetHash(), Equals, hashes[i])
+		c.Check(*d.Node().NodeId(), Equals, ids[i])
+	}
+	c.Assert(d.Err(), IsNil)
+	c.Check(i, Equals, len(ids))
+	c.Check(d.Next(), Equals, false)
+
+	truncated := NewDecoder(bytes.NewReader(stream.Bytes()[:stream.Len()-1]))
+	for truncated.Next() {
+	}
+	c.Check(truncated.Err(), NotNil)
+
+	empty := NewDecoder(bytes.NewReader(nil))
+	c.Check(empty.Next(), Equals, false)
+	c.Check(empty.Err(), IsNil)
+}
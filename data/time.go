@@ -1,6 +1,7 @@
 package data
 
 import (
+	"math"
 	"time"
 )
 
@@ -18,16 +19,27 @@ type rippleHumanTime struct {
 	RippleTime
 }
 
-func NewRippleTime(t uint32) *RippleTime {
-	return &RippleTime{t}
+// NewRippleTime truncates t to seconds. Times before the Ripple epoch are
+// clamped to the epoch and times after 2136-Feb-07 06:28:15 to that time.
+func NewRippleTime(t time.Time) RippleTime {
+	return RippleTime{convertToRippleTime(t)}
 }
 
 func convertToRippleTime(t time.Time) uint32 {
-	return uint32(t.Sub(time.Unix(rippleTimeEpoch, 0)).Nanoseconds() / 1000000000)
+	seconds := t.Unix() - rippleTimeEpoch
+	switch {
+	case seconds < 0:
+		return 0
+	case seconds > math.MaxUint32:
+		return math.MaxUint32
+	default:
+		return uint32(seconds)
+	}
 }
 
+// Time returns the time in UTC
 func (t RippleTime) Time() time.Time {
-	return time.Unix(int64(t.T)+rippleTimeEpoch, 0)
+	return time.Unix(int64(t.T)+rippleTimeEpoch, 0).UTC()
 }
 
 func Now() *RippleTime {
@@ -56,12 +68,17 @@ func (t RippleTime) human() *rippleHumanTime {
 	return &rippleHumanTime{t}
 }
 
-// Returns time formatted as 2006-Jan-02 15:04:05
+// Returns time formatted as 2006-01-02T15:04:05Z
 func (t RippleTime) String() string {
-	return t.Time().UTC().Format(rippleTimeFormat)
+	return t.Time().Format(time.RFC3339)
+}
+
+// Returns time formatted as 2006-Jan-02 15:04:05
+func (t RippleTime) Human() string {
+	return t.Time().Format(rippleTimeFormat)
 }
 
 // Returns time formatted as 15:04:05
 func (t RippleTime) Short() string {
-	return t.Time().UTC().Format("15:04:05")
+	return t.Time().Format("15:04:05")
 }
@@ -0,0 +1,34 @@
+package data
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type TimeSuite struct{}
+
+var _ = Suite(&TimeSuite{})
+
+func (s *TimeSuite) TestNewRippleTime(c *C) {
+	x := time.Date(2014, time.May, 30, 13, 11, 50, 999999999, time.FixedZone("UTC+2", 2*3600))
+	t := NewRippleTime(x)
+	c.Check(t.Uint32(), Equals, uint32(454763510))
+	c.Check(t.Time().Equal(x.Truncate(time.Second)), Equals, true)
+	c.Check(t.Time().Location(), Equals, time.UTC)
+	c.Check(t.String(), Equals, "2014-05-30T11:11:50Z")
+	c.Check(t.Human(), Equals, "2014-May-30 11:11:50")
+	c.Check(t.Short(), Equals, "11:11:50")
+
+	epoch := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c.Check(NewRippleTime(epoch).Uint32(), Equals, uint32(0))
+	c.Check(NewRippleTime(epoch).String(), Equals, "2000-01-01T00:00:00Z")
+}
+
+func (s *TimeSuite) TestNewRippleTimeClamped(c *C) {
+	before := time.Date(1999, time.December, 31, 23, 59, 59, 0, time.UTC)
+	c.Check(NewRippleTime(before).Uint32(), Equals, uint32(0))
+	after := time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c.Check(NewRippleTime(after).Uint32(), Equals, uint32(4294967295))
+	c.Check(NewRippleTime(after).String(), Equals, "2136-02-07T06:28:15Z")
+}
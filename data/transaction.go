@@ -1,11 +1,19 @@
 package data
 
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
 type TxBase struct {
 	TransactionType    TransactionType
+	NetworkID          *uint32          `json:",omitempty"` // Required by networks with ids above 1024, absent on mainnet
 	Flags              *TransactionFlag `json:",omitempty"`
 	SourceTag          *uint32          `json:",omitempty"`
 	Account            Account
 	Sequence           uint32
+	TicketSequence     *uint32 `json:",omitempty"` // Used instead of Sequence, which is then 0
 	Fee                Value
 	AccountTxnID       *Hash256        `json:",omitempty"`
 	SigningPubKey      *PublicKey      `json:",omitempty"`
@@ -14,6 +22,7 @@ type TxBase struct {
 	Memos              Memos           `json:",omitempty"`
 	PreviousTxnID      *Hash256        `json:",omitempty"`
 	LastLedgerSequence *uint32         `json:",omitempty"`
+	OperationLimit     *uint32         `json:",omitempty"`
 	Hash               Hash256         `json:"hash"`
 }
 
@@ -83,19 +92,22 @@ type EscrowCreate struct {
 	TxBase
 	Destination    Account
 	Amount         Amount
-	Digest         *Hash256 `json:",omitempty"`
-	CancelAfter    *uint32  `json:",omitempty"`
-	FinishAfter    *uint32  `json:",omitempty"`
-	DestinationTag *uint32  `json:",omitempty"`
+	Digest         *Hash256        `json:",omitempty"` // Suspended payments only
+	Condition      *VariableLength `json:",omitempty"`
+	CancelAfter    *uint32         `json:",omitempty"`
+	FinishAfter    *uint32         `json:",omitempty"`
+	DestinationTag *uint32         `json:",omitempty"`
 }
 
 type EscrowFinish struct {
 	TxBase
 	Owner         Account
 	OfferSequence uint32
-	Method        *uint8   `json:",omitempty"`
-	Digest        *Hash256 `json:",omitempty"`
-	Proof         *Hash256 `json:",omitempty"`
+	Method        *uint8          `json:",omitempty"` // Suspended payments only
+	Digest        *Hash256        `json:",omitempty"` // Suspended payments only
+	Proof         *Hash256        `json:",omitempty"` // Suspended payments only
+	Condition     *VariableLength `json:",omitempty"`
+	Fulfillment   *VariableLength `json:",omitempty"`
 }
 
 type EscrowCancel struct {
@@ -158,9 +170,18 @@ type CheckCancel struct {
 	CheckID Hash256
 }
 
+// DepositPreauthTx is the DepositPreauth transaction, named apart from the
+// ledger entry it creates or deletes. Exactly one of Authorize and
+// Unauthorize is set.
+type DepositPreauthTx struct {
+	TxBase
+	Authorize   *Account `json:",omitempty"`
+	Unauthorize *Account `json:",omitempty"`
+}
+
 type TicketCreate struct {
-	Target     *Account `json:",omitempty"`
-	Expiration *uint32  `json:",omitempty"`
+	TxBase
+	TicketCount uint32
 }
 
 type TicketCancel struct {
@@ -173,6 +194,81 @@ type SignerListSet struct {
 	SignerEntries []SignerEntries `json:",omitempty"`
 }
 
+type AuthAccount struct {
+	AuthAccount struct {
+		Account Account
+	}
+}
+
+type AuthAccounts []AuthAccount
+
+// https://xrpl.org/ammbid.html
+// AuthAccounts are serialized in the order given, at most four are allowed
+type AMMBid struct {
+	TxBase
+	Asset        Issue
+	Asset2       Issue
+	BidMin       *Amount      `json:",omitempty"`
+	BidMax       *Amount      `json:",omitempty"`
+	AuthAccounts AuthAccounts `json:",omitempty"`
+}
+
+// https://xrpl.org/nftokenmint.html
+// TransferFee is in units of 1/100000, at most 50000 (50%)
+type NFTokenMint struct {
+	TxBase
+	NFTokenTaxon uint32
+	Issuer       *Account        `json:",omitempty"`
+	TransferFee  *uint16         `json:",omitempty"`
+	URI          *VariableLength `json:",omitempty"`
+}
+
+// https://xrpl.org/nftokenburn.html
+type NFTokenBurn struct {
+	TxBase
+	NFTokenID Hash256
+	Owner     *Account `json:",omitempty"`
+}
+
+// https://xrpl.org/nftokencreateoffer.html
+// Owner is required for buy offers and must be absent for sell offers
+type NFTokenCreateOffer struct {
+	TxBase
+	NFTokenID   Hash256
+	Amount      Amount
+	Owner       *Account `json:",omitempty"`
+	Expiration  *uint32  `json:",omitempty"`
+	Destination *Account `json:",omitempty"`
+}
+
+// https://xrpl.org/nftokencanceloffer.html
+type NFTokenCancelOffer struct {
+	TxBase
+	NFTokenOffers Vector256
+}
+
+// https://xrpl.org/nftokenacceptoffer.html
+// Setting both offers is brokered mode
+type NFTokenAcceptOffer struct {
+	TxBase
+	NFTokenSellOffer *Hash256 `json:",omitempty"`
+	NFTokenBuyOffer  *Hash256 `json:",omitempty"`
+	NFTokenBrokerFee *Amount  `json:",omitempty"`
+}
+
+// https://xrpl.org/clawback.html
+// The issuer of Amount is the holder the tokens are clawed back from,
+// Account is the issuer
+type Clawback struct {
+	TxBase
+	Amount Amount
+}
+
+// Holder returns the account the tokens are clawed back from
+func (c *Clawback) Holder() Account {
+	return c.Amount.Issuer
+}
+
 func (t *TxBase) GetBase() *TxBase                    { return t }
 func (t *TxBase) GetType() string                     { return txNames[t.TransactionType] }
 func (t *TxBase) GetTransactionType() TransactionType { return t.TransactionType }
@@ -205,6 +301,30 @@ func (t *TxBase) AddSignature(signer *Signer) {
 	t.Signers = append(t.Signers, *signer)
 }
 
+// Multi-signed transactions must have their Signers sorted by account
+// and an empty SigningPubKey. Unsorted Signers are encoded from a shallow
+// copy of tx holding a sorted copy of them, leaving tx untouched.
+func prepareSigners(tx Transaction) (Transaction, error) {
+	t := tx.GetBase()
+	if len(t.Signers) == 0 {
+		return tx, nil
+	}
+	if t.SigningPubKey != nil && !t.SigningPubKey.IsZero() {
+		return nil, fmt.Errorf("Multi-signed transaction must have an empty SigningPubKey")
+	}
+	if sort.IsSorted(t.Signers) {
+		return tx, nil
+	}
+	v := reflect.Indirect(reflect.ValueOf(tx))
+	copied := reflect.New(v.Type())
+	copied.Elem().Set(v)
+	sorted := copied.Interface().(Transaction)
+	signers := append(Signers(nil), t.Signers...)
+	signers.Sort()
+	sorted.GetBase().Signers = signers
+	return sorted, nil
+}
+
 func (t *TxBase) signingPrefix() HashPrefix {
 	if t.Signers == nil {
 		return HP_TRANSACTION_SIGN
@@ -226,6 +346,21 @@ func (t *TxBase) InitialiseForSigning() {
 	}
 }
 
+// UseTicket makes the transaction consume the ticket instead of a sequence
+func (t *TxBase) UseTicket(ticketSequence uint32) {
+	t.Sequence = 0
+	t.TicketSequence = &ticketSequence
+}
+
+// SequenceOrTicket returns the sequence the transaction consumes and
+// whether it is a ticket rather than the account's next sequence
+func (t *TxBase) SequenceOrTicket() (uint32, bool) {
+	if t.TicketSequence != nil {
+		return *t.TicketSequence, true
+	}
+	return t.Sequence, false
+}
+
 func (t *TxBase) InitialiseForMultiSigning() {
 	if t.Signers == nil {
 		t.Signers = Signers{}
@@ -238,9 +373,95 @@ func (o *OfferCreate) Ratio() *Value {
 	return o.TakerPays.Ratio(o.TakerGets)
 }
 
+// Quality returns the exchange rate of the BookDirectory the offer is
+// placed in
+func (o *OfferCreate) Quality() (ExchangeRate, error) {
+	return NewExchangeRate(&o.TakerPays, &o.TakerGets)
+}
+
+// ValidatePartial checks that a Payment with DeliverMin is a partial payment
+// and that DeliverMin is in the same currency as Amount.
+func (p *Payment) ValidatePartial() error {
+	if p.DeliverMin == nil {
+		return nil
+	}
+	if p.Flags == nil || *p.Flags&TxPartialPayment == 0 {
+		return fmt.Errorf("DeliverMin requires the PartialPayment flag")
+	}
+	if !p.DeliverMin.Currency.Equals(p.Amount.Currency) {
+		return fmt.Errorf("DeliverMin currency %s does not match Amount currency %s", p.DeliverMin.Currency, p.Amount.Currency)
+	}
+	return nil
+}
+
 func (p *Payment) PathSet() PathSet {
 	if p.Paths == nil {
 		return PathSet(nil)
 	}
 	return *p.Paths
 }
+
+// NewTrustLineRemoval returns a TrustSet which returns account's side of the
+// trust line to its default state, with a zero limit, default qualities and
+// the freeze cleared. NoRipple is cleared when the issuer has DefaultRipple
+// set and set otherwise, as that is account's default. rippled deletes the
+// line once both sides are in the default state and the balance is zero.
+func NewTrustLineRemoval(account Account, currency Currency, issuer Account, issuerDefaultRipple bool) (*TransactionWithMetaData, error) {
+	switch {
+	case currency.IsNative():
+		return nil, fmt.Errorf("XRP has no trust line")
+	case account.Equals(issuer):
+		return nil, fmt.Errorf("Trust line to self: %s", account)
+	}
+	var (
+		txm                   = NewTransactionWithMetadata(TRUST_SET)
+		tx                    = txm.Transaction.(*TrustSet)
+		flags                 = TxSetNoRipple | TxClearFreeze
+		qualityIn, qualityOut uint32
+	)
+	if issuerDefaultRipple {
+		flags = TxClearNoRipple | TxClearFreeze
+	}
+	tx.Account = account
+	tx.Flags = &flags
+	tx.LimitAmount = *newAmount(zeroNonNative.Clone(), currency, issuer)
+	tx.QualityIn, tx.QualityOut = &qualityIn, &qualityOut
+	return txm, nil
+}
+
+// NewOfferCreate returns an OfferCreate by account for takerGets in
+// exchange for takerPays. The amounts must be positive and of different assets.
+func NewOfferCreate(account Account, takerPays, takerGets Amount) (*TransactionWithMetaData, error) {
+	for _, amount := range []struct {
+		name  string
+		value Amount
+	}{{"TakerPays", takerPays}, {"TakerGets", takerGets}} {
+		switch {
+		case amount.value.Value == nil:
+			return nil, fmt.Errorf("OfferCreate missing %s", amount.name)
+		case amount.value.IsZero() || !amount.value.IsPositive():
+			return nil, fmt.Errorf("OfferCreate %s must be positive: %s", amount.name, amount.value)
+		}
+	}
+	if *takerPays.Asset() == *takerGets.Asset() {
+		return nil, fmt.Errorf("OfferCreate TakerPays and TakerGets are both %s", takerPays.Asset())
+	}
+	txm := NewTransactionWithMetadata(OFFER_CREATE)
+	tx := txm.Transaction.(*OfferCreate)
+	tx.Account = account
+	tx.TakerPays, tx.TakerGets = *takerPays.Clone(), *takerGets.Clone()
+	return txm, nil
+}
+
+// NewOfferCancel returns an OfferCancel by account of the offer created
+// by its transaction with offerSequence
+func NewOfferCancel(account Account, offerSequence uint32) (*TransactionWithMetaData, error) {
+	if offerSequence == 0 {
+		return nil, fmt.Errorf("OfferCancel requires an OfferSequence")
+	}
+	txm := NewTransactionWithMetadata(OFFER_CANCEL)
+	tx := txm.Transaction.(*OfferCancel)
+	tx.Account = account
+	tx.OfferSequence = offerSequence
+	return txm, nil
+}
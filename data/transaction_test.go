@@ -0,0 +1,932 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/atticlab/ripple/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	. "gopkg.in/check.v1"
+)
+
+type TransactionSuite struct{}
+
+var _ = Suite(&TransactionSuite{})
+
+func partialPayment(amount, deliverMin string, flags TransactionFlag) *Payment {
+	payment := TxFactory[PAYMENT]().(*Payment)
+	payment.Flags = &flags
+	payment.Amount = *amountCheck(amount)
+	if deliverMin != "" {
+		payment.DeliverMin = amountCheck(deliverMin)
+	}
+	return payment
+}
+
+func (s *TransactionSuite) TestValidatePartial(c *C) {
+	const issuer = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	valid := []*Payment{
+		partialPayment("10/USD/"+issuer, "", 0),
+		partialPayment("10/USD/"+issuer, "5/USD/"+issuer, TxPartialPayment),
+		partialPayment("10/XRP", "5/XRP", TxPartialPayment|TxCanonicalSignature),
+	}
+	for _, payment := range valid {
+		c.Check(payment.ValidatePartial(), IsNil)
+	}
+	c.Check(partialPayment("10/USD/"+issuer, "5/USD/"+issuer, 0).ValidatePartial(), ErrorMatches, "DeliverMin requires the PartialPayment flag")
+	c.Check(partialPayment("10/USD/"+issuer, "5/EUR/"+issuer, TxPartialPayment).ValidatePartial(), ErrorMatches, "DeliverMin currency EUR does not match Amount currency USD")
+	c.Check(partialPayment("10/USD/"+issuer, "5/XRP", TxPartialPayment).ValidatePartial(), ErrorMatches, "DeliverMin currency XRP does not match Amount currency USD")
+}
+
+func signedBy(c *C, account Account, passphrase string) *TransactionWithMetaData {
+	seed, err := crypto.GenerateFamilySeed(passphrase)
+	c.Assert(err, IsNil)
+	key, err := crypto.NewECDSAKey(seed.Payload())
+	c.Assert(err, IsNil)
+	txm := NewTransactionWithMetadata(PAYMENT)
+	txm.GetBase().Account = account
+	txm.GetBase().SigningPubKey = new(PublicKey)
+	var sequence uint32
+	copy(txm.GetBase().SigningPubKey[:], key.Public(&sequence))
+	return txm
+}
+
+func (s *TransactionSuite) TestPrecheckSigning(c *C) {
+	root, err := NewAccountFromAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Assert(err, IsNil)
+
+	master := signedBy(c, *root, "masterpassphrase")
+	c.Check(master.PrecheckSigning(false), IsNil)
+	c.Check(master.PrecheckSigning(true), ErrorMatches, "Master key is disabled for rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh, sign with the regular key")
+
+	regular := signedBy(c, *root, "alice")
+	c.Check(regular.PrecheckSigning(false), IsNil)
+	c.Check(regular.PrecheckSigning(true), IsNil)
+
+	multi := NewTransactionWithMetadata(PAYMENT)
+	multi.GetBase().Account = *root
+	multi.GetBase().SigningPubKey = new(PublicKey)
+	c.Check(multi.PrecheckSigning(true), IsNil)
+}
+
+func (s *TransactionSuite) TestAMMBidRoundTrip(c *C) {
+	const bid = `{
+		"TransactionType": "AMMBid",
+		"Account": "rJVUeRqDFNs2xqA7ncVE6ZoAhPUoaJJSQm",
+		"Sequence": 5,
+		"Fee": "10",
+		"Asset": {"currency": "XRP"},
+		"Asset2": {"currency": "TST", "issuer": "rP9jPyP5kyvFRb6ZiRghAGw5u8SGAmU4bd"},
+		"BidMin": {"currency": "039C99CD9AB0B70B32ECDA51EAAE471625608EA2", "issuer": "rE54zDvgnghAoPopCgvtiqWNq3dU5y836S", "value": "100"},
+		"BidMax": {"currency": "039C99CD9AB0B70B32ECDA51EAAE471625608EA2", "issuer": "rE54zDvgnghAoPopCgvtiqWNq3dU5y836S", "value": "110"},
+		"AuthAccounts": [
+			{"AuthAccount": {"Account": "rMKXGCbJ5d8LbrqthdG46q3f969MVK2Qeg"}},
+			{"AuthAccount": {"Account": "rBepJuTLFJt3WmtLXYAxSjtBWAeQxVbncv"}}
+		]
+	}`
+	var tx AMMBid
+	c.Assert(json.Unmarshal([]byte(bid), &tx), IsNil)
+	c.Assert(tx.GetTransactionType(), Equals, AMM_BID)
+	c.Assert(tx.AuthAccounts, HasLen, 2)
+	c.Assert(tx.Asset.Currency.IsNative(), Equals, true)
+
+	_, raw, err := Raw(&tx)
+	c.Assert(err, IsNil)
+	first, second := tx.AuthAccounts[0].AuthAccount.Account, tx.AuthAccounts[1].AuthAccount.Account
+	// Array order is preserved, each entry is an AuthAccount object
+	authAccounts := "F019" + "E01B8114" + string(b2h(first[:])) + "E1" + "E01B8114" + string(b2h(second[:])) + "E1" + "F1"
+	encoded := string(b2h(raw))
+	c.Assert(strings.Contains(encoded, authAccounts), Equals, true)
+	// Issues sort after arrays in canonical order
+	c.Assert(strings.HasSuffix(encoded, authAccounts+"0318"+strings.Repeat("0", 40)+"0418"+string(b2h(tx.Asset2.Currency[:]))+string(b2h(tx.Asset2.Issuer[:]))), Equals, true)
+
+	decoded, err := ReadTransaction(bytes.NewReader(raw))
+	c.Assert(err, IsNil)
+	ammBid, ok := decoded.(*AMMBid)
+	c.Assert(ok, Equals, true)
+	c.Assert(ammBid.AuthAccounts, DeepEquals, tx.AuthAccounts)
+	c.Assert(ammBid.Asset, Equals, tx.Asset)
+	c.Assert(ammBid.Asset2, Equals, tx.Asset2)
+	c.Assert(ammBid.BidMin.String(), Equals, tx.BidMin.String())
+	c.Assert(ammBid.BidMax.String(), Equals, tx.BidMax.String())
+	_, reencoded, err := Raw(ammBid)
+	c.Assert(err, IsNil)
+	c.Assert(reencoded, DeepEquals, raw)
+
+	out, err := json.Marshal(ammBid)
+	c.Assert(err, IsNil)
+	var again AMMBid
+	c.Assert(json.Unmarshal(out, &again), IsNil)
+	c.Assert(again.AuthAccounts, DeepEquals, tx.AuthAccounts)
+	c.Assert(again.Asset2, Equals, tx.Asset2)
+}
+
+func familyKey(c *C, passphrase string) crypto.Key {
+	seed, err := crypto.GenerateFamilySeed(passphrase)
+	c.Assert(err, IsNil)
+	key, err := crypto.NewECDSAKey(seed.Payload())
+	c.Assert(err, IsNil)
+	return key
+}
+
+func multiSignPayment(c *C) *Payment {
+	root, err := NewAccountFromAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Assert(err, IsNil)
+	payment := partialPayment("10/XRP", "", 0)
+	payment.Account = *root
+	payment.Destination = *root
+	payment.Sequence = 1
+	payment.Fee = *amountCheck("40/XRP").Value
+	return payment
+}
+
+func signerAccounts(signers Signers) []string {
+	var accounts []string
+	for _, signer := range signers {
+		accounts = append(accounts, signer.Signer.Account.String())
+	}
+	return accounts
+}
+
+// Sorted by account id
+var sortedSigners = []string{
+	"rG1QQv2nh2gr7RCZ1P8YYcBUKCCN633jCn", // alice AE123A85...
+	"rH4KEcG9dEwGwpn6AyoWK9cZPLL4RLSmWW", // carol B389FBCE...
+	"rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK", // bob F51DFC2A...
+}
+
+func (s *TransactionSuite) TestSignForSortsSigners(c *C) {
+	payment := multiSignPayment(c)
+	var sequence uint32
+	for _, passphrase := range []string{"alice", "bob", "carol"} {
+		c.Assert(SignFor(payment, familyKey(c, passphrase), &sequence), IsNil)
+	}
+	c.Assert(payment.SigningPubKey.IsZero(), Equals, true)
+
+	_, raw, err := Raw(payment)
+	c.Assert(err, IsNil)
+	decoded, err := ReadTransaction(bytes.NewReader(raw))
+	c.Assert(err, IsNil)
+	c.Assert(signerAccounts(decoded.GetBase().Signers), DeepEquals, sortedSigners)
+	// The Signers of the transaction are left in the order they were added
+	c.Assert(signerAccounts(payment.Signers), DeepEquals, []string{sortedSigners[0], sortedSigners[2], sortedSigners[1]})
+	_, again, err := Raw(decoded)
+	c.Assert(err, IsNil)
+	c.Assert(again, DeepEquals, raw)
+}
+
+func (s *TransactionSuite) TestMultiSign(c *C) {
+	var signers []Signer
+	var sequence uint32
+	for _, passphrase := range []string{"bob", "alice", "carol"} {
+		key := familyKey(c, passphrase)
+		var account Account
+		var publicKey PublicKey
+		copy(account[:], key.Id(&sequence))
+		copy(publicKey[:], key.Public(&sequence))
+		signers = append(signers, NewSigner(account, publicKey, VariableLength{0x30, 0x01}))
+	}
+	payment := multiSignPayment(c)
+	payment.SigningPubKey = new(PublicKey)
+	copy(payment.SigningPubKey[:], signers[0].Signer.SigningPubKey[:])
+	c.Assert(MultiSign(payment, signers...), IsNil)
+	c.Assert(signerAccounts(payment.Signers), DeepEquals, sortedSigners)
+	c.Assert(payment.SigningPubKey.IsZero(), Equals, true)
+	c.Assert(payment.TxnSignature, IsNil)
+	c.Assert(payment.Hash.IsZero(), Equals, false)
+	// The inputs are left untouched
+	c.Assert(signers[0].Signer.Account.String(), Equals, "rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK")
+
+	c.Check(MultiSign(multiSignPayment(c)), ErrorMatches, "MultiSign requires at least one signer")
+	c.Check(MultiSign(multiSignPayment(c), signers[0], signers[1], signers[0]), ErrorMatches, "Duplicate signer: rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK")
+}
+
+func (s *TransactionSuite) TestCombine(c *C) {
+	var partials []Transaction
+	var sequence uint32
+	for _, passphrase := range []string{"bob", "carol", "alice"} {
+		payment := multiSignPayment(c)
+		c.Assert(SignFor(payment, familyKey(c, passphrase), &sequence), IsNil)
+		partials = append(partials, payment)
+	}
+	blob, err := Combine(partials...)
+	c.Assert(err, IsNil)
+	combined, err := DecodeTxBlob(blob)
+	c.Assert(err, IsNil)
+	c.Assert(signerAccounts(combined.GetBase().Signers), DeepEquals, sortedSigners)
+	c.Assert(combined.GetBase().SigningPubKey.IsZero(), Equals, true)
+	// Each signature is still valid for its signer
+	for _, signer := range combined.GetBase().Signers {
+		hash, msg, err := SigningHash(combined.Transaction, signer.Signer.Account.Bytes())
+		c.Assert(err, IsNil)
+		prefix := combined.Transaction.SigningPrefix().Bytes()
+		ok, err := crypto.Verify(signer.Signer.SigningPubKey.Bytes(), hash.Bytes(), append(prefix, msg...), signer.Signer.TxnSignature.Bytes())
+		c.Assert(err, IsNil)
+		c.Check(ok, Equals, true, Commentf(signer.Signer.Account.String()))
+	}
+	c.Assert(partials[0].GetBase().Signers, HasLen, 1)
+
+	_, err = Combine()
+	c.Check(err, ErrorMatches, "Combine requires at least one transaction")
+	other := multiSignPayment(c)
+	other.Sequence = 2
+	c.Assert(SignFor(other, familyKey(c, "alice"), &sequence), IsNil)
+	_, err = Combine(partials[0], other)
+	c.Check(err, ErrorMatches, "Transaction 1 differs from the first: .*")
+	_, err = Combine(partials[2], partials[2])
+	c.Check(err, ErrorMatches, "Duplicate signer: rG1QQv2nh2gr7RCZ1P8YYcBUKCCN633jCn")
+}
+
+func (s *TransactionSuite) TestMultiSignedRequiresEmptySigningPubKey(c *C) {
+	payment := multiSignPayment(c)
+	var sequence uint32
+	key := familyKey(c, "alice")
+	c.Assert(SignFor(payment, key, &sequence), IsNil)
+	copy(payment.SigningPubKey[:], key.Public(&sequence))
+	_, _, err := Raw(payment)
+	c.Check(err, ErrorMatches, "Multi-signed transaction must have an empty SigningPubKey")
+}
+
+func (s *TransactionSuite) TestMultisignFee(c *C) {
+	c.Check(MultisignFee(10, 1), Equals, uint64(20))
+	c.Check(MultisignFee(10, 3), Equals, uint64(40))
+	c.Check(MultisignFee(12, 8), Equals, uint64(108))
+}
+
+func (s *TransactionSuite) TestVerifyHash(c *C) {
+	b, err := ioutil.ReadFile("testdata/transaction_payment_with_rippling.json")
+	c.Assert(err, IsNil)
+	var txm TransactionWithMetaData
+	c.Assert(json.Unmarshal(b, &txm), IsNil)
+	hash, err := txm.ComputeHash()
+	c.Assert(err, IsNil)
+	c.Assert(hash, Equals, *txm.GetHash())
+	c.Assert(txm.VerifyHash(), IsNil)
+
+	corrupted := bytes.Replace(b, []byte(`"DestinationTag": `), []byte(`"DestinationTag": 1`), 1)
+	c.Assert(corrupted, Not(DeepEquals), b)
+	var tampered TransactionWithMetaData
+	c.Assert(json.Unmarshal(corrupted, &tampered), IsNil)
+	c.Assert(tampered.GetHash().String(), Equals, txm.GetHash().String())
+	c.Assert(tampered.VerifyHash(), ErrorMatches, "Bad transaction hash: "+txm.GetHash().String()+" expected: [0-9A-F]{64}")
+}
+
+func (s *TransactionSuite) TestDecodeTxBlob(c *C) {
+	txm := readTransactionWithMetaData(c, "testdata/transaction_payment_with_rippling.json")
+	_, raw, err := Raw(txm.Transaction)
+	c.Assert(err, IsNil)
+
+	decoded, err := DecodeTxBlob(fmt.Sprintf("%X", raw))
+	c.Assert(err, IsNil)
+	c.Assert(decoded.GetHash().String(), Equals, txm.GetHash().String())
+	payment, ok := decoded.Transaction.(*Payment)
+	c.Assert(ok, Equals, true)
+	c.Assert(payment.Account.String(), Equals, "rGgj3GurcrAqgBXGVoS9wvQG3Hjkj5oCbj")
+	c.Assert(payment.Destination.String(), Equals, "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(*payment.DestinationTag, Equals, uint32(54025705))
+	c.Assert(payment.Amount.String(), Equals, "20/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(payment.Fee.String(), Equals, "0.000012")
+	c.Assert(payment.Fee.XRP(), Equals, "0.000012")
+	c.Assert(payment.Fee.Drops(), Equals, uint64(12))
+	c.Assert(txm.GetBase().Fee.Drops(), Equals, uint64(12))
+	c.Assert(*payment.Paths, HasLen, 4)
+	c.Assert(decoded.VerifyHash(), IsNil)
+
+	_, err = DecodeTxBlob("not hex")
+	c.Assert(err, ErrorMatches, "Bad tx_blob: .*")
+	_, err = DecodeTxBlob("")
+	c.Assert(err, NotNil)
+}
+
+func (s *TransactionSuite) TestEncodeTxBlob(c *C) {
+	// A payment of 1 USD signed with the Ed25519 key of masterpassphrase
+	const blob = "1200002280000000240000000361D4838D7EA4C6800000000000000000000000000055534400000000000A20B3C85F482532A9578DBB3950B85CA06594D168400000000000000C7321EDAAC3F98BB94F451804EF5993C847DAAA4E6154F455635659D88AA5C80F15630374405A910EA4188BA38E8D2E0533501A1027634BB0DB71267D6EB3628745588B589B6853BAFB7DBEA94D7C56DE58CF3695C69EFAE7D6311C77B173D502C3561F330A8114AA066C988C712815CC37AF71472B7CBBBD4E2A0A83140A20B3C85F482532A9578DBB3950B85CA06594D1"
+	txm, err := DecodeTxBlob(blob)
+	c.Assert(err, IsNil)
+	payment, ok := txm.Transaction.(*Payment)
+	c.Assert(ok, Equals, true)
+	c.Assert(txm.GetHash().String(), Equals, "FCE22BDDDAD667F8A271753FA4A19E5FE60783CBBA18FF223B9184EBA3BB4042")
+	c.Assert(payment.Amount.String(), Equals, "1/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(payment.Account.String(), Equals, "rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf")
+	valid, err := CheckSignature(payment)
+	c.Assert(err, IsNil)
+	c.Assert(valid, Equals, true)
+	encoded, err := txm.EncodeTxBlob()
+	c.Assert(err, IsNil)
+	c.Assert(encoded, Equals, blob)
+
+	_, err = (&TransactionWithMetaData{}).EncodeTxBlob()
+	c.Assert(err, ErrorMatches, "No transaction to encode")
+}
+
+func (s *TransactionSuite) TestSignDecodeRoundTrip(c *C) {
+	seed, err := crypto.GenerateFamilySeed("masterpassphrase")
+	c.Assert(err, IsNil)
+	ecdsa, err := crypto.NewECDSAKey(seed.Payload())
+	c.Assert(err, IsNil)
+	ed25519, err := crypto.NewEd25519Key(seed.Payload())
+	c.Assert(err, IsNil)
+	var zero uint32
+	for _, test := range []struct {
+		key      crypto.Key
+		sequence *uint32
+	}{{ecdsa, &zero}, {ed25519, nil}} {
+		payment := multiSignPayment(c)
+		tag := uint32(42)
+		payment.DestinationTag = &tag
+		copy(payment.Account[:], test.key.Id(test.sequence))
+		c.Assert(Sign(payment, test.key, test.sequence), IsNil)
+		_, raw, err := Raw(payment)
+		c.Assert(err, IsNil)
+
+		decoded, err := DecodeTxBlob(fmt.Sprintf("%X", raw))
+		c.Assert(err, IsNil)
+		again, ok := decoded.Transaction.(*Payment)
+		c.Assert(ok, Equals, true)
+		c.Assert(*again.GetHash(), Equals, *payment.GetHash())
+		c.Assert(again.Account, Equals, payment.Account)
+		c.Assert(again.Destination, Equals, payment.Destination)
+		c.Assert(*again.DestinationTag, Equals, tag)
+		c.Assert(again.Amount.String(), Equals, payment.Amount.String())
+		c.Assert(again.Fee.String(), Equals, payment.Fee.String())
+		c.Assert(again.Sequence, Equals, payment.Sequence)
+		c.Assert(*again.SigningPubKey, Equals, *payment.SigningPubKey)
+		c.Assert(*again.TxnSignature, DeepEquals, *payment.TxnSignature)
+
+		valid, err := CheckSignature(again)
+		c.Assert(err, IsNil)
+		c.Assert(valid, Equals, true)
+
+		again.Sequence++
+		valid, _ = CheckSignature(again)
+		c.Assert(valid, Equals, false)
+	}
+}
+
+// Examples from https://xrpl.org/nftokenmint.html and https://xrpl.org/nftokencreateoffer.html
+var nftokenTransactions = []string{
+	`{"TransactionType":"NFTokenMint","Account":"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B","TransferFee":314,"NFTokenTaxon":0,"Flags":8,"Fee":"10","Sequence":2,"URI":"697066733A2F2F62616679626569676479727A74357366703775646D37687537367568377932366E6634646675796C71616266336F636C67747179353566627A6469"}`,
+	`{"TransactionType":"NFTokenCreateOffer","Account":"rs8jBmmfpwgmrSPgwMsh7CvKRmRt1JTVSX","Owner":"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B","NFTokenID":"000100001E962F495F07A990F4ED55ACCFEEF365DBAA76B6A048C0A200000007","Amount":"100","Flags":1,"Destination":"ra5nK24KXen9AHvsdFTKHSANinZseWnPcX","Expiration":533257958,"Fee":"10","Sequence":3}`,
+	`{"TransactionType":"NFTokenBurn","Account":"rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B","NFTokenID":"000100001E962F495F07A990F4ED55ACCFEEF365DBAA76B6A048C0A200000007","Fee":"10","Sequence":4}`,
+	`{"TransactionType":"NFTokenCancelOffer","Account":"ra5nK24KXen9AHvsdFTKHSANinZseWnPcX","NFTokenOffers":["9C92E061381C1EF37A8CDE0E8FC35188BFC30B1883825042A64309AC09F4C36D"],"Fee":"10","Sequence":5}`,
+	`{"TransactionType":"NFTokenAcceptOffer","Account":"r9spUPhPBfB6kQeF6vPhwmtFwRhBh2JUCG","NFTokenSellOffer":"68CD1F6F906494EA08C9CB5CAFA64DFA90D4E834B7151899B73231DE5A0C3B77","Fee":"10","Sequence":6}`,
+}
+
+func (s *TransactionSuite) TestNFTokenRoundTrip(c *C) {
+	for _, test := range nftokenTransactions {
+		var txm TransactionWithMetaData
+		c.Assert(json.Unmarshal([]byte(test), &txm), IsNil, Commentf(test))
+		_, raw, err := Raw(txm.Transaction)
+		c.Assert(err, IsNil, Commentf(test))
+		decoded, err := ReadTransaction(bytes.NewReader(raw))
+		c.Assert(err, IsNil, Commentf(test))
+		c.Assert(decoded, DeepEquals, txm.Transaction, Commentf(test))
+
+		out, err := json.Marshal(decoded)
+		c.Assert(err, IsNil)
+		var expected, obtained map[string]interface{}
+		c.Assert(json.Unmarshal([]byte(test), &expected), IsNil)
+		c.Assert(json.Unmarshal(out, &obtained), IsNil)
+		delete(obtained, "hash")
+		c.Assert(obtained, DeepEquals, expected, Commentf(test))
+	}
+
+	var mint TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(nftokenTransactions[0]), &mint), IsNil)
+	minted := mint.Transaction.(*NFTokenMint)
+	c.Assert(*minted.TransferFee, Equals, uint16(314))
+	c.Assert(string(*minted.URI), Equals, "ipfs://bafybeigdyrzt5sfp7udm7hu76uh7y26nf4dfuylqabf3oclgtqy55fbzdi")
+	c.Assert(minted.Flags.Explain(minted), DeepEquals, []string{"Transferable"})
+
+	var offer TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(nftokenTransactions[1]), &offer), IsNil)
+	created := offer.Transaction.(*NFTokenCreateOffer)
+	c.Assert(created.NFTokenID.String(), Equals, "000100001E962F495F07A990F4ED55ACCFEEF365DBAA76B6A048C0A200000007")
+	c.Assert(created.Owner.String(), Equals, "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(*created.Flags&TxSellNFToken, Equals, TxSellNFToken)
+}
+
+var escrowTransactions = []string{
+	`{"TransactionType":"EscrowCreate","Account":"rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn","Fee":"12","Flags":0,"Sequence":7,"SigningPubKey":"","Amount":"10000","Destination":"rsA2LpzuawewSBQXkiju3YQTMzW13pAAdW","CancelAfter":533257958,"FinishAfter":533171558,"Condition":"A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810100","DestinationTag":23480}`,
+	`{"TransactionType":"EscrowFinish","Account":"rsA2LpzuawewSBQXkiju3YQTMzW13pAAdW","Fee":"3300","Flags":0,"Sequence":2,"SigningPubKey":"","Owner":"rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn","OfferSequence":7,"Condition":"A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810100","Fulfillment":"A0028000"}`,
+	`{"TransactionType":"EscrowCancel","Account":"rsA2LpzuawewSBQXkiju3YQTMzW13pAAdW","Fee":"12","Flags":0,"Sequence":3,"SigningPubKey":"","Owner":"rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn","OfferSequence":7}`,
+}
+
+func (s *TransactionSuite) TestEscrowRoundTrip(c *C) {
+	for _, test := range escrowTransactions {
+		var txm TransactionWithMetaData
+		c.Assert(json.Unmarshal([]byte(test), &txm), IsNil, Commentf(test))
+		_, raw, err := Raw(txm.Transaction)
+		c.Assert(err, IsNil, Commentf(test))
+		decoded, err := ReadTransaction(bytes.NewReader(raw))
+		c.Assert(err, IsNil, Commentf(test))
+		c.Assert(decoded, DeepEquals, txm.Transaction, Commentf(test))
+
+		out, err := json.Marshal(decoded)
+		c.Assert(err, IsNil)
+		var expected, obtained map[string]interface{}
+		c.Assert(json.Unmarshal([]byte(test), &expected), IsNil)
+		c.Assert(json.Unmarshal(out, &obtained), IsNil)
+		delete(obtained, "hash")
+		c.Assert(obtained, DeepEquals, expected, Commentf(test))
+	}
+
+	var create, finish TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(escrowTransactions[0]), &create), IsNil)
+	c.Assert(json.Unmarshal([]byte(escrowTransactions[1]), &finish), IsNil)
+	created, finished := create.Transaction.(*EscrowCreate), finish.Transaction.(*EscrowFinish)
+	escrow := Escrow{Condition: created.Condition}
+	ok, err := escrow.CanFinish(RippleTime{0}, finished.Fulfillment.Bytes())
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+
+	le, err := DecodeLedgerEntry([]byte(`{"LedgerEntryType":"Escrow","Account":"rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn","Destination":"rsA2LpzuawewSBQXkiju3YQTMzW13pAAdW","Amount":"10000","Flags":0,"OwnerNode":"0000000000000000","DestinationNode":"0000000000000002","index":"DC5F3851D8A1AB622F957761E5963BC5BD439D5C24AC6AD7AC4523F0640244AC"}`))
+	c.Assert(err, IsNil)
+	c.Check(uint64(*le.(*Escrow).DestinationNode), Equals, uint64(2))
+	var raw bytes.Buffer
+	c.Assert(encode(&raw, le, false), IsNil)
+	c.Check(fmt.Sprintf("%X", raw.Bytes()), Matches, ".*390000000000000002.*")
+}
+
+var checkTransactions = []string{
+	`{"TransactionType":"CheckCreate","Account":"rUn84CUYbNjRoTQ6mSW7BVJPSVJNLb1QLo","Fee":"12","Flags":0,"Sequence":2,"SigningPubKey":"","Destination":"rfkE1aSy9G8Upk4JssnwBxhEv5p4mn2KTy","SendMax":"100000000","Expiration":570113521,"InvoiceID":"6F1DFD1D0FE8A32E40E1F2C05CF1C15545BAB56B617F9C6C2D63A6B704BEF59B","DestinationTag":1}`,
+	`{"TransactionType":"CheckCash","Account":"rfkE1aSy9G8Upk4JssnwBxhEv5p4mn2KTy","Fee":"12","Flags":0,"Sequence":3,"SigningPubKey":"","CheckID":"838766BA2B995C00744175F69A1B11E32C3DBC40E64801A4056FCBD657F57334","Amount":"100000000"}`,
+	`{"TransactionType":"CheckCash","Account":"rfkE1aSy9G8Upk4JssnwBxhEv5p4mn2KTy","Fee":"12","Flags":0,"Sequence":4,"SigningPubKey":"","CheckID":"838766BA2B995C00744175F69A1B11E32C3DBC40E64801A4056FCBD657F57334","DeliverMin":{"value":"1","currency":"USD","issuer":"rUn84CUYbNjRoTQ6mSW7BVJPSVJNLb1QLo"}}`,
+	`{"TransactionType":"CheckCancel","Account":"rUn84CUYbNjRoTQ6mSW7BVJPSVJNLb1QLo","Fee":"12","Flags":0,"Sequence":5,"SigningPubKey":"","CheckID":"49647F0D748DC3FE26BDACBC57F251AADEFFF391403EC9BF87C97F67E9977FB0"}`,
+}
+
+func (s *TransactionSuite) TestCheckRoundTrip(c *C) {
+	for _, test := range checkTransactions {
+		var txm TransactionWithMetaData
+		c.Assert(json.Unmarshal([]byte(test), &txm), IsNil, Commentf(test))
+		_, raw, err := Raw(txm.Transaction)
+		c.Assert(err, IsNil, Commentf(test))
+		decoded, err := ReadTransaction(bytes.NewReader(raw))
+		c.Assert(err, IsNil, Commentf(test))
+		c.Assert(decoded, DeepEquals, txm.Transaction, Commentf(test))
+		_, again, err := Raw(decoded)
+		c.Assert(err, IsNil)
+		c.Assert(again, DeepEquals, raw, Commentf(test))
+
+		out, err := json.Marshal(decoded)
+		c.Assert(err, IsNil)
+		var expected, obtained map[string]interface{}
+		c.Assert(json.Unmarshal([]byte(test), &expected), IsNil)
+		c.Assert(json.Unmarshal(out, &obtained), IsNil)
+		delete(obtained, "hash")
+		c.Assert(obtained, DeepEquals, expected, Commentf(test))
+	}
+
+	// The metadata of the CheckCreate
+	var meta MetaData
+	c.Assert(json.Unmarshal([]byte(`{"AffectedNodes":[{"CreatedNode":{"LedgerEntryType":"Check","LedgerIndex":"49647F0D748DC3FE26BDACBC57F251AADEFFF391403EC9BF87C97F67E9977FB0","NewFields":{"Account":"rUn84CUYbNjRoTQ6mSW7BVJPSVJNLb1QLo","Destination":"rfkE1aSy9G8Upk4JssnwBxhEv5p4mn2KTy","DestinationNode":"0000000000000000","DestinationTag":1,"Expiration":570113521,"InvoiceID":"46060241FABCF692D4D934BA2A6C4427CD4279083E38C77CBE642243E43BE291","SendMax":"100000000","Sequence":2}}}],"TransactionIndex":0,"TransactionResult":"tesSUCCESS"}`), &meta), IsNil)
+	check, ok := meta.AffectedNodes[0].CreatedNode.NewFields.(*Check)
+	c.Assert(ok, Equals, true)
+	c.Check(check.InvoiceID.String(), Equals, "46060241FABCF692D4D934BA2A6C4427CD4279083E38C77CBE642243E43BE291")
+	c.Check(*check.DestinationTag, Equals, uint32(1))
+	var raw bytes.Buffer
+	c.Assert(encode(&raw, &meta, false), IsNil)
+	var decoded MetaData
+	v := reflect.ValueOf(&decoded)
+	c.Assert(readObject(bytes.NewReader(raw.Bytes()), &v), IsNil)
+	c.Assert(decoded.AffectedNodes[0].CreatedNode.NewFields, DeepEquals, meta.AffectedNodes[0].CreatedNode.NewFields)
+	var again bytes.Buffer
+	c.Assert(encode(&again, &decoded, false), IsNil)
+	c.Check(again.Bytes(), DeepEquals, raw.Bytes())
+}
+
+func (s *TransactionSuite) TestTickets(c *C) {
+	for _, test := range []string{
+		`{"TransactionType":"TicketCreate","Account":"rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de","Fee":"10","Flags":0,"Sequence":381,"SigningPubKey":"","TicketCount":10}`,
+		`{"TransactionType":"AccountSet","Account":"rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de","Fee":"10","Flags":0,"Sequence":0,"TicketSequence":383,"SigningPubKey":""}`,
+	} {
+		var txm TransactionWithMetaData
+		c.Assert(json.Unmarshal([]byte(test), &txm), IsNil, Commentf(test))
+		_, raw, err := Raw(txm.Transaction)
+		c.Assert(err, IsNil, Commentf(test))
+		decoded, err := ReadTransaction(bytes.NewReader(raw))
+		c.Assert(err, IsNil, Commentf(test))
+		c.Assert(decoded, DeepEquals, txm.Transaction, Commentf(test))
+
+		out, err := json.Marshal(decoded)
+		c.Assert(err, IsNil)
+		var expected, obtained map[string]interface{}
+		c.Assert(json.Unmarshal([]byte(test), &expected), IsNil)
+		c.Assert(json.Unmarshal(out, &obtained), IsNil)
+		delete(obtained, "hash")
+		c.Assert(obtained, DeepEquals, expected, Commentf(test))
+	}
+
+	seed, err := crypto.NewSeed("snoPBrXtMeMyMHUVTgbuqAfg1SUTb")
+	c.Assert(err, IsNil)
+	keyPair, err := seed.DeriveKeyPair(crypto.ECDSA, 0)
+	c.Assert(err, IsNil)
+	payment := multiSignPayment(c)
+	sequence, ticket := payment.SequenceOrTicket()
+	c.Check(sequence, Equals, uint32(1))
+	c.Check(ticket, Equals, false)
+	payment.UseTicket(42)
+	sequence, ticket = payment.SequenceOrTicket()
+	c.Check(sequence, Equals, uint32(42))
+	c.Check(ticket, Equals, true)
+	txm := &TransactionWithMetaData{Transaction: payment}
+	c.Assert(txm.Sign(keyPair, false), IsNil)
+	blob, err := txm.EncodeTxBlob()
+	c.Assert(err, IsNil)
+	decoded, err := DecodeTxBlob(blob)
+	c.Assert(err, IsNil)
+	c.Check(decoded.GetBase().Sequence, Equals, uint32(0))
+	c.Check(*decoded.GetBase().TicketSequence, Equals, uint32(42))
+	valid, err := CheckSignature(decoded.Transaction)
+	c.Assert(err, IsNil)
+	c.Check(valid, Equals, true)
+
+	le, err := DecodeLedgerEntry([]byte(`{"Account":"rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de","Flags":0,"LedgerEntryType":"Ticket","OwnerNode":"0000000000000000","PreviousTxnID":"F19AD4577212D3BEACA0F75FE1BA1644F2E854D46E8D62E9C95D18E9708CBFB1","PreviousTxnLgrSeq":4,"TicketSequence":3}`))
+	c.Assert(err, IsNil)
+	index, err := LedgerIndex(le)
+	c.Assert(err, IsNil)
+	expected, err := GetTicketIndex(*le.(*Ticket).Account, 3)
+	c.Assert(err, IsNil)
+	c.Check(*index, Equals, *expected)
+}
+
+func (s *TransactionSuite) TestPaymentChannelClaim(c *C) {
+	publicKey, err := crypto.NewRippleHashCheck("aB44YfzW24VDEJQ2UuLPV2PvqcPCSoLnL7y5M1EzhdW4LnK5xMS3", crypto.RIPPLE_ACCOUNT_PUBLIC)
+	c.Assert(err, IsNil)
+	test := fmt.Sprintf(`{"TransactionType":"PaymentChannelClaim","Account":"rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn","Fee":"12","Flags":0,"Sequence":9,"SigningPubKey":"","Channel":"5DB01B7FFED6B67E6B0414DED11E051D2EE2B7619CE0EAA6286D67A3A4D5BDB3","Balance":"1000000","Signature":"304402204EF0AFB78AC23ED1C472E74F4299C0C21F1B21D07EFC0A3838A420F76D783A400220154FB11B6F54320666E4C36CA7F686C16A3A0456800BBC43746F34AF50290064","PublicKey":"%X"}`, publicKey.Payload())
+	var txm TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(test), &txm), IsNil)
+	_, raw, err := Raw(txm.Transaction)
+	c.Assert(err, IsNil)
+	decoded, err := ReadTransaction(bytes.NewReader(raw))
+	c.Assert(err, IsNil)
+	c.Assert(decoded, DeepEquals, txm.Transaction)
+
+	claim := decoded.(*PaymentChannelClaim)
+	ok, err := claim.CheckClaim()
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	claim.Balance = amountCheck("999999")
+	ok, err = claim.CheckClaim()
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	claim.Balance = amountCheck("1/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	_, err = claim.CheckClaim()
+	c.Check(err, ErrorMatches, "PaymentChannelClaim Balance must be XRP: .*")
+	claim.Signature = nil
+	_, err = claim.CheckClaim()
+	c.Check(err, ErrorMatches, "PaymentChannelClaim has no signed claim")
+}
+
+func (s *TransactionSuite) TestOperationLimit(c *C) {
+	payment := partialPayment("10/XRP", "", 0)
+	_, without, err := Raw(payment)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(b2h(without)), "201D"), Equals, false)
+
+	limit := uint32(1000)
+	payment.OperationLimit = &limit
+	_, with, err := Raw(payment)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(b2h(with)), "201D000003E8"), Equals, true)
+	c.Assert(len(with), Equals, len(without)+6)
+
+	decoded, err := ReadTransaction(bytes.NewReader(with))
+	c.Assert(err, IsNil)
+	c.Assert(*decoded.GetBase().OperationLimit, Equals, limit)
+
+	out, err := json.Marshal(payment)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(out), `"OperationLimit":1000`), Equals, true)
+	payment.OperationLimit = nil
+	out, err = json.Marshal(payment)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(out), "OperationLimit"), Equals, false)
+}
+
+func (s *TransactionSuite) TestNewTrustLineRemoval(c *C) {
+	account, err := NewAccountFromAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Assert(err, IsNil)
+	issuer, err := NewAccountFromAddress("rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(err, IsNil)
+	usd, err := NewCurrency("USD")
+	c.Assert(err, IsNil)
+
+	txm, err := NewTrustLineRemoval(*account, usd, *issuer, false)
+	c.Assert(err, IsNil)
+	tx := txm.Transaction.(*TrustSet)
+	c.Assert(tx.TransactionType, Equals, TRUST_SET)
+	c.Assert(tx.LimitAmount.IsZero(), Equals, true)
+	c.Assert(tx.LimitAmount.String(), Equals, "0/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(*tx.Flags, Equals, TransactionFlag(0x00220000))
+	c.Assert(*tx.QualityIn, Equals, uint32(0))
+	c.Assert(*tx.QualityOut, Equals, uint32(0))
+
+	tx.Sequence, tx.Fee = 1, *amountCheck("12/XRP").Value
+	_, raw, err := Raw(tx)
+	c.Assert(err, IsNil)
+	limit := "63" + "8000000000000000" + string(b2h(usd[:])) + string(b2h(issuer[:]))
+	c.Assert(strings.Contains(string(b2h(raw)), limit), Equals, true)
+	decoded, err := ReadTransaction(bytes.NewReader(raw))
+	c.Assert(err, IsNil)
+	again, ok := decoded.(*TrustSet)
+	c.Assert(ok, Equals, true)
+	c.Assert(again.LimitAmount.String(), Equals, tx.LimitAmount.String())
+	c.Assert(*again.Flags, Equals, *tx.Flags)
+	c.Assert(*again.QualityIn, Equals, uint32(0))
+
+	// NoRipple is cleared on lines to an issuer with DefaultRipple
+	txm, err = NewTrustLineRemoval(*account, usd, *issuer, true)
+	c.Assert(err, IsNil)
+	c.Assert(*txm.Transaction.GetBase().Flags, Equals, TxClearNoRipple|TxClearFreeze)
+
+	_, err = NewTrustLineRemoval(*account, zeroCurrency, *issuer, false)
+	c.Check(err, ErrorMatches, "XRP has no trust line")
+	_, err = NewTrustLineRemoval(*account, usd, *account, false)
+	c.Check(err, ErrorMatches, "Trust line to self: rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+}
+
+func (s *TransactionSuite) TestSignWithKeyPair(c *C) {
+	seed, err := crypto.NewSeed("snoPBrXtMeMyMHUVTgbuqAfg1SUTb")
+	c.Assert(err, IsNil)
+	for _, keyType := range []crypto.KeyType{crypto.ECDSA, crypto.Ed25519} {
+		keyPair, err := seed.DeriveKeyPair(keyType, 0)
+		c.Assert(err, IsNil)
+		account, err := keyPair.Account()
+		c.Assert(err, IsNil)
+		txm := &TransactionWithMetaData{Transaction: multiSignPayment(c)}
+		copy(txm.GetBase().Account[:], account.Payload())
+
+		c.Assert(txm.Sign(keyPair, false), IsNil)
+		c.Assert(txm.GetPublicKey().Bytes(), DeepEquals, keyPair.Public())
+		valid, err := CheckSignature(txm.Transaction)
+		c.Assert(err, IsNil)
+		c.Assert(valid, Equals, true)
+		hash, err := txm.ComputeHash()
+		c.Assert(err, IsNil)
+		c.Assert(*txm.GetHash(), Equals, hash)
+
+		c.Assert(txm.Sign(keyPair, false), ErrorMatches, "Transaction is already signed: "+hash.String())
+		txm.GetBase().Sequence++
+		c.Assert(txm.Sign(keyPair, true), IsNil)
+		c.Assert(*txm.GetHash(), Not(Equals), hash)
+		c.Assert(txm.VerifyHash(), IsNil)
+
+		// Forcing replaces the signatures of a multi-signed transaction
+		var sequence uint32
+		multiSigned := &TransactionWithMetaData{Transaction: multiSignPayment(c)}
+		copy(multiSigned.GetBase().Account[:], account.Payload())
+		c.Assert(SignFor(multiSigned.Transaction, familyKey(c, "alice"), &sequence), IsNil)
+		hash = *multiSigned.GetHash()
+		c.Assert(multiSigned.Sign(keyPair, false), ErrorMatches, "Transaction is already signed: "+hash.String())
+		c.Assert(multiSigned.Sign(keyPair, true), IsNil)
+		c.Assert(multiSigned.GetBase().Signers, IsNil)
+		c.Assert(multiSigned.GetPublicKey().Bytes(), DeepEquals, keyPair.Public())
+		valid, err = CheckSignature(multiSigned.Transaction)
+		c.Assert(err, IsNil)
+		c.Assert(valid, Equals, true)
+	}
+}
+
+// highS returns the DER encoding of an ECDSA signature with S replaced by N-S
+func highS(c *C, signature []byte) []byte {
+	sig, err := btcec.ParseDERSignature(signature, btcec.S256())
+	c.Assert(err, IsNil)
+	integer := func(n *big.Int) []byte {
+		b := n.Bytes()
+		if b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return append([]byte{0x02, byte(len(b))}, b...)
+	}
+	body := append(integer(sig.R), integer(new(big.Int).Sub(btcec.S256().N, sig.S))...)
+	return append([]byte{0x30, byte(len(body))}, body...)
+}
+
+func (s *TransactionSuite) TestCheckSignatureCanonical(c *C) {
+	seed, err := crypto.NewSeed("snoPBrXtMeMyMHUVTgbuqAfg1SUTb")
+	c.Assert(err, IsNil)
+	keyPair, err := seed.DeriveKeyPair(crypto.ECDSA, 0)
+	c.Assert(err, IsNil)
+	payment := multiSignPayment(c)
+	flags := TxCanonicalSignature
+	payment.Flags = &flags
+	txm := &TransactionWithMetaData{Transaction: payment}
+	c.Assert(txm.Sign(keyPair, false), IsNil)
+	signature := txm.GetBase().TxnSignature.Bytes()
+	c.Assert(crypto.IsFullyCanonical(signature), Equals, true)
+
+	high := highS(c, signature)
+	c.Assert(crypto.IsFullyCanonical(high), Equals, false)
+	hash, msg, err := SigningHash(txm.Transaction, nil)
+	c.Assert(err, IsNil)
+	// The twin is a valid signature, which rippled nonetheless rejects
+	valid, err := crypto.Verify(keyPair.Public(), hash.Bytes(), msg, high)
+	c.Assert(err, IsNil)
+	c.Assert(valid, Equals, true)
+	*txm.GetBase().TxnSignature = VariableLength(high)
+	valid, err = CheckSignature(txm.Transaction)
+	c.Assert(err, IsNil)
+	c.Check(valid, Equals, false)
+	// Historical transactions without the flag may have either
+	payment.Flags = nil
+	c.Assert(txm.Sign(keyPair, true), IsNil)
+	hash, msg, err = SigningHash(txm.Transaction, nil)
+	c.Assert(err, IsNil)
+	*txm.GetBase().TxnSignature = VariableLength(highS(c, txm.GetBase().TxnSignature.Bytes()))
+	valid, err = CheckSignature(txm.Transaction)
+	c.Assert(err, IsNil)
+	c.Check(valid, Equals, true)
+
+	_, err = CheckSignature(multiSignPayment(c))
+	c.Check(err, ErrorMatches, "No SigningPubKey to check")
+}
+
+func (s *TransactionSuite) TestEd25519RoundTrip(c *C) {
+	seed, keyType, err := crypto.ParseSeed("sEdSKaCy2JT7JaM7v95H9SxkhP9wS2r")
+	c.Assert(err, IsNil)
+	keyPair, err := seed.DeriveKeyPair(keyType, 0)
+	c.Assert(err, IsNil)
+	account, err := keyPair.Account()
+	c.Assert(err, IsNil)
+	txm := &TransactionWithMetaData{Transaction: multiSignPayment(c)}
+	copy(txm.GetBase().Account[:], account.Payload())
+	c.Assert(txm.Sign(keyPair, false), IsNil)
+	publicKey := txm.GetBase().SigningPubKey
+	c.Assert(publicKey.String()[:2], Equals, "ED")
+	publicKeyType, ok := publicKey.KeyType()
+	c.Assert(ok, Equals, true)
+	c.Check(publicKeyType, Equals, Ed25519)
+	c.Check(publicKey.AccountId().String(), Equals, "rLUEXYuLiQptky37CqLcm9USQpPiz5rkpD")
+
+	b, err := json.Marshal(txm)
+	c.Assert(err, IsNil)
+	var fromJSON TransactionWithMetaData
+	c.Assert(json.Unmarshal(b, &fromJSON), IsNil)
+	blob, err := txm.EncodeTxBlob()
+	c.Assert(err, IsNil)
+	fromBlob, err := DecodeTxBlob(blob)
+	c.Assert(err, IsNil)
+	for _, decoded := range []*TransactionWithMetaData{&fromJSON, fromBlob} {
+		c.Check(*decoded.GetBase().SigningPubKey, Equals, *publicKey)
+		valid, err := CheckSignature(decoded.Transaction)
+		c.Assert(err, IsNil)
+		c.Check(valid, Equals, true)
+		c.Check(decoded.VerifyHash(), IsNil)
+	}
+
+	var key PublicKey
+	c.Check(key.UnmarshalText([]byte("ED01")), ErrorMatches, "Bad PublicKey: ED01")
+	c.Check(key.UnmarshalText([]byte("04"+strings.Repeat("00", 32))), ErrorMatches, "Unknown PublicKey prefix: 04")
+	_, ok = key.KeyType()
+	c.Check(ok, Equals, false)
+	c.Assert(key.UnmarshalText(nil), IsNil)
+	c.Check(key.IsZero(), Equals, true)
+}
+
+func (s *TransactionSuite) TestNewOffer(c *C) {
+	account, err := NewAccountFromAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Assert(err, IsNil)
+	pays, gets := amountCheck("10/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"), amountCheck("1000")
+
+	txm, err := NewOfferCreate(*account, *pays, *gets)
+	c.Assert(err, IsNil)
+	tx := txm.Transaction.(*OfferCreate)
+	c.Assert(tx.TransactionType, Equals, OFFER_CREATE)
+	c.Assert(tx.Account, Equals, *account)
+	c.Assert(tx.TakerPays.String(), Equals, "10/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(tx.TakerGets.String(), Equals, "0.001/XRP")
+	tx.Sequence, tx.Fee = 1, *amountCheck("12/XRP").Value
+	_, raw, err := Raw(tx)
+	c.Assert(err, IsNil)
+	decoded, err := ReadTransaction(bytes.NewReader(raw))
+	c.Assert(err, IsNil)
+	_, again, err := Raw(decoded)
+	c.Assert(err, IsNil)
+	c.Assert(b2h(again), DeepEquals, b2h(raw))
+
+	for _, t := range []struct {
+		Pays, Gets Amount
+		Error      string
+	}{
+		{*gets, *amountCheck("5"), "OfferCreate TakerPays and TakerGets are both XRP"},
+		{*pays, *amountCheck("2/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"), "OfferCreate TakerPays and TakerGets are both USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"},
+		{*pays, *amountCheck("0"), "OfferCreate TakerGets must be positive: 0/XRP"},
+		{*amountCheck("-10/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"), *gets, "OfferCreate TakerPays must be positive: .*"},
+		{Amount{}, *gets, "OfferCreate missing TakerPays"},
+	} {
+		_, err := NewOfferCreate(*account, t.Pays, t.Gets)
+		c.Check(err, ErrorMatches, t.Error)
+	}
+
+	txm, err = NewOfferCancel(*account, 7)
+	c.Assert(err, IsNil)
+	cancel := txm.Transaction.(*OfferCancel)
+	c.Assert(cancel.TransactionType, Equals, OFFER_CANCEL)
+	c.Assert(cancel.OfferSequence, Equals, uint32(7))
+	_, err = NewOfferCancel(*account, 0)
+	c.Check(err, ErrorMatches, "OfferCancel requires an OfferSequence")
+}
+
+func (s *TransactionSuite) TestZeroOptionalHashes(c *C) {
+	txm := NewTransactionWithMetadata(CHECK_CASH)
+	check := txm.Transaction.(*CheckCash)
+	account, err := NewAccountFromAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	c.Assert(err, IsNil)
+	check.Account = *account
+	check.Fee, check.Amount = *amountCheck("12").Value, amountCheck("1")
+	check.AccountTxnID = &Hash256{}
+
+	encoded := func() (map[string]json.RawMessage, Transaction) {
+		b, err := json.Marshal(txm)
+		c.Assert(err, IsNil)
+		fields := make(map[string]json.RawMessage)
+		c.Assert(json.Unmarshal(b, &fields), IsNil)
+		_, raw, err := Raw(check)
+		c.Assert(err, IsNil)
+		decoded, err := ReadTransaction(bytes.NewReader(raw))
+		c.Assert(err, IsNil)
+		return fields, decoded
+	}
+	fields, decoded := encoded()
+	c.Check(fields["AccountTxnID"], IsNil)
+	c.Check(decoded.GetBase().AccountTxnID, IsNil)
+	// CheckID is required, so is kept even when zero
+	c.Check(string(fields["CheckID"]), Equals, `"`+zero256.String()+`"`)
+	c.Check(decoded.(*CheckCash).CheckID, Equals, zero256)
+	unsigned, err := json.Marshal(OmitEmptyAccounts{check})
+	c.Assert(err, IsNil)
+	c.Check(strings.Contains(string(unsigned), "AccountTxnID"), Equals, false)
+
+	id := hash256Check(c, "61E8E8ED53FA2CEBE192B23897071E9A75217BF5A410E9CB5B45AAB7AECA567A")
+	check.AccountTxnID = &id
+	fields, decoded = encoded()
+	c.Check(string(fields["AccountTxnID"]), Equals, `"`+id.String()+`"`)
+	c.Check(*decoded.GetBase().AccountTxnID, Equals, id)
+}
+
+func (s *TransactionSuite) TestNetworkID(c *C) {
+	seed, err := crypto.GenerateFamilySeed("masterpassphrase")
+	c.Assert(err, IsNil)
+	key, err := crypto.NewEd25519Key(seed.Payload())
+	c.Assert(err, IsNil)
+	sign := func(networkID *uint32) (Hash256, []byte) {
+		payment := multiSignPayment(c)
+		copy(payment.Account[:], key.Id(nil))
+		payment.NetworkID = networkID
+		c.Assert(Sign(payment, key, nil), IsNil)
+		hash, _, err := SigningHash(payment, nil)
+		c.Assert(err, IsNil)
+		_, raw, err := Raw(payment)
+		c.Assert(err, IsNil)
+		decoded, err := ReadTransaction(bytes.NewReader(raw))
+		c.Assert(err, IsNil)
+		c.Assert(decoded.GetBase().NetworkID, DeepEquals, networkID)
+		valid, err := CheckSignature(decoded)
+		c.Assert(err, IsNil)
+		c.Assert(valid, Equals, true)
+		return hash, raw
+	}
+	mainnet, mainnetRaw := sign(nil)
+	networkID := uint32(1025)
+	sidechain, sidechainRaw := sign(&networkID)
+	c.Check(sidechain, Not(Equals), mainnet)
+	c.Check(strings.HasPrefix(string(b2h(sidechainRaw)), "1200002100000401"), Equals, true)
+	c.Check(strings.Contains(string(b2h(mainnetRaw)), "2100000401"), Equals, false)
+	again, _ := sign(nil)
+	c.Check(again, Equals, mainnet)
+}
+
+func (s *TransactionSuite) TestDepositPreauth(c *C) {
+	for _, test := range []string{
+		`{"TransactionType":"DepositPreauth","Account":"rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8","Authorize":"rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de","Fee":"10","Flags":2147483648,"Sequence":2,"SigningPubKey":""}`,
+		`{"TransactionType":"DepositPreauth","Account":"rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8","Unauthorize":"rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de","Fee":"10","Flags":2147483648,"Sequence":3,"SigningPubKey":""}`,
+	} {
+		var txm TransactionWithMetaData
+		c.Assert(json.Unmarshal([]byte(test), &txm), IsNil, Commentf(test))
+		_, raw, err := Raw(txm.Transaction)
+		c.Assert(err, IsNil, Commentf(test))
+		decoded, err := ReadTransaction(bytes.NewReader(raw))
+		c.Assert(err, IsNil, Commentf(test))
+		c.Assert(decoded, DeepEquals, txm.Transaction, Commentf(test))
+		out, err := json.Marshal(decoded)
+		c.Assert(err, IsNil)
+		var expected, obtained map[string]interface{}
+		c.Assert(json.Unmarshal([]byte(test), &expected), IsNil)
+		c.Assert(json.Unmarshal(out, &obtained), IsNil)
+		delete(obtained, "hash")
+		c.Assert(obtained, DeepEquals, expected, Commentf(test))
+	}
+
+	le, err := DecodeLedgerEntry([]byte(`{"LedgerEntryType":"AccountRoot","Account":"rsUiUMpnrgxQp24dJYZDhmV4bE3aBtQyt8","Balance":"1000000000","Flags":16777216,"OwnerCount":1,"Sequence":4}`))
+	c.Assert(err, IsNil)
+	account := le.(*AccountRoot)
+	c.Check(account.DepositAuth(), Equals, true)
+	c.Check(account.NoFreeze(), Equals, false)
+	c.Check(account.Flags.Explain(account), DeepEquals, []string{"DepositAuth"})
+}
@@ -87,6 +87,52 @@ func NewNonNativeValue(n int64, offset int64) (*Value, error) {
 	return v, v.canonicalise()
 }
 
+// MaxIOUValue returns the largest representable non-native value,
+// 9999999999999999e80.
+func MaxIOUValue() Value {
+	return *newValue(false, false, maxValue, maxOffset)
+}
+
+// MinIOUValue returns the smallest positive representable non-native value,
+// 1000000000000000e-96.
+func MinIOUValue() Value {
+	return *newValue(false, false, minValue, minOffset)
+}
+
+// newValueFromRat returns the value nearest to r rounding towards zero.
+// Native values are in drips.
+func newValueFromRat(r *big.Rat, native bool) (*Value, error) {
+	abs := new(big.Rat).Abs(r)
+	if native {
+		drips := new(big.Int).Quo(abs.Num(), abs.Denom())
+		if !drips.IsUint64() {
+			return nil, fmt.Errorf("Native amount out of range: %s", r.FloatString(0))
+		}
+		v := newValue(true, r.Sign() < 0, drips.Uint64(), 0)
+		return v, v.canonicalise()
+	}
+	if abs.Sign() == 0 {
+		return zeroNonNative.Clone(), nil
+	}
+	var (
+		offset int64
+		ten    = new(big.Rat).SetInt(bigTen)
+		lower  = new(big.Rat).SetUint64(minValue)
+		upper  = new(big.Rat).SetUint64(maxValue + 1)
+	)
+	for abs.Cmp(lower) < 0 && offset >= minOffset {
+		abs.Mul(abs, ten)
+		offset--
+	}
+	for abs.Cmp(upper) >= 0 && offset <= maxOffset {
+		abs.Quo(abs, ten)
+		offset++
+	}
+	num := new(big.Int).Quo(abs.Num(), abs.Denom())
+	v := newValue(false, r.Sign() < 0, num.Uint64(), offset)
+	return v, v.canonicalise()
+}
+
 // Match fields:
 // 0 = whole input
 // 1 = sign
@@ -96,18 +142,26 @@ func NewNonNativeValue(n int64, offset int64) (*Value, error) {
 // 5 = whole exponent (with 'e')
 // 6 = exponent sign
 // 7 = exponent number
-var valueRegex = regexp.MustCompile("([+-]?)(\\d*)(\\.(\\d*))?([eE]([+-]?)(\\d+))?")
+var valueRegex = regexp.MustCompile("^([+-]?)(\\d*)(\\.(\\d*))?([eE]([+-]?)(\\d+))?$")
+
+const (
+	maxSignificantDigits = 16
+	// Exponents beyond this always overflow or underflow
+	maxExponent int64 = 1000
+)
 
 // NewValue accepts a string representation of a value and a flag to indicate if it
 // should be stored as native. If the native flag is set AND a decimal is used, the
 // number is interpreted as XRP. If no decimal is used, it is interpreted as drips.
+// Non-native values may have at most 16 significant digits. Like rippled, non-zero
+// non-native values smaller in magnitude than MinIOUValue become zero.
 func NewValue(s string, native bool) (*Value, error) {
 	var err error
 	v := Value{
 		native: native,
 	}
 	matches := valueRegex.FindStringSubmatch(s)
-	if matches == nil {
+	if matches == nil || len(matches[2])+len(matches[4]) == 0 {
 		return nil, fmt.Errorf("Invalid Number: %s", s)
 	}
 	if len(matches[2])+len(matches[4]) > 32 {
@@ -116,21 +170,24 @@ func NewValue(s string, native bool) (*Value, error) {
 	if matches[1] == "-" {
 		v.negative = true
 	}
-	if len(matches[4]) == 0 {
-		if v.num, err = strconv.ParseUint(matches[2], 10, 64); err != nil {
-			return nil, fmt.Errorf("Invalid Number: %s Reason: %s", s, err.Error())
-		}
-		v.offset = 0
-	} else {
-		if v.num, err = strconv.ParseUint(matches[2]+matches[4], 10, 64); err != nil {
+	// Trailing zeros are moved into the offset so that large
+	// integers with few significant digits still fit in a uint64
+	digits := strings.TrimLeft(matches[2]+matches[4], "0")
+	trimmed := strings.TrimRight(digits, "0")
+	if !native && len(trimmed) > maxSignificantDigits {
+		return nil, fmt.Errorf("Too many significant digits: %s", s)
+	}
+	v.offset = int64(len(digits)-len(trimmed)) - int64(len(matches[4]))
+	if len(trimmed) > 0 {
+		if v.num, err = strconv.ParseUint(trimmed, 10, 64); err != nil {
 			return nil, fmt.Errorf("Invalid Number: %s Reason: %s", s, err.Error())
 		}
-		v.offset = -int64(len(matches[4]))
 	}
 	if len(matches[5]) > 0 {
+		// The regex only allows digits so the only possible error is a range error
 		exp, err := strconv.ParseInt(matches[7], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("Invalid Number: %s %s", s, err.Error())
+		if err != nil || exp > maxExponent {
+			exp = maxExponent
 		}
 		if matches[6] == "-" {
 			v.offset -= exp
@@ -150,17 +207,24 @@ func (v *Value) canonicalise() error {
 			v.offset = 0
 			v.negative = false
 		} else {
-			for v.offset < 0 {
+			for v.offset < 0 && v.num != 0 {
 				v.num /= 10
 				v.offset++
 			}
 			for v.offset > 0 {
+				if v.num > maxNative/10 {
+					return fmt.Errorf("Native amount out of range: %s", v.debug())
+				}
 				v.num *= 10
 				v.offset--
 			}
 			if v.num > maxNative {
 				return fmt.Errorf("Native amount out of range: %s", v.debug())
 			}
+			if v.num == 0 {
+				v.offset = 0
+				v.negative = false
+			}
 		}
 	} else {
 		if v.num == 0 {
@@ -173,18 +237,19 @@ func (v *Value) canonicalise() error {
 			}
 			for v.num > maxValue {
 				if v.offset >= maxOffset {
-					return fmt.Errorf("Value overflow: %s", v.debug())
+					return fmt.Errorf("Value overflow: %s exceeds %s", v.debug(), MaxIOUValue())
 				}
 				v.num /= 10
 				v.offset++
 			}
+			// Dust below the smallest representable value is zero
 			if v.offset < minOffset || v.num < minValue {
 				v.num = 0
-				v.offset = 0
+				v.offset = -100
 				v.negative = false
 			}
 			if v.offset > maxOffset {
-				return fmt.Errorf("Value overflow: %s", v.debug())
+				return fmt.Errorf("Value overflow: %s exceeds %s", v.debug(), MaxIOUValue())
 			}
 		}
 	}
@@ -243,7 +308,8 @@ func (a Value) factor(b Value) (int64, int64, int64) {
 		return av, bv, ao
 	}
 
-	// FIXME: This can silently underflow
+	// The value with the smaller offset is truncated to the larger, as
+	// rippled does, so that digits beyond the 16 of the result are lost
 	for ; ao < bo; ao++ {
 		av /= 10
 	}
@@ -254,6 +320,8 @@ func (a Value) factor(b Value) (int64, int64, int64) {
 }
 
 // Add adds a to b and returns the sum as a new Value.
+// As in rippled, a non-native sum within 10 of zero in its last digit,
+// the residue of truncation, is zero.
 func (a Value) Add(b Value) (*Value, error) {
 	switch {
 	case a.IsNative() != b.IsNative():
@@ -262,9 +330,19 @@ func (a Value) Add(b Value) (*Value, error) {
 		return b.Clone(), nil
 	case b.IsZero():
 		return a.Clone(), nil
+	case a.IsNative():
+		// The sum of two values of the same sign can exceed an int64
+		if a.negative == b.negative && a.num > maxNative-b.num {
+			return nil, fmt.Errorf("Native value overflow: %s+%s", a.debug(), b.debug())
+		}
+		fallthrough
 	default:
 		av, bv, ao := a.factor(b)
-		v := newValue(a.native, (av+bv) < 0, abs(av+bv), ao)
+		sum := av + bv
+		if !a.IsNative() && sum >= -10 && sum <= 10 {
+			return a.ZeroClone(), nil
+		}
+		v := newValue(a.native, sum < 0, abs(sum), ao)
 		return v, v.canonicalise()
 	}
 }
@@ -299,7 +377,8 @@ func (a Value) Multiply(b Value) (*Value, error) {
 		if min > maxNativeSqrt || (((max >> 32) * min) > maxNativeDiv) {
 			return nil, fmt.Errorf("Native value overflow: %s*%s", a.debug(), b.debug())
 		}
-		return NewNativeValue(int64(min * max))
+		v := newValue(true, a.negative != b.negative, min*max, 0)
+		return v, v.canonicalise()
 	}
 	av, bv, ao, bo := normalise(a, b)
 	// Compute (numerator * denominator) / 10^14 with rounding
@@ -307,9 +386,9 @@ func (a Value) Multiply(b Value) (*Value, error) {
 	m := big.NewInt(0).SetUint64(av)
 	m.Mul(m, big.NewInt(0).SetUint64(bv))
 	m.Div(m, bigTenTo14)
-	// 10^16 <= product <= 10^18
-	if len(m.Bytes()) > 64 {
-		return nil, fmt.Errorf("Multiply: %s*%s", a.debug(), b.debug())
+	// 10^16 <= product <= 10^18, unless a native value exceeded 10^16
+	if !m.IsUint64() {
+		return nil, fmt.Errorf("Value overflow: %s*%s", a.debug(), b.debug())
 	}
 	v := newValue(a.native, a.negative != b.negative, m.Uint64()+7, ao+bo+14)
 	return v, v.canonicalise()
@@ -327,9 +406,9 @@ func (num Value) Divide(den Value) (*Value, error) {
 	d := big.NewInt(0).SetUint64(av)
 	d.Mul(d, bigTenTo17)
 	d.Div(d, big.NewInt(0).SetUint64(bv))
-	// 10^16 <= quotient <= 10^18
-	if len(d.Bytes()) > 64 {
-		return nil, fmt.Errorf("Divide: %s/%s", num.debug(), den.debug())
+	// 10^16 <= quotient <= 10^18, unless a native value exceeded 10^16
+	if !d.IsUint64() {
+		return nil, fmt.Errorf("Value overflow: %s/%s", num.debug(), den.debug())
 	}
 	v := newValue(num.native, num.negative != den.negative, d.Uint64()+5, ao-bo-17)
 	return v, v.canonicalise()
@@ -432,7 +511,8 @@ func (v *Value) UnmarshalBinary(b []byte) error {
 	return v.Unmarshal(buf)
 }
 
-// Rat returns the value as a big.Rat.
+// Rat returns the value as an exact big.Rat.
+// Native values are in drips.
 func (v Value) Rat() *big.Rat {
 	n := big.NewInt(int64(v.num))
 	if v.negative {
@@ -453,6 +533,44 @@ func (v Value) Rat() *big.Rat {
 	return res
 }
 
+// Decimal returns the value exactly as mantissa * 10^-scale, without
+// allocating a big.Rat. Native values are in XRP with a scale of 6, so
+// the mantissa is in drips. Otherwise trailing zeros are removed from the
+// mantissa and the scale is never negative.
+func (v Value) Decimal() (*big.Int, int) {
+	num, offset := v.num, v.offset
+	scale := 0
+	if v.native {
+		scale = 6
+	}
+	if num == 0 {
+		return new(big.Int), scale
+	}
+	if !v.native {
+		for num%10 == 0 && offset < 0 {
+			num /= 10
+			offset++
+		}
+	}
+	mantissa := new(big.Int).SetUint64(num)
+	switch {
+	case offset < 0:
+		scale -= int(offset)
+	case offset > 0:
+		mantissa.Mul(mantissa, new(big.Int).Exp(bigTen, big.NewInt(offset), nil))
+	}
+	if v.negative {
+		mantissa.Neg(mantissa)
+	}
+	return mantissa, scale
+}
+
+// Float64 returns the nearest float64 to the value and whether it is exact.
+// Unlike Float, native values are in drips.
+func (v Value) Float64() (float64, bool) {
+	return v.Rat().Float64()
+}
+
 func (v Value) Float() float64 {
 	switch {
 	case v.negative && v.native:
@@ -466,6 +584,29 @@ func (v Value) Float() float64 {
 	}
 }
 
+// Drops returns the number of drops of a native value.
+// Negative and non-native values return 0.
+func (v Value) Drops() uint64 {
+	if !v.native || v.negative {
+		return 0
+	}
+	return v.num
+}
+
+// XRP returns a native value as XRP with exactly 6 decimal places, e.g. a fee of
+// 12 drops is 0.000012. NewValue with native set parses either this form or drops.
+// Non-native values are formatted by String.
+func (v Value) XRP() string {
+	if !v.native {
+		return v.String()
+	}
+	sign := ""
+	if v.negative {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%d.%06d", sign, v.num/xrpPrecision, v.num%xrpPrecision)
+}
+
 // String returns the Value as a string for human consumption. Native values are
 // represented as decimal XRP rather than drips.
 func (v Value) String() string {
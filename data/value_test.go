@@ -1,6 +1,9 @@
 package data
 
 import (
+	"fmt"
+	"strings"
+
 	. "github.com/atticlab/ripple/testing"
 	. "gopkg.in/check.v1"
 )
@@ -55,6 +58,17 @@ var valueTests = TestSlice{
 	{valueCheckCanonical(false, false, 1230000000000000, -15).Rat().FloatString(3), Equals, "1.230", "Rat String 1230000000000000, -15"},
 	{valueCheckCanonical(true, false, 1, 0).Rat().FloatString(2), Equals, "1.00", "Rat String n1, 0"},
 	{valueCheckCanonical(true, false, 4000000, 0).Rat().FloatString(2), Equals, "4000000.00", "Rat String n4000000, 0"},
+	{valueCheckCanonical(true, true, 4000000, 0).Rat().String(), Equals, "-4000000/1", "Rat n-4000000, 0"},
+	{valueCheckCanonical(false, false, 9999999999999999, 80).Rat().String(), Equals, "9999999999999999" + strings.Repeat("0", 80) + "/1", "Rat 9999999999999999, 80"},
+	{valueCheckCanonical(false, true, 1000000000000000, -96).Rat().String(), Equals, "-1/1" + strings.Repeat("0", 81), "Rat -1000000000000000, -96"},
+
+	{floatCheck(valueCheck("1.5")), DeepEquals, []interface{}{1.5, true}, "Float64 1.5"},
+	{floatCheck(valueCheck("0.1")), DeepEquals, []interface{}{0.1, false}, "Float64 0.1"},
+	{floatCheck(valueCheck("-123e9")), DeepEquals, []interface{}{-123e9, true}, "Float64 -123e9"},
+	{floatCheck(valueCheckCanonical(true, false, 4000000, 0)), DeepEquals, []interface{}{4000000.0, true}, "Float64 n4000000"},
+	{floatCheck(valueCheckCanonical(false, false, 9999999999999999, 80)), DeepEquals, []interface{}{9999999999999999e80, false}, "Float64 9999999999999999, 80"},
+	{floatCheck(valueCheckCanonical(false, false, 1000000000000000, -96)), DeepEquals, []interface{}{1e-81, false}, "Float64 1000000000000000, -96"},
+	{floatCheck(amountCheck("20.25/USD/rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh").Value), DeepEquals, []interface{}{20.25, true}, "Float64 Amount 20.25/USD"},
 
 	{valueCheck("0"), DeepEquals, valueCheckCanonical(false, false, 0, -100), "Parse 0"},
 	{valueCheck("1"), DeepEquals, valueCheckCanonical(false, false, 1000000000000000, -15), "Parse 1"},
@@ -77,7 +91,45 @@ var valueTests = TestSlice{
 	{ErrorCheck(NewValue("foo", false)), ErrorMatches, "Invalid Number: .*", "Parse foo (invalid)"},
 	{valueCheck("n0.0000001").IsZero(), Equals, true, "Parse n0.0000001 (silent underflow)"},
 	{ErrorCheck(NewValue("9000000000000.000001", true)), ErrorMatches, "Native amount out of range: .*", "Parse n9000000000000.000001 (overflow)"},
-
+	{MaxIOUValue(), DeepEquals, *valueCheck("9999999999999999e80"), "MaxIOUValue"},
+	{MinIOUValue(), DeepEquals, *valueCheck("1000000000000000e-96"), "MinIOUValue"},
+	{valueCheck(MaxIOUValue().String()), DeepEquals, valueCheckCanonical(false, false, 9999999999999999, 80), "Parse MaxIOUValue"},
+	{valueCheck(MinIOUValue().String()), DeepEquals, valueCheckCanonical(false, false, 1000000000000000, -96), "Parse MinIOUValue"},
+	{fmt.Sprintf("%X", MaxIOUValue().Clone().Bytes()), Equals, "EC6386F26FC0FFFF", "Serialize MaxIOUValue"},
+	{fmt.Sprintf("%X", MinIOUValue().Clone().Bytes()), Equals, "C0438D7EA4C68000", "Serialize MinIOUValue"},
+	{MaxIOUValue().Negate().String(), Equals, "-" + MaxIOUValue().String(), "Negate MaxIOUValue"},
+	{valueCheck("1000000000000000e-96"), DeepEquals, valueCheckCanonical(false, false, 1000000000000000, -96), "Parse 1000000000000000e-96"},
+	{valueCheck("100000000000000000000"), DeepEquals, valueCheckCanonical(false, false, 1000000000000000, 5), "Parse 100000000000000000000"},
+	{valueCheck("1234567890123456000"), DeepEquals, valueCheckCanonical(false, false, 1234567890123456, 3), "Parse 1234567890123456000"},
+	{ErrorCheck(NewValue("10000000000000000e80", false)), ErrorMatches, "Value overflow: .*", "Parse 10000000000000000e80 (overflow)"},
+	{ErrorCheck(NewValue("1e9999", false)), ErrorMatches, "Value overflow: .*", "Parse 1e9999 (overflow)"},
+	{ErrorCheck(NewValue("1e99999999999999999999", false)), ErrorMatches, "Value overflow: .*", "Parse 1e99999999999999999999 (overflow)"},
+	{valueCheck("1e-9999").IsZero(), Equals, true, "Parse 1e-9999 (silent underflow)"},
+	{valueCheck("9.999999999999999e-82"), DeepEquals, valueCheckCanonical(false, false, 0, -100), "Parse 9.999999999999999e-82 (dust)"},
+	{valueCheck("-9.999999999999999e-82"), DeepEquals, valueCheckCanonical(false, false, 0, -100), "Parse -9.999999999999999e-82 (dust)"},
+	{valueCheck("1.000000000000001e-81"), DeepEquals, valueCheckCanonical(false, false, 1000000000000001, -96), "Parse 1.000000000000001e-81 (not dust)"},
+	{amountCheck("1e-82/USD/rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh").IsZero(), Equals, true, "Parse 1e-82/USD (dust)"},
+	{ErrorCheck(NewValue("12345678901234567", false)), ErrorMatches, "Too many significant digits: 12345678901234567", "Parse 12345678901234567 (precision)"},
+	{ErrorCheck(NewValue("0.00012345678901234567", false)), ErrorMatches, "Too many significant digits: .*", "Parse 0.00012345678901234567 (precision)"},
+	{ErrorCheck(NewValue("1e9999", true)), ErrorMatches, "Native amount out of range: .*", "Parse n1e9999 (overflow)"},
+	{ErrorCheck(NewValue("1e20", true)), ErrorMatches, "Native amount out of range: .*", "Parse n1e20 (overflow)"},
+	{valueCheck("n12345678901234567"), DeepEquals, valueCheckCanonical(true, false, 12345678901234567, 0), "Parse n12345678901234567"},
+	{ErrorCheck(NewValue("1.5abc", false)), ErrorMatches, "Invalid Number: .*", "Parse 1.5abc (invalid)"},
+	{ErrorCheck(NewValue(".", false)), ErrorMatches, "Invalid Number: .*", "Parse . (invalid)"},
+
+	{valueCheck("n0").XRP(), Equals, "0.000000", "XRP n0"},
+	{valueCheck("n0").Drops(), Equals, uint64(0), "Drops n0"},
+	{valueCheck("n12").XRP(), Equals, "0.000012", "XRP n12"},
+	{valueCheck("n12").Drops(), Equals, uint64(12), "Drops n12"},
+	{valueCheck("n1.5").XRP(), Equals, "1.500000", "XRP n1.5"},
+	{valueCheck("n1.5").Drops(), Equals, uint64(1500000), "Drops n1.5"},
+	{valueCheck("n" + valueCheck("n1.5").XRP()).Drops(), Equals, uint64(1500000), "Parse XRP n1.5"},
+	{valueCheck("n9000000000000000000").XRP(), Equals, "9000000000000.000000", "XRP max drops"},
+	{valueCheck("n9000000000000000000").Drops(), Equals, maxNative, "Drops max drops"},
+	{valueCheck("n-12").XRP(), Equals, "-0.000012", "XRP n-12"},
+	{valueCheck("n-12").Drops(), Equals, uint64(0), "Drops n-12"},
+	{valueCheck("1.5").XRP(), Equals, "1.5", "XRP non-native"},
+	{valueCheck("1.5").Drops(), Equals, uint64(0), "Drops non-native"},
 	{valueCheck("123").ZeroClone().IsZero(), Equals, true, "ZeroClone is zero"},
 	{valueCheck("123").ZeroClone().IsNative(), Equals, false, "ZeroClone is not native"},
 	{valueCheck("0").IsZero(), Equals, true, "IsZero true"},
@@ -130,6 +182,11 @@ var valueTests = TestSlice{
 	{addValCheck("n-1", "n1").String(), Equals, "0", "n-1+n1"},
 	{addValCheck("n-1", "n-1").String(), Equals, "-0.000002", "n-1+n-1"},
 	{addValCheck("n1", "n-1").String(), Equals, "0", "n1+n-1"},
+	{addValCheck("1.000000000000001", "-1").String(), Equals, "0", "1.000000000000001+-1 (residue)"},
+	{addValCheck("1.00000000000001", "-1").String(), Equals, "0", "1.00000000000001+-1 (residue)"},
+	{addValCheck("1.0000000000001", "-1").String(), Equals, "1e-13", "1.0000000000001+-1"},
+	{addValCheck("1", "1e-20").String(), Equals, "1", "1+1e-20 (truncated)"},
+	{addValCheck("n9000000000000000000", "n-1").String(), Equals, "8999999999999.999999", "max+n-1"},
 	{ErrorCheck(valueCheck("n1").Add(*valueCheck("1"))), ErrorMatches, "Cannot add native and non-native values", "n1+1"},
 
 	{subValCheck("0", "0").String(), Equals, "0", "0-0"},
@@ -157,6 +214,8 @@ var valueTests = TestSlice{
 	{mulValCheck("n1", "n0").String(), Equals, "0", "n1*n0"},
 	{mulValCheck("n0", "n1").String(), Equals, "0", "n0*n1"},
 	{mulValCheck("n1", "n1").String(), Equals, "0.000001", "n1*n1"},
+	{mulValCheck("n-2", "n3").String(), Equals, "-0.000006", "n-2*n3"},
+	{mulValCheck("n-2", "n-3").String(), Equals, "0.000006", "n-2*n-3"},
 	{mulValCheck("n1.", "n1.").String(), Equals, "1000000", "n1.*n1."}, // Unintuitive case
 	{mulValCheck("n1.", "2").String(), Equals, "2", "n1.*2"},
 	{mulValCheck("n1.", "0.000001").String(), Equals, "0.000001", "n1.*0.000001"},
@@ -235,6 +294,37 @@ var valueTests = TestSlice{
 	{checkValHex(valueCheckCanonical(false, false, 0, -15)), Equals, "8000000000000000", "Zero hex"},
 }
 
+func (s *ValueSuite) TestValueOverflow(c *C) {
+	max := valueCheck("n9000000000000000000")
+	_, err := max.Add(*valueCheck("n1"))
+	c.Check(err, ErrorMatches, "Native value overflow.*")
+	_, err = max.Add(*max)
+	c.Check(err, ErrorMatches, "Native value overflow.*")
+	_, err = max.Negate().Add(*max.Negate())
+	c.Check(err, ErrorMatches, "Native value overflow.*")
+	_, err = valueCheck("n3000000001").Multiply(*valueCheck("n3000000001"))
+	c.Check(err, ErrorMatches, "Native value overflow.*")
+	_, err = max.Multiply(MaxIOUValue())
+	c.Check(err, ErrorMatches, "Value overflow.*")
+	_, err = MaxIOUValue().Add(MaxIOUValue())
+	c.Check(err, ErrorMatches, "Value overflow.*")
+	_, err = MaxIOUValue().Multiply(*valueCheck("10"))
+	c.Check(err, ErrorMatches, "Value overflow.*")
+	_, err = MaxIOUValue().Divide(*valueCheck("0.1"))
+	c.Check(err, ErrorMatches, "Value overflow.*")
+	_, err = valueCheck("1").Divide(*valueCheck("0"))
+	c.Check(err, ErrorMatches, "Division by zero")
+
+	// Underflow is zero
+	tiny := MinIOUValue()
+	product, err := tiny.Multiply(tiny)
+	c.Assert(err, IsNil)
+	c.Check(product.IsZero(), Equals, true)
+	quotient, err := tiny.Divide(*valueCheck("10"))
+	c.Assert(err, IsNil)
+	c.Check(quotient.IsZero(), Equals, true)
+}
+
 func subValCheck(a, b string) *Value {
 	if sum, err := valueCheck(a).Subtract(*valueCheck(b)); err != nil {
 		panic(err)
@@ -275,6 +365,11 @@ func ratioValCheck(a, b string) *Value {
 	}
 }
 
+func floatCheck(v *Value) []interface{} {
+	f, exact := v.Float64()
+	return []interface{}{f, exact}
+}
+
 func valueCheck(v string) *Value {
 	native := false
 	if v[0] == 'n' {
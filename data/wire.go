@@ -57,6 +57,24 @@ func (c *Currency) Marshal(w io.Writer) error {
 	return binary.Write(w, binary.BigEndian, c.Bytes())
 }
 
+func (i *Issue) Unmarshal(r Reader) error {
+	if err := unmarshalSlice(i.Currency[:], r, "Currency"); err != nil {
+		return err
+	}
+	if i.Currency.IsNative() {
+		i.Issuer = Account{}
+		return nil
+	}
+	return unmarshalSlice(i.Issuer[:], r, "Issuer")
+}
+
+func (i *Issue) Marshal(w io.Writer) error {
+	if i.Currency.IsNative() {
+		return binary.Write(w, binary.BigEndian, i.Currency.Bytes())
+	}
+	return binary.Write(w, binary.BigEndian, append(i.Currency.Bytes(), i.Issuer.Bytes()...))
+}
+
 func (h *Hash128) Unmarshal(r Reader) error {
 	return unmarshalSlice(h[:], r, "Hash128")
 }
@@ -170,6 +188,10 @@ func (p *PathSet) Unmarshal(r Reader) error {
 				return err
 			}
 			if entry == PATH_END {
+				if i == 0 && len((*p)[i]) == 0 {
+					// An empty PathSet is a lone PATH_END
+					*p = (*p)[:0]
+				}
 				return nil
 			}
 			var pe PathElem
@@ -197,8 +219,14 @@ func (p *PathSet) Unmarshal(r Reader) error {
 }
 
 func (p *PathSet) Marshal(w io.Writer) error {
+	if len(*p) == 0 {
+		return write(w, PATH_END)
+	}
 	for i, path := range *p {
 		for _, entry := range path {
+			if entry.pathEntry() == PATH_END {
+				return fmt.Errorf("Empty PathElem in path: %s", path)
+			}
 			if err := write(w, entry.pathEntry()); err != nil {
 				return err
 			}
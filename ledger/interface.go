@@ -1,12 +1,14 @@
 package ledger
 
 import (
+	"context"
+
 	"github.com/atticlab/ripple/data"
 )
 
 type Sync interface {
 	Current(uint32)
-	Missing(*data.LedgerRange) *data.Work
+	Missing(context.Context, *data.LedgerRange) (*data.Work, error)
 	Submit([]data.Hashable)
 	Copy() *RadixMap
 }
@@ -0,0 +1,37 @@
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/atticlab/ripple/data"
+	"github.com/atticlab/ripple/storage"
+)
+
+// CanIssue reports whether issuer can usefully issue currency c. The issuer
+// must have DefaultRipple set, so that holders can transfer the currency
+// between each other, and must not have frozen all of its trust lines.
+// XRP cannot be issued.
+func (m *RadixMap) CanIssue(issuer data.Account, c data.Currency) (bool, error) {
+	if c.IsNative() {
+		return false, nil
+	}
+	index, err := data.GetAccountRootIndex(issuer)
+	if err != nil {
+		return false, err
+	}
+	node, err := m.Get(*index)
+	switch {
+	case err == storage.ErrNotFound:
+		return false, fmt.Errorf("Account not found: %s", issuer.String())
+	case err != nil:
+		return false, err
+	}
+	account, ok := node.(*data.AccountRoot)
+	if !ok {
+		return false, fmt.Errorf("Not an AccountRoot: %s", index.String())
+	}
+	if account.Flags == nil || *account.Flags&data.LsDefaultRipple == 0 {
+		return false, nil
+	}
+	return !account.GlobalFreeze(), nil
+}
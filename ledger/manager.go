@@ -1,6 +1,7 @@
 package ledger
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -10,8 +11,14 @@ import (
 	"github.com/golang/glog"
 )
 
+// A request for the ledgers missing from a range, answered on result
+type missingRequest struct {
+	work   *data.Work
+	result chan *data.Work
+}
+
 type Manager struct {
-	missing  chan chan *data.Work
+	missing  chan missingRequest
 	incoming chan []data.Hashable
 	current  chan uint32
 	db       storage.DB
@@ -28,7 +35,7 @@ func NewManager(db storage.DB) (*Manager, error) {
 	}
 	glog.Infof("Manager: Created Ledger in %0.4f secs", time.Now().Sub(start).Seconds())
 	return &Manager{
-		missing:  make(chan chan *data.Work),
+		missing:  make(chan missingRequest),
 		incoming: make(chan []data.Hashable, 1000),
 		current:  make(chan uint32),
 		db:       db,
@@ -75,11 +82,10 @@ func (m *Manager) Start() {
 				}
 			}
 		case missing := <-m.missing:
-			continue
-			work := <-missing
+			work := missing.work
 			m.ledgers.Extend(work.End)
 			work.MissingLedgers = m.ledgers.TakeMiddle(work.LedgerRange)
-			missing <- work
+			missing.result <- work
 		}
 	}
 }
@@ -92,10 +98,24 @@ func (m *Manager) Submit(items []data.Hashable) {
 	m.incoming <- items
 }
 
-func (m *Manager) Missing(*data.LedgerRange) *data.Work {
-	c := make(chan *data.Work)
-	m.missing <- c
-	return <-c
+// Missing returns the ledgers missing from r, or the error of ctx if it is
+// done before the work is scheduled
+func (m *Manager) Missing(ctx context.Context, r *data.LedgerRange) (*data.Work, error) {
+	missing := missingRequest{
+		work:   &data.Work{LedgerRange: r},
+		result: make(chan *data.Work, 1), // Never blocks the manager
+	}
+	select {
+	case m.missing <- missing:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case work := <-missing.result:
+		return work, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 func (m *Manager) Copy() *RadixMap { return nil }
 
@@ -2,6 +2,7 @@ package ledger
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/atticlab/ripple/data"
 	"github.com/atticlab/ripple/storage"
@@ -17,6 +18,7 @@ type RadixMap struct {
 	db    storage.DB
 	nodes map[data.Hash256]*RadixNode
 	full  bool
+	mu    *sync.RWMutex // nil unless concurrent
 }
 
 type WalkFunc func(key data.Hash256, node *RadixNode) error
@@ -35,21 +37,67 @@ func NewRadixMap(root data.Hash256, db storage.DB) *RadixMap {
 	}
 }
 
+// NewConcurrentRadixMap returns a map which can be read, copied and
+// submitted to from several goroutines at once
+func NewConcurrentRadixMap(root data.Hash256, db storage.DB) *RadixMap {
+	m := NewRadixMap(root, db)
+	m.mu = new(sync.RWMutex)
+	return m
+}
+
+func (m *RadixMap) rlock() func() {
+	if m.mu == nil {
+		return func() {}
+	}
+	m.mu.RLock()
+	return m.mu.RUnlock
+}
+
+func (m *RadixMap) lock() func() {
+	if m.mu == nil {
+		return func() {}
+	}
+	m.mu.Lock()
+	return m.mu.Unlock
+}
+
+func (m *RadixMap) lookup(key data.Hash256) (*RadixNode, bool) {
+	defer m.rlock()()
+	node, ok := m.nodes[key]
+	return node, ok
+}
+
 func (m *RadixMap) Ledger() *data.Ledger {
-	return m.nodes[m.root].Node.(*data.Ledger)
+	node, _ := m.lookup(m.root)
+	return node.Node.(*data.Ledger)
 }
 
 func (m *RadixMap) Fill() error {
-	if m.full {
+	unlock := m.rlock()
+	full := m.full
+	unlock()
+	if full {
 		return nil
 	}
 	if err := m.walk(nil, m.root, 0, true); err != nil {
 		return err
 	}
+	defer m.lock()()
 	m.full = true
 	return nil
 }
 
+// Submit adds the nodes among items to the index of the map, keyed by
+// node id. Their depth is not known. Items which are not nodes are ignored.
+func (m *RadixMap) Submit(items []data.Hashable) {
+	defer m.lock()()
+	for _, item := range items {
+		if node, ok := item.(data.Storer); ok {
+			m.nodes[*node.NodeId()] = &RadixNode{Node: node}
+		}
+	}
+}
+
 func (m *RadixMap) Walk(f WalkFunc) error {
 	return m.walk(f, m.root, 0, false)
 }
@@ -68,10 +116,12 @@ func (m *RadixMap) walk(f WalkFunc, key data.Hash256, depth uint8, fill bool) er
 		if err != nil {
 			return err
 		}
+		unlock := m.lock()
 		m.nodes[key] = node
+		unlock()
 	} else {
 		var ok bool
-		node, ok = m.nodes[key]
+		node, ok = m.lookup(key)
 		if !ok {
 			return fmt.Errorf("Missing hash: %s", key.String())
 		}
@@ -88,6 +138,102 @@ func (m *RadixMap) walk(f WalkFunc, key data.Hash256, depth uint8, fill bool) er
 	})
 }
 
+// Copy returns a map with its own node index so that it is unaffected by
+// later changes to m. The nodes themselves are shared as they are never modified.
+// The copy of a concurrent map is also concurrent.
+func (m *RadixMap) Copy() *RadixMap {
+	defer m.rlock()()
+	nodes := make(map[data.Hash256]*RadixNode, len(m.nodes))
+	for key, node := range m.nodes {
+		nodes[key] = node
+	}
+	copied := &RadixMap{
+		root:  m.root,
+		db:    m.db,
+		nodes: nodes,
+		full:  m.full,
+	}
+	if m.mu != nil {
+		copied.mu = new(sync.RWMutex)
+	}
+	return copied
+}
+
+// WalkLeaves calls fn with the index and item of each leaf in ascending
+// index order until fn returns false
+func (m *RadixMap) WalkLeaves(fn func(key data.Hash256, item data.Hashable) bool) error {
+	_, err := m.walkLeaves(fn, m.root)
+	return err
+}
+
+func (m *RadixMap) walkLeaves(fn func(key data.Hash256, item data.Hashable) bool, key data.Hash256) (bool, error) {
+	if key.IsZero() {
+		return true, nil
+	}
+	node, err := m.get(key)
+	if err != nil {
+		return false, err
+	}
+	inner, ok := node.(*data.InnerNode)
+	if !ok {
+		return fn(leafIndex(node), node), nil
+	}
+	for _, child := range inner.Children {
+		if more, err := m.walkLeaves(fn, child); !more || err != nil {
+			return more, err
+		}
+	}
+	return true, nil
+}
+
+// Get returns the leaf with the given ledger index by descending from the
+// root, using each nibble of the index to choose the next child
+func (m *RadixMap) Get(index data.Hash256) (data.Storer, error) {
+	for key, depth := m.root, 0; !key.IsZero(); depth++ {
+		node, err := m.get(key)
+		if err != nil {
+			return nil, err
+		}
+		inner, ok := node.(*data.InnerNode)
+		if !ok {
+			if leafIndex(node) != index {
+				break
+			}
+			return node, nil
+		}
+		if depth == 2*len(index) {
+			break
+		}
+		nibble := index[depth/2]
+		if depth%2 == 0 {
+			nibble >>= 4
+		}
+		key = inner.Children[nibble&0xF]
+	}
+	return nil, storage.ErrNotFound
+}
+
+// Some stores replace the hash of a ledger entry with its node id,
+// so the index is recomputed where possible
+func leafIndex(node data.Storer) data.Hash256 {
+	if le, ok := node.(data.LedgerEntry); ok {
+		if index, err := data.LedgerIndex(le); err == nil {
+			return *index
+		}
+	}
+	return *node.GetHash()
+}
+
+func (m *RadixMap) get(key data.Hash256) (data.Storer, error) {
+	if node, ok := m.lookup(key); ok {
+		return node.Node, nil
+	}
+	if m.db == nil {
+		return nil, fmt.Errorf("Missing hash: %s", key.String())
+	}
+	return m.db.Get(key)
+}
+
 func (m *RadixMap) Summary(summary map[string]uint64) error {
 	return m.Walk(func(key data.Hash256, n *RadixNode) error {
 		summary[n.Node.GetType()]++
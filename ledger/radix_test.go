@@ -0,0 +1,219 @@
+package ledger
+
+import (
+	"sync"
+
+	"github.com/atticlab/ripple/data"
+	"github.com/atticlab/ripple/storage"
+	"github.com/atticlab/ripple/storage/memdb"
+	. "gopkg.in/check.v1"
+)
+
+type RadixSuite struct{}
+
+var _ = Suite(&RadixSuite{})
+
+// newRadixMap builds an in memory map holding the ledger entries
+func newRadixMap(c *C, entries ...data.LedgerEntry) *RadixMap {
+	m := NewEmptyRadixMap()
+	for _, le := range entries {
+		index, err := data.LedgerIndex(le)
+		c.Assert(err, IsNil)
+		*le.GetHash() = *index
+	}
+	m.root = m.build(c, entries, 0)
+	return m
+}
+
+func (m *RadixMap) build(c *C, entries []data.LedgerEntry, depth uint8) data.Hash256 {
+	if len(entries) == 1 {
+		id, err := data.NodeId(entries[0])
+		c.Assert(err, IsNil)
+		m.nodes[id] = &RadixNode{Node: entries[0], Depth: depth}
+		return id
+	}
+	var children [16][]data.LedgerEntry
+	for _, le := range entries {
+		nibble := le.GetHash()[depth/2]
+		if depth%2 == 0 {
+			nibble >>= 4
+		}
+		children[nibble&0xF] = append(children[nibble&0xF], le)
+	}
+	inner := &data.InnerNode{Type: data.NT_ACCOUNT_NODE}
+	for i, child := range children {
+		if len(child) > 0 {
+			inner.Children[i] = m.build(c, child, depth+1)
+		}
+	}
+	id, err := data.NodeId(inner)
+	c.Assert(err, IsNil)
+	inner.Id = id
+	m.nodes[id] = &RadixNode{Node: inner, Depth: depth}
+	return id
+}
+
+func accountRoot(c *C, address string, flags data.LedgerEntryFlag) *data.AccountRoot {
+	account, err := data.NewAccountFromAddress(address)
+	c.Assert(err, IsNil)
+	le := data.LedgerEntryFactory[data.ACCOUNT_ROOT]().(*data.AccountRoot)
+	le.Account = account
+	le.Flags = &flags
+	return le
+}
+
+func (s *RadixSuite) TestGet(c *C) {
+	db, err := memdb.NewMemoryDB([]string{"testdata/38129-32570.gz"})
+	c.Assert(err, IsNil)
+	ledger, err := data.NewHash256("E6DB7365949BF9814D76BCC730B01818EB9136A89DB224F3F9F5AAE4569D758E") // 38,129 Ledger Hash
+	c.Assert(err, IsNil)
+	state, err := NewLedgerStateFromDB(*ledger, db)
+	c.Assert(err, IsNil)
+	c.Assert(state.AccountState.Fill(), IsNil)
+	leaves := make(map[data.Hash256]data.LedgerEntry)
+	c.Assert(state.AccountState.Walk(func(key data.Hash256, node *RadixNode) error {
+		// Directory indexes can't always be recomputed from their contents
+		le, ok := node.Node.(data.LedgerEntry)
+		if ok && (le.GetLedgerEntryType() == data.ACCOUNT_ROOT || le.GetLedgerEntryType() == data.RIPPLE_STATE) {
+			index, err := data.LedgerIndex(le)
+			c.Assert(err, IsNil)
+			leaves[*index] = le
+		}
+		return nil
+	}), IsNil)
+	c.Assert(len(leaves) > 0, Equals, true)
+	for index, le := range leaves {
+		found, err := state.AccountState.Get(index)
+		c.Assert(err, IsNil)
+		c.Assert(found, Equals, data.Storer(le))
+	}
+	_, err = state.AccountState.Get(data.Hash256{})
+	c.Assert(err, Equals, storage.ErrNotFound)
+}
+
+func (s *RadixSuite) TestCanIssue(c *C) {
+	const (
+		issuer   = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+		frozen   = "rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK"
+		noRipple = "rG1QQv2nh2gr7RCZ1P8YYcBUKCCN633jCn"
+		missing  = "rH4KEcG9dEwGwpn6AyoWK9cZPLL4RLSmWW"
+	)
+	m := newRadixMap(c,
+		accountRoot(c, issuer, data.LsDefaultRipple),
+		accountRoot(c, frozen, data.LsDefaultRipple|data.LsGlobalFreeze),
+		accountRoot(c, noRipple, 0),
+	)
+	usd, err := data.NewCurrency("USD")
+	c.Assert(err, IsNil)
+	for _, t := range []struct {
+		Address  string
+		Currency data.Currency
+		Expected bool
+	}{
+		{issuer, usd, true},
+		{issuer, data.Currency{}, false},
+		{frozen, usd, false},
+		{noRipple, usd, false},
+	} {
+		account, err := data.NewAccountFromAddress(t.Address)
+		c.Assert(err, IsNil)
+		ok, err := m.CanIssue(*account, t.Currency)
+		c.Assert(err, IsNil)
+		c.Check(ok, Equals, t.Expected, Commentf("%s %s", t.Address, t.Currency))
+	}
+	account, err := data.NewAccountFromAddress(missing)
+	c.Assert(err, IsNil)
+	_, err = m.CanIssue(*account, usd)
+	c.Check(err, ErrorMatches, "Account not found: "+missing)
+}
+
+func walkedKeys(c *C, m *RadixMap, limit int) []data.Hash256 {
+	var keys []data.Hash256
+	c.Assert(m.WalkLeaves(func(key data.Hash256, item data.Hashable) bool {
+		c.Assert(*item.GetHash(), Equals, key)
+		keys = append(keys, key)
+		return len(keys) < limit
+	}), IsNil)
+	return keys
+}
+
+func (s *RadixSuite) TestWalkLeaves(c *C) {
+	addresses := []string{
+		"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		"rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK",
+		"rG1QQv2nh2gr7RCZ1P8YYcBUKCCN633jCn",
+		"rH4KEcG9dEwGwpn6AyoWK9cZPLL4RLSmWW",
+	}
+	var entries []data.LedgerEntry
+	var expected data.Hash256Set
+	for _, address := range addresses {
+		le := accountRoot(c, address, 0)
+		index, err := data.LedgerIndex(le)
+		c.Assert(err, IsNil)
+		expected.Add(*index)
+		entries = append(entries, le)
+	}
+	m := newRadixMap(c, entries...)
+
+	c.Check(walkedKeys(c, m, len(addresses)+1), DeepEquals, expected.Sorted())
+	c.Check(walkedKeys(c, m, 2), DeepEquals, expected.Sorted()[:2])
+	c.Check(walkedKeys(c, NewEmptyRadixMap(), 1), HasLen, 0)
+
+	copied := m.Copy()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for key := range m.nodes {
+			delete(m.nodes, key)
+		}
+		m.root = data.Hash256{}
+	}()
+	c.Check(walkedKeys(c, copied, len(addresses)+1), DeepEquals, expected.Sorted())
+	wg.Wait()
+	c.Check(walkedKeys(c, m, len(addresses)+1), HasLen, 0)
+	c.Check(walkedKeys(c, copied, len(addresses)+1), DeepEquals, expected.Sorted())
+}
+
+func (s *RadixSuite) TestConcurrentSubmitCopy(c *C) {
+	const writers, perWriter = 4, 50
+	m := NewConcurrentRadixMap(data.Hash256{}, nil)
+	batches := make([][]data.Hashable, writers)
+	for i := range batches {
+		for j := 0; j < perWriter; j++ {
+			le := data.LedgerEntryFactory[data.ACCOUNT_ROOT]().(*data.AccountRoot)
+			le.Account = &data.Account{byte(i), byte(j)}
+			id, err := data.NodeId(le)
+			c.Assert(err, IsNil)
+			le.Id = id
+			batches[i] = append(batches[i], le)
+		}
+	}
+	var wg sync.WaitGroup
+	for _, batch := range batches {
+		wg.Add(2)
+		go func(batch []data.Hashable) {
+			defer wg.Done()
+			for i := range batch {
+				m.Submit(batch[i : i+1])
+			}
+		}(batch)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				copied := m.Copy()
+				copied.Submit(batches[0][:1])
+				c.Check(len(copied.nodes) <= writers*perWriter, Equals, true)
+			}
+		}()
+	}
+	wg.Wait()
+	c.Assert(m.Copy().nodes, HasLen, writers*perWriter)
+	for _, batch := range batches {
+		for _, item := range batch {
+			node, err := m.get(*item.(data.Storer).NodeId())
+			c.Assert(err, IsNil)
+			c.Check(node, Equals, item)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Package rpc is a client for the HTTP JSON-RPC port of rippled, sending
+// the same commands as websockets.Remote for servers which do not expose
+// a websocket port.
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/atticlab/ripple/data"
+	"github.com/atticlab/ripple/websockets"
+	"github.com/golang/glog"
+)
+
+// Time allowed for a request and its response.
+const requestTimeout = 60 * time.Second
+
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+var _ websockets.Client = (*Client)(nil)
+
+// NewClient returns a client for the JSON-RPC server at the specified
+// endpoint URL, such as http://localhost:5005
+func NewClient(endpoint string) *Client {
+	return &Client{
+		endpoint: endpoint,
+		http:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+type request struct {
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+func newCommand(command string) *websockets.Command {
+	return &websockets.Command{Name: command}
+}
+
+// call posts a websocket command as the params of a JSON-RPC request and
+// decodes the result. Errors reported by rippled are *websockets.RippleError.
+func (c *Client) call(ctx context.Context, command interface{}, result interface{}) error {
+	b, err := json.Marshal(command)
+	if err != nil {
+		return err
+	}
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(b, &params); err != nil {
+		return err
+	}
+	var method string
+	if err := json.Unmarshal(params["command"], &method); err != nil {
+		return fmt.Errorf("Bad command: %s", err)
+	}
+	// Only meaningful to the websocket API
+	for _, field := range []string{"id", "command", "result", "Result"} {
+		delete(params, field)
+	}
+	b, err = json.Marshal(request{Method: method, Params: []interface{}{params}})
+	if err != nil {
+		return err
+	}
+	glog.V(2).Infoln(method, string(b))
+	req, err := http.NewRequest("POST", c.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed: %s %s", method, resp.Status, bytes.TrimSpace(b))
+	}
+	return websockets.DecodeResponse(b, result)
+}
+
+// Synchronously get a single transaction
+func (c *Client) Tx(ctx context.Context, hash data.Hash256) (*websockets.TxResult, error) {
+	cmd := &websockets.TxCommand{
+		Command:     newCommand("tx"),
+		Transaction: hash,
+	}
+	var result websockets.TxResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) accountTx(ctx context.Context, account data.Account, txs chan *data.TransactionWithMetaData, pageSize int, minLedger, maxLedger int64) {
+	defer close(txs)
+	var marker map[string]interface{}
+	for {
+		cmd := &websockets.AccountTxCommand{
+			Command:   newCommand("account_tx"),
+			Account:   account,
+			MinLedger: minLedger,
+			MaxLedger: maxLedger,
+			Limit:     pageSize,
+			Marker:    marker,
+		}
+		var result websockets.AccountTxResult
+		if err := c.call(ctx, cmd, &result); err != nil {
+			glog.Errorln(err.Error())
+			return
+		}
+		for _, tx := range result.Transactions {
+			select {
+			case txs <- tx:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if result.Marker == nil {
+			return
+		}
+		marker = result.Marker
+	}
+}
+
+// Retrieve all transactions for an account, calling account_tx until no
+// marker is returned, as websockets.Remote.AccountTx does.
+func (c *Client) AccountTx(ctx context.Context, account data.Account, pageSize int, minLedger, maxLedger int64) chan *data.TransactionWithMetaData {
+	txs := make(chan *data.TransactionWithMetaData)
+	go c.accountTx(ctx, account, txs, pageSize, minLedger, maxLedger)
+	return txs
+}
+
+// AccountTxPage requests the page of the transactions of q after marker,
+// for walking with a websockets.AccountTxIterator
+func (c *Client) AccountTxPage(ctx context.Context, q *websockets.AccountTxQuery, marker map[string]interface{}) (*websockets.AccountTxResult, error) {
+	cmd := &websockets.AccountTxCommand{
+		Command:   newCommand("account_tx"),
+		Account:   q.Account,
+		MinLedger: q.MinLedger,
+		MaxLedger: q.MaxLedger,
+		Forward:   q.Forward,
+		Limit:     q.PageSize,
+		Marker:    marker,
+	}
+	var result websockets.AccountTxResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Synchronously submit a single transaction
+func (c *Client) Submit(ctx context.Context, tx data.Transaction) (*websockets.SubmitResult, error) {
+	_, raw, err := data.Raw(tx)
+	if err != nil {
+		return nil, err
+	}
+	cmd := &websockets.SubmitCommand{
+		Command: newCommand("submit"),
+		TxBlob:  fmt.Sprintf("%X", raw),
+	}
+	var result websockets.SubmitResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Synchronously gets ledger entries
+func (c *Client) LedgerData(ctx context.Context, ledger websockets.LedgerIndex, marker *data.Hash256) (*websockets.LedgerDataResult, error) {
+	cmd := &websockets.LedgerDataCommand{
+		Command:        newCommand("ledger_data"),
+		LedgerSelector: ledger.Selector(),
+		Marker:         marker,
+	}
+	var result websockets.LedgerDataResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Synchronously gets the last closed ledger
+func (c *Client) ClosedLedger(ctx context.Context) (*websockets.LedgerClosedResult, error) {
+	cmd := &websockets.LedgerClosedCommand{
+		Command: newCommand("ledger_closed"),
+	}
+	var result websockets.LedgerClosedResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Synchronously gets a single ledger
+func (c *Client) Ledger(ctx context.Context, ledger websockets.LedgerIndex, transactions bool) (*websockets.LedgerResult, error) {
+	cmd := &websockets.LedgerCommand{
+		Command:        newCommand("ledger"),
+		LedgerSelector: ledger.Selector(),
+		Transactions:   transactions,
+		Expand:         true,
+	}
+	var result websockets.LedgerResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	result.Ledger.Transactions.Sort()
+	return &result, nil
+}
+
+func (c *Client) LedgerHeader(ctx context.Context, ledger websockets.LedgerIndex) (*websockets.LedgerHeaderResult, error) {
+	cmd := &websockets.LedgerHeaderCommand{
+		Command:        newCommand("ledger_header"),
+		LedgerSelector: ledger.Selector(),
+	}
+	var result websockets.LedgerHeaderResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Synchronously requests paths
+func (c *Client) RipplePathFind(ctx context.Context, src, dest data.Account, amount data.Amount, srcCurr *[]data.Currency) (*websockets.RipplePathFindResult, error) {
+	cmd := &websockets.RipplePathFindCommand{
+		Command:       newCommand("ripple_path_find"),
+		SrcAccount:    src,
+		SrcCurrencies: srcCurr,
+		DestAccount:   dest,
+		DestAmount:    amount,
+	}
+	var result websockets.RipplePathFindResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Synchronously requests account info
+func (c *Client) AccountInfo(ctx context.Context, a data.Account) (*websockets.AccountInfoResult, error) {
+	cmd := &websockets.AccountInfoCommand{
+		Command: newCommand("account_info"),
+		Account: a,
+	}
+	var result websockets.AccountInfoResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Synchronously requests all the trust lines of an account
+func (c *Client) AccountLines(ctx context.Context, account data.Account, ledger websockets.LedgerIndex) (*websockets.AccountLinesResult, error) {
+	var lines data.AccountLineSlice
+	var marker *data.Hash256
+	for {
+		cmd := &websockets.AccountLinesCommand{
+			Command:        newCommand("account_lines"),
+			Account:        account,
+			Limit:          400,
+			Marker:         marker,
+			LedgerSelector: ledger.Selector(),
+		}
+		var result websockets.AccountLinesResult
+		if err := c.call(ctx, cmd, &result); err != nil {
+			return nil, err
+		}
+		lines = append(lines, result.Lines...)
+		if result.Marker == nil {
+			result.Lines = lines
+			result.Lines.SortByCurrencyAmount()
+			return &result, nil
+		}
+		marker = result.Marker
+		if result.LedgerSequence != nil {
+			ledger = websockets.NewLedgerIndex(*result.LedgerSequence)
+		}
+	}
+}
+
+// Synchronously requests all the offers of an account
+func (c *Client) AccountOffers(ctx context.Context, account data.Account, ledger websockets.LedgerIndex) (*websockets.AccountOffersResult, error) {
+	var offers data.AccountOfferSlice
+	var marker *data.Hash256
+	for {
+		cmd := &websockets.AccountOffersCommand{
+			Command:        newCommand("account_offers"),
+			Account:        account,
+			Limit:          400,
+			Marker:         marker,
+			LedgerSelector: ledger.Selector(),
+		}
+		var result websockets.AccountOffersResult
+		if err := c.call(ctx, cmd, &result); err != nil {
+			return nil, err
+		}
+		offers = append(offers, result.Offers...)
+		if result.Marker == nil {
+			result.Offers = offers
+			sort.Sort(result.Offers)
+			return &result, nil
+		}
+		marker = result.Marker
+		if result.LedgerSequence != nil {
+			ledger = websockets.NewLedgerIndex(*result.LedgerSequence)
+		}
+	}
+}
+
+func (c *Client) BookOffers(ctx context.Context, taker data.Account, ledger websockets.LedgerIndex, pays, gets data.Asset) (*websockets.BookOffersResult, error) {
+	cmd := &websockets.BookOffersCommand{
+		Command:        newCommand("book_offers"),
+		LedgerSelector: ledger.Selector(),
+		Taker:          taker,
+		TakerPays:      pays,
+		TakerGets:      gets,
+		Limit:          5000,
+	}
+	var result websockets.BookOffersResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) AMMInfo(ctx context.Context, asset, asset2 data.Asset, ledger websockets.LedgerIndex) (*websockets.AMMInfoResult, error) {
+	cmd := &websockets.AMMInfoCommand{
+		Command:        newCommand("amm_info"),
+		Asset:          asset,
+		Asset2:         asset2,
+		LedgerSelector: ledger.Selector(),
+	}
+	var result websockets.AMMInfoResult
+	if err := c.call(ctx, cmd, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) Fee(ctx context.Context) (*websockets.FeeResult, error) {
+	var result websockets.FeeResult
+	if err := c.call(ctx, &websockets.FeeCommand{Command: newCommand("fee")}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Synchronously requests the status of the server
+func (c *Client) ServerInfo(ctx context.Context) (*websockets.ServerInfoResult, error) {
+	var result websockets.ServerInfoResult
+	if err := c.call(ctx, &websockets.ServerInfoCommand{Command: newCommand("server_info")}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atticlab/ripple/data"
+	"github.com/atticlab/ripple/websockets"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type ClientSuite struct{}
+
+var _ = Suite(&ClientSuite{})
+
+// serveFile responds to every request with the file, recording the requests
+func serveFile(c *C, path string, requests *[]request) *httptest.Server {
+	b, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var r request
+		c.Check(req.Method, Equals, "POST")
+		c.Check(json.NewDecoder(req.Body).Decode(&r), IsNil)
+		*requests = append(*requests, r)
+		w.Write(b)
+	}))
+}
+
+func (s *ClientSuite) TestAccountInfo(c *C) {
+	var requests []request
+	server := serveFile(c, "../websockets/testdata/account_info_rpc.json", &requests)
+	defer server.Close()
+
+	account, err := data.NewAccountFromAddress("rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(err, IsNil)
+	var client websockets.Client = NewClient(server.URL)
+	result, err := client.AccountInfo(context.Background(), *account)
+	c.Assert(err, IsNil)
+	c.Check(result.LedgerSequence, Equals, uint32(7636529))
+	c.Check(*result.AccountData.Sequence, Equals, uint32(546))
+
+	c.Assert(requests, HasLen, 1)
+	c.Check(requests[0].Method, Equals, "account_info")
+	c.Check(requests[0].Params, DeepEquals, []interface{}{
+		map[string]interface{}{"account": "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"},
+	})
+}
+
+func (s *ClientSuite) TestError(c *C) {
+	var requests []request
+	server := serveFile(c, "../websockets/testdata/account_info_rpc_error.json", &requests)
+	defer server.Close()
+
+	_, err := NewClient(server.URL).AccountInfo(context.Background(), data.Account{})
+	c.Assert(err, NotNil)
+	rippleErr, ok := err.(*websockets.RippleError)
+	c.Assert(ok, Equals, true)
+	c.Check(rippleErr.Name, Equals, "actNotFound")
+}
+
+func (s *ClientSuite) TestServerInfo(c *C) {
+	var requests []request
+	server := serveFile(c, "../websockets/testdata/server_info.json", &requests)
+	defer server.Close()
+
+	result, err := NewClient(server.URL).ServerInfo(context.Background())
+	c.Assert(err, IsNil)
+	c.Check(result.Info.ServerState, Equals, "full")
+	c.Assert(requests, HasLen, 1)
+	c.Check(requests[0].Method, Equals, "server_info")
+	c.Check(requests[0].Params, DeepEquals, []interface{}{map[string]interface{}{}})
+}
+
+func (s *ClientSuite) TestHTTPError(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).Fee(context.Background())
+	c.Assert(err, ErrorMatches, "fee failed: 403 Forbidden Forbidden")
+}
+
+func (s *ClientSuite) TestCancel(c *C) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewClient(server.URL).ServerInfo(ctx)
+	c.Assert(err, ErrorMatches, ".*context canceled")
+}
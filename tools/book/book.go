@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -50,7 +51,7 @@ func main() {
 	pays, err := data.NewAsset(os.Args[2])
 	checkErr(err)
 	var zeroAccount data.Account
-	result, err := remote.BookOffers(zeroAccount, "closed", *pays, *gets)
+	result, err := remote.BookOffers(context.Background(), zeroAccount, websockets.LedgerClosed, *pays, *gets)
 	checkErr(err)
 	// fmt.Println(*result.LedgerSequence) //TODO: wait for nikb fix
 	for _, offer := range result.Offers {
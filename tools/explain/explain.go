@@ -4,6 +4,7 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/hex"
 	"flag"
 	"fmt"
@@ -95,6 +96,7 @@ func main() {
 	r, err := websockets.NewRemote(*host)
 	checkErr(err)
 	glog.Infoln("Connected to: ", *host)
+	ctx := context.Background()
 	switch {
 	case len(matches) == 0:
 		showUsage()
@@ -102,13 +104,13 @@ func main() {
 		hash, err := data.NewHash256(matches[1])
 		checkErr(err)
 		fmt.Println("Getting transaction: ", hash.String())
-		result, err := r.Tx(*hash)
+		result, err := r.Tx(ctx, *hash)
 		checkErr(err)
 		explain(&result.TransactionWithMetaData, terminal.Default)
 	case len(matches[2]) > 0:
 		seq, err := strconv.ParseUint(matches[2], 10, 32)
 		checkErr(err)
-		ledger, err := r.Ledger(seq, true)
+		ledger, err := r.Ledger(ctx, websockets.NewLedgerIndex(uint32(seq)), true)
 		checkErr(err)
 		fmt.Println("Getting transactions for: ", seq)
 		for _, txm := range ledger.Ledger.Transactions {
@@ -118,7 +120,7 @@ func main() {
 		account, err := data.NewAccountFromAddress(matches[3])
 		checkErr(err)
 		fmt.Println("Getting transactions for: ", account.String())
-		for txm := range r.AccountTx(*account, *pageSize) {
+		for txm := range r.AccountTx(ctx, *account, *pageSize, -1, -1) {
 			explain(txm, terminal.ShowLedgerSequence)
 		}
 	case len(matches[4]) > 0:
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -47,7 +48,7 @@ func main() {
 	checkErr(err)
 	account, err := data.NewAccountFromAddress(os.Args[1])
 	checkErr(err)
-	result, err := remote.AccountOffers(*account, "closed")
+	result, err := remote.AccountOffers(context.Background(), *account, websockets.LedgerClosed)
 	checkErr(err)
 	fmt.Println(*result.LedgerSequence)
 	for _, offer := range result.Offers {
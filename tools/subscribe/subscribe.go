@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -29,7 +30,7 @@ func main() {
 	r, err := websockets.NewRemote(*host)
 	checkErr(err, true)
 
-	confirmation, err := r.Subscribe(true, !*proposed, *proposed, true)
+	confirmation, err := r.Subscribe(context.Background(), true, !*proposed, *proposed, true)
 	checkErr(err, true)
 	terminal.Println(fmt.Sprint("Subscribed at: ", confirmation.LedgerSequence), terminal.Default)
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -26,7 +27,7 @@ var (
 )
 
 func stream(r *websockets.Remote, filter *data.Account) {
-	confirmation, err := r.Subscribe(true, true, false, false)
+	confirmation, err := r.Subscribe(context.Background(), true, true, false, false)
 	checkErr(err, true)
 	log.Printf("Subscribed at: %d ", confirmation.LedgerSequence)
 
@@ -52,7 +53,7 @@ func stream(r *websockets.Remote, filter *data.Account) {
 
 func download(r *websockets.Remote, start, end uint32, filter *data.Account) {
 	for ledger := start; ledger <= end; ledger++ {
-		result, err := r.Ledger(ledger, true)
+		result, err := r.Ledger(context.Background(), websockets.NewLedgerIndex(ledger), true)
 		checkErr(err, true)
 		for _, tx := range result.Ledger.Transactions {
 			tx.LedgerSequence = result.Ledger.LedgerSequence
@@ -0,0 +1,136 @@
+package websockets
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/atticlab/ripple/data"
+	"github.com/golang/glog"
+)
+
+// Delay before retrying a page after a transient error, doubled after
+// each further failure
+var accountTxRetryDelay = time.Second
+
+// Attempts per page after a transient error when AccountTxQuery.Retries is 0
+var defaultAccountTxRetries = 3
+
+// AccountTxQuery selects the transactions walked by an AccountTxIterator
+type AccountTxQuery struct {
+	Account   data.Account
+	MinLedger int64 // -1 for the earliest ledger available
+	MaxLedger int64 // -1 for the most recent validated ledger
+	Forward   bool  // Oldest first, rather than newest first
+	PageSize  int
+	Retries   int // Attempts per page after a transient error, 0 for the default and -1 for none
+}
+
+// AccountTxPager requests a single page of account_tx, continuing from
+// the marker of the previous page. Implemented by Remote and rpc.Client.
+type AccountTxPager interface {
+	AccountTxPage(ctx context.Context, q *AccountTxQuery, marker map[string]interface{}) (*AccountTxResult, error)
+}
+
+// AccountTxIterator delivers the transactions of a query on C, requesting
+// each page in turn, until the last page or the first error which could
+// not be retried. Err reports that error once C is closed.
+//
+//	it := NewAccountTxIterator(ctx, remote, query)
+//	for txm := range it.C {
+//	}
+//	if err := it.Err(); err != nil {
+type AccountTxIterator struct {
+	C   <-chan *data.TransactionWithMetaData
+	err error
+}
+
+func NewAccountTxIterator(ctx context.Context, pager AccountTxPager, q AccountTxQuery) *AccountTxIterator {
+	c := make(chan *data.TransactionWithMetaData)
+	it := &AccountTxIterator{C: c}
+	go func() {
+		defer close(c)
+		it.err = it.run(ctx, pager, &q, c)
+	}()
+	return it
+}
+
+// Err returns the error which ended the iteration early, which is only
+// valid once C is closed
+func (it *AccountTxIterator) Err() error { return it.err }
+
+func (it *AccountTxIterator) run(ctx context.Context, pager AccountTxPager, q *AccountTxQuery, c chan<- *data.TransactionWithMetaData) error {
+	var marker map[string]interface{}
+	for {
+		result, err := accountTxPage(ctx, pager, q, marker)
+		if err != nil {
+			return err
+		}
+		for _, txm := range result.Transactions {
+			// Stop promptly, rather than when the select picks ctx
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			select {
+			case c <- txm:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if result.Marker == nil {
+			return nil
+		}
+		marker = result.Marker
+	}
+}
+
+func accountTxPage(ctx context.Context, pager AccountTxPager, q *AccountTxQuery, marker map[string]interface{}) (*AccountTxResult, error) {
+	retries := q.Retries
+	if retries == 0 {
+		retries = defaultAccountTxRetries
+	}
+	delay := accountTxRetryDelay
+	for attempt := 0; ; attempt++ {
+		result, err := pager.AccountTxPage(ctx, q, marker)
+		if err == nil || attempt >= retries || !isTransient(err) {
+			return result, err
+		}
+		glog.Warningf("account_tx: %s retrying in %s", err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// Errors which rippled reports when it is overloaded or out of sync
+var transientErrors = map[string]bool{
+	"tooBusy":   true,
+	"slowDown":  true,
+	"noNetwork": true,
+	"noCurrent": true,
+	"noClosed":  true,
+}
+
+func isTransient(err error) bool {
+	if name, ok := errorName(err); ok {
+		return transientErrors[name]
+	}
+	if e, ok := err.(net.Error); ok {
+		return e.Timeout()
+	}
+	return false
+}
+
+// AccountTxPage requests the page of the transactions of q after marker,
+// which is nil for the first page
+func (r *Remote) AccountTxPage(ctx context.Context, q *AccountTxQuery, marker map[string]interface{}) (*AccountTxResult, error) {
+	cmd := newAccountTxCommand(q.Account, q.PageSize, marker, q.MinLedger, q.MaxLedger)
+	cmd.Forward = q.Forward
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return cmd.Result, nil
+}
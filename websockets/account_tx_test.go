@@ -0,0 +1,105 @@
+package websockets
+
+import (
+	"context"
+	"time"
+
+	"github.com/atticlab/ripple/data"
+	. "gopkg.in/check.v1"
+)
+
+type AccountTxSuite struct{}
+
+var _ = Suite(&AccountTxSuite{})
+
+// pages serves the ledgers of each page in turn, failing with the
+// errors first
+type pages struct {
+	ledgers [][]uint32
+	errors  []error
+	markers []map[string]interface{}
+	forward []bool
+}
+
+func (p *pages) AccountTxPage(ctx context.Context, q *AccountTxQuery, marker map[string]interface{}) (*AccountTxResult, error) {
+	if len(p.errors) > 0 {
+		err := p.errors[0]
+		p.errors = p.errors[1:]
+		return nil, err
+	}
+	p.markers = append(p.markers, marker)
+	p.forward = append(p.forward, q.Forward)
+	page := len(p.markers) - 1
+	result := &AccountTxResult{}
+	for _, ledger := range p.ledgers[page] {
+		result.Transactions = append(result.Transactions, &data.TransactionWithMetaData{LedgerSequence: ledger})
+	}
+	if page < len(p.ledgers)-1 {
+		result.Marker = map[string]interface{}{"ledger": float64(page), "seq": float64(0)}
+	}
+	return result, nil
+}
+
+func collect(it *AccountTxIterator) []uint32 {
+	var ledgers []uint32
+	for txm := range it.C {
+		ledgers = append(ledgers, txm.LedgerSequence)
+	}
+	return ledgers
+}
+
+func (s *AccountTxSuite) TestPages(c *C) {
+	defer func(delay time.Duration) { accountTxRetryDelay = delay }(accountTxRetryDelay)
+	accountTxRetryDelay = time.Millisecond
+
+	pager := &pages{
+		ledgers: [][]uint32{{1, 2}, {3}, {4, 5}},
+		errors:  []error{&CommandError{Name: "tooBusy", Code: 9}},
+	}
+	it := NewAccountTxIterator(context.Background(), pager, AccountTxQuery{Forward: true, Retries: 1})
+	c.Check(collect(it), DeepEquals, []uint32{1, 2, 3, 4, 5})
+	c.Check(it.Err(), IsNil)
+	c.Check(pager.markers, DeepEquals, []map[string]interface{}{
+		nil,
+		{"ledger": float64(0), "seq": float64(0)},
+		{"ledger": float64(1), "seq": float64(0)},
+	})
+	c.Check(pager.forward, DeepEquals, []bool{true, true, true})
+}
+
+func (s *AccountTxSuite) TestErrors(c *C) {
+	defer func(delay time.Duration) { accountTxRetryDelay = delay }(accountTxRetryDelay)
+	accountTxRetryDelay = time.Millisecond
+
+	// Out of retries
+	busy := &RippleError{Name: "tooBusy"}
+	it := NewAccountTxIterator(context.Background(), &pages{errors: []error{busy, busy}}, AccountTxQuery{Retries: 1})
+	c.Check(collect(it), HasLen, 0)
+	c.Check(it.Err(), Equals, busy)
+
+	// Retries of 0 uses the default and -1 disables them
+	defer func(retries int) { defaultAccountTxRetries = retries }(defaultAccountTxRetries)
+	defaultAccountTxRetries = 2
+	pager := &pages{ledgers: [][]uint32{{1}}, errors: []error{busy, busy}}
+	it = NewAccountTxIterator(context.Background(), pager, AccountTxQuery{})
+	c.Check(collect(it), DeepEquals, []uint32{1})
+	c.Check(it.Err(), IsNil)
+	it = NewAccountTxIterator(context.Background(), &pages{ledgers: [][]uint32{{1}}, errors: []error{busy}}, AccountTxQuery{Retries: -1})
+	c.Check(collect(it), HasLen, 0)
+	c.Check(it.Err(), Equals, busy)
+
+	// Not transient
+	notFound := &RippleError{Name: "actNotFound"}
+	pager = &pages{ledgers: [][]uint32{{1}}, errors: []error{notFound}}
+	it = NewAccountTxIterator(context.Background(), pager, AccountTxQuery{Retries: 5})
+	c.Check(collect(it), HasLen, 0)
+	c.Check(it.Err(), Equals, notFound)
+	c.Check(pager.markers, HasLen, 0)
+
+	// Cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = NewAccountTxIterator(ctx, &pages{ledgers: [][]uint32{{1, 2}}}, AccountTxQuery{})
+	c.Check(collect(it), HasLen, 0)
+	c.Check(it.Err(), Equals, context.Canceled)
+}
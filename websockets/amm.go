@@ -0,0 +1,96 @@
+package websockets
+
+import (
+	"context"
+
+	"github.com/atticlab/ripple/data"
+)
+
+type AMMAuthAccount struct {
+	Account data.Account `json:"account"`
+}
+
+type AMMAuctionSlot struct {
+	Account       data.Account     `json:"account"`
+	AuthAccounts  []AMMAuthAccount `json:"auth_accounts,omitempty"`
+	DiscountedFee uint32           `json:"discounted_fee"`
+	Expiration    string           `json:"expiration"`
+	Price         data.Amount      `json:"price"`
+	TimeInterval  uint32           `json:"time_interval"`
+}
+
+type AMMVoteSlot struct {
+	Account    data.Account `json:"account"`
+	TradingFee uint32       `json:"trading_fee"`
+	VoteWeight uint32       `json:"vote_weight"`
+}
+
+// AMMInfo describes an automated market maker pool as returned by amm_info.
+// Fees are in units of 1/100,000 and vote weights sum to 100,000.
+type AMMInfo struct {
+	Account      data.Account    `json:"account"`
+	Amount       data.Amount     `json:"amount"`
+	Amount2      data.Amount     `json:"amount2"`
+	Asset2Frozen bool            `json:"asset2_frozen,omitempty"`
+	AuctionSlot  *AMMAuctionSlot `json:"auction_slot,omitempty"`
+	LPToken      data.Amount     `json:"lp_token"`
+	TradingFee   uint32          `json:"trading_fee"`
+	VoteSlots    []AMMVoteSlot   `json:"vote_slots,omitempty"`
+}
+
+// ComputeTradingFee returns the average of the fees voted for weighted by
+// vote weight, which is proportional to the LP tokens held by each voter.
+// As in rippled the result is rounded to the nearest integer, ties to even.
+func ComputeTradingFee(voteSlots []AMMVoteSlot) uint32 {
+	var num, den uint64
+	for _, slot := range voteSlots {
+		num += uint64(slot.VoteWeight) * uint64(slot.TradingFee)
+		den += uint64(slot.VoteWeight)
+	}
+	if den == 0 {
+		return 0
+	}
+	fee, remainder := num/den, num%den
+	if 2*remainder > den || (2*remainder == den && fee%2 == 1) {
+		fee++
+	}
+	return uint32(fee)
+}
+
+type AMMInfoCommand struct {
+	*Command
+	Asset  data.Asset `json:"asset"`
+	Asset2 data.Asset `json:"asset2"`
+	LedgerSelector
+	Result *AMMInfoResult `json:"result,omitempty"`
+}
+
+type AMMInfoResult struct {
+	AMM                AMMInfo `json:"amm"`
+	LedgerSequence     uint32  `json:"ledger_index"`
+	LedgerCurrentIndex uint32  `json:"ledger_current_index"`
+	Validated          bool    `json:"validated"`
+}
+
+// UnmarshalAMMInfo parses an amm_info response in any of the forms
+// accepted by DecodeResponse.
+func UnmarshalAMMInfo(b []byte) (AMMInfo, error) {
+	var result AMMInfoResult
+	if err := DecodeResponse(b, &result); err != nil {
+		return AMMInfo{}, err
+	}
+	return result.AMM, nil
+}
+
+func (r *Remote) AMMInfo(ctx context.Context, asset, asset2 data.Asset, ledger LedgerIndex) (*AMMInfoResult, error) {
+	cmd := &AMMInfoCommand{
+		Command:        newCommand("amm_info"),
+		Asset:          asset,
+		Asset2:         asset2,
+		LedgerSelector: ledger.Selector(),
+	}
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return cmd.Result, nil
+}
@@ -0,0 +1,56 @@
+package websockets
+
+import (
+	"io/ioutil"
+
+	. "gopkg.in/check.v1"
+)
+
+type AMMSuite struct{}
+
+var _ = Suite(&AMMSuite{})
+
+func (s *AMMSuite) TestUnmarshalAMMInfo(c *C) {
+	b, err := ioutil.ReadFile("testdata/amm_info.json")
+	c.Assert(err, IsNil)
+	amm, err := UnmarshalAMMInfo(b)
+	c.Assert(err, IsNil)
+	c.Assert(amm.Account.String(), Equals, "rp9E3FN3gNmvePGhYnf414T2TkUuoxu8vM")
+	c.Assert(amm.Amount.String(), Equals, "227.993374/XRP")
+	c.Assert(amm.Amount2.String(), Equals, "259.3398493922455/USD/rhpHaFggC92ELty3n3yDEtuFgWxXWkUFET")
+	c.Assert(amm.LPToken.Value.String(), Equals, "7611.779223147337")
+	c.Assert(amm.LPToken.Issuer, Equals, amm.Account)
+	c.Assert(amm.TradingFee, Equals, uint32(600))
+
+	c.Assert(amm.AuctionSlot, NotNil)
+	c.Assert(amm.AuctionSlot.Account.String(), Equals, "rJVUeRqDFNs2xqA7ncVE6ZoAhPUoaJJSQm")
+	c.Assert(amm.AuctionSlot.AuthAccounts, HasLen, 1)
+	c.Assert(amm.AuctionSlot.AuthAccounts[0].Account.String(), Equals, "rMKXGCbJ5d8LbrqthdG46q3f969MVK2Qeg")
+	c.Assert(amm.AuctionSlot.DiscountedFee, Equals, uint32(60))
+	c.Assert(amm.AuctionSlot.Price.Value.String(), Equals, "32.4")
+	c.Assert(amm.AuctionSlot.TimeInterval, Equals, uint32(20))
+
+	c.Assert(amm.VoteSlots, HasLen, 1)
+	c.Assert(amm.VoteSlots[0].TradingFee, Equals, uint32(600))
+	c.Assert(amm.VoteSlots[0].VoteWeight, Equals, uint32(100000))
+}
+
+func (s *AMMSuite) TestComputeTradingFee(c *C) {
+	tests := []struct {
+		slots    []AMMVoteSlot
+		expected uint32
+	}{
+		{nil, 0},
+		{[]AMMVoteSlot{{TradingFee: 600, VoteWeight: 100000}}, 600},
+		{[]AMMVoteSlot{{TradingFee: 1000, VoteWeight: 25000}, {TradingFee: 200, VoteWeight: 75000}}, 400},
+		{[]AMMVoteSlot{{TradingFee: 1000, VoteWeight: 10000}, {TradingFee: 500, VoteWeight: 30000}, {TradingFee: 0, VoteWeight: 60000}}, 250},
+		{[]AMMVoteSlot{{TradingFee: 3, VoteWeight: 1}, {TradingFee: 0, VoteWeight: 2}}, 1},                                  // 1 exactly
+		{[]AMMVoteSlot{{TradingFee: 1, VoteWeight: 1}, {TradingFee: 0, VoteWeight: 1}}, 0},                                  // 0.5 ties to even
+		{[]AMMVoteSlot{{TradingFee: 3, VoteWeight: 1}, {TradingFee: 0, VoteWeight: 1}}, 2},                                  // 1.5 ties to even
+		{[]AMMVoteSlot{{TradingFee: 1000, VoteWeight: 33333}, {TradingFee: 999, VoteWeight: 66667}}, 999},                   // 999.33
+		{[]AMMVoteSlot{{TradingFee: 1000, VoteWeight: 66667}, {TradingFee: 999, VoteWeight: 33333}, {VoteWeight: 0}}, 1000}, // 999.67
+	}
+	for i, test := range tests {
+		c.Check(ComputeTradingFee(test.slots), Equals, test.expected, Commentf("Test: %d", i))
+	}
+}
@@ -0,0 +1,91 @@
+package websockets
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/atticlab/ripple/data"
+)
+
+// AutofillOptions limit the fee chosen by Autofill
+type AutofillOptions struct {
+	FeeCushion float64 // Multiple of the open ledger fee to pay, 1.2 if zero
+	MaxFee     uint64  // Maximum fee in drops, including any signers, 2 XRP if zero
+	Signers    int     // Number of signers of a multi-signed transaction
+}
+
+var DefaultAutofillOptions = AutofillOptions{
+	FeeCushion: 1.2,
+	MaxFee:     2000000,
+}
+
+// Autofill populates the Fee, Sequence and LastLedgerSequence of tx before
+// signing, when they are not already set. The fee is the open ledger fee
+// reported by the fee command, which escalates while the open ledger is
+// full, multiplied by the cushion and by the signers of a multi-signed
+// transaction, and capped at MaxFee. Sequence is taken from account_info
+// unless a TicketSequence is used instead. LastLedgerSequence allows the
+// same number of ledgers as SubmitAndWait. A nil opts uses
+// DefaultAutofillOptions.
+func Autofill(ctx context.Context, client Client, tx data.Transaction, opts *AutofillOptions) error {
+	if opts == nil {
+		opts = &DefaultAutofillOptions
+	}
+	base := tx.GetBase()
+	if base.Fee.IsZero() {
+		fee, err := autofillFee(ctx, client, opts)
+		if err != nil {
+			return err
+		}
+		base.Fee = *fee
+	}
+	if _, ticket := base.SequenceOrTicket(); base.Sequence == 0 && !ticket {
+		info, err := client.AccountInfo(ctx, base.Account)
+		if err != nil {
+			return err
+		}
+		if info.AccountData.Sequence == nil {
+			return fmt.Errorf("No Sequence for account: %s", base.Account)
+		}
+		base.Sequence = *info.AccountData.Sequence
+	}
+	if base.LastLedgerSequence == nil {
+		validated, err := validatedLedger(ctx, client)
+		if err != nil {
+			return err
+		}
+		last := validated + 1 + lastLedgerOffset
+		base.LastLedgerSequence = &last
+	}
+	return nil
+}
+
+func autofillFee(ctx context.Context, client Client, opts *AutofillOptions) (*data.Value, error) {
+	result, err := client.Fee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	drops := result.Drops.OpenLedgerFee.Drops()
+	if base := result.Drops.BaseFee.Drops(); drops < base {
+		drops = base
+	}
+	if drops == 0 {
+		return nil, fmt.Errorf("No fee reported: %+v", result.Drops)
+	}
+	cushion, max := opts.FeeCushion, opts.MaxFee
+	if cushion == 0 {
+		cushion = DefaultAutofillOptions.FeeCushion
+	}
+	if max == 0 {
+		max = DefaultAutofillOptions.MaxFee
+	}
+	fee := uint64(math.Ceil(float64(drops) * cushion))
+	if opts.Signers > 0 {
+		fee = data.MultisignFee(fee, opts.Signers)
+	}
+	if fee > max {
+		fee = max
+	}
+	return data.NewNativeValue(int64(fee))
+}
@@ -0,0 +1,91 @@
+package websockets
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/atticlab/ripple/data"
+	. "gopkg.in/check.v1"
+)
+
+type AutofillSuite struct{}
+
+var _ = Suite(&AutofillSuite{})
+
+// escalated reports an open ledger fee of 2000 drops and an account
+// Sequence of 42
+type escalated struct {
+	ledgers
+	accounts int
+}
+
+func (e *escalated) Fee(ctx context.Context) (*FeeResult, error) {
+	var result FeeResult
+	fee := `{"drops":{"base_fee":"10","median_fee":"5000","minimum_fee":"10","open_ledger_fee":"2000"}}`
+	if err := json.Unmarshal([]byte(fee), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (e *escalated) AccountInfo(ctx context.Context, account data.Account) (*AccountInfoResult, error) {
+	e.accounts++
+	sequence := uint32(42)
+	result := &AccountInfoResult{}
+	result.AccountData.Sequence = &sequence
+	return result, nil
+}
+
+func (s *AutofillSuite) payment(c *C, fields string) data.Transaction {
+	var txm data.TransactionWithMetaData
+	test := `{"TransactionType":"Payment","Account":"rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de","Destination":"rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn","Amount":"1000000","Flags":0` + fields + `}`
+	c.Assert(json.Unmarshal([]byte(test), &txm), IsNil)
+	return txm.Transaction
+}
+
+func (s *AutofillSuite) TestAutofill(c *C) {
+	client := &escalated{ledgers: ledgers{validated: 1000}}
+	tx := s.payment(c, "")
+	c.Assert(Autofill(context.Background(), client, tx, nil), IsNil)
+	base := tx.GetBase()
+	c.Check(base.Fee.Drops(), Equals, uint64(2400))
+	c.Check(base.Sequence, Equals, uint32(42))
+	c.Check(*base.LastLedgerSequence, Equals, uint32(1022))
+}
+
+func (s *AutofillSuite) TestPreserve(c *C) {
+	client := &escalated{ledgers: ledgers{validated: 1000}}
+	tx := s.payment(c, `,"Fee":"12","Sequence":7,"LastLedgerSequence":1010`)
+	c.Assert(Autofill(context.Background(), client, tx, nil), IsNil)
+	base := tx.GetBase()
+	c.Check(base.Fee.Drops(), Equals, uint64(12))
+	c.Check(base.Sequence, Equals, uint32(7))
+	c.Check(*base.LastLedgerSequence, Equals, uint32(1010))
+	c.Check(client.accounts, Equals, 0)
+	c.Check(client.validated, Equals, uint32(1000))
+}
+
+func (s *AutofillSuite) TestTicket(c *C) {
+	client := &escalated{ledgers: ledgers{validated: 1000}}
+	tx := s.payment(c, `,"Sequence":0,"TicketSequence":3`)
+	c.Assert(Autofill(context.Background(), client, tx, nil), IsNil)
+	c.Check(tx.GetBase().Sequence, Equals, uint32(0))
+	c.Check(client.accounts, Equals, 0)
+}
+
+func (s *AutofillSuite) TestFeeLimits(c *C) {
+	for _, test := range []struct {
+		opts     AutofillOptions
+		expected uint64
+	}{
+		{AutofillOptions{FeeCushion: 1}, 2000},
+		{AutofillOptions{FeeCushion: 1.5, Signers: 2}, 9000},
+		{AutofillOptions{MaxFee: 1000}, 1000},
+		{AutofillOptions{Signers: 3, MaxFee: 5000}, 5000},
+	} {
+		client := &escalated{ledgers: ledgers{validated: 1000}}
+		tx := s.payment(c, "")
+		c.Assert(Autofill(context.Background(), client, tx, &test.opts), IsNil)
+		c.Check(tx.GetBase().Fee.Drops(), Equals, test.expected, Commentf("%+v", test.opts))
+	}
+}
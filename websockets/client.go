@@ -0,0 +1,32 @@
+package websockets
+
+import (
+	"context"
+
+	"github.com/atticlab/ripple/data"
+)
+
+// Client is the synchronous request API of a Remote. It is also
+// implemented by rpc.Client, for servers which only expose JSON-RPC, so
+// that callers can use either transport. Calls return the error of ctx if
+// it is done before the response arrives.
+type Client interface {
+	Tx(ctx context.Context, hash data.Hash256) (*TxResult, error)
+	AccountTx(ctx context.Context, account data.Account, pageSize int, minLedger, maxLedger int64) chan *data.TransactionWithMetaData
+	AccountTxPage(ctx context.Context, q *AccountTxQuery, marker map[string]interface{}) (*AccountTxResult, error)
+	Submit(ctx context.Context, tx data.Transaction) (*SubmitResult, error)
+	LedgerData(ctx context.Context, ledger LedgerIndex, marker *data.Hash256) (*LedgerDataResult, error)
+	ClosedLedger(ctx context.Context) (*LedgerClosedResult, error)
+	Ledger(ctx context.Context, ledger LedgerIndex, transactions bool) (*LedgerResult, error)
+	LedgerHeader(ctx context.Context, ledger LedgerIndex) (*LedgerHeaderResult, error)
+	RipplePathFind(ctx context.Context, src, dest data.Account, amount data.Amount, srcCurr *[]data.Currency) (*RipplePathFindResult, error)
+	AccountInfo(ctx context.Context, a data.Account) (*AccountInfoResult, error)
+	AccountLines(ctx context.Context, account data.Account, ledger LedgerIndex) (*AccountLinesResult, error)
+	AccountOffers(ctx context.Context, account data.Account, ledger LedgerIndex) (*AccountOffersResult, error)
+	BookOffers(ctx context.Context, taker data.Account, ledger LedgerIndex, pays, gets data.Asset) (*BookOffersResult, error)
+	AMMInfo(ctx context.Context, asset, asset2 data.Asset, ledger LedgerIndex) (*AMMInfoResult, error)
+	Fee(ctx context.Context) (*FeeResult, error)
+	ServerInfo(ctx context.Context) (*ServerInfoResult, error)
+}
+
+var _ Client = (*Remote)(nil)
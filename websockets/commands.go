@@ -13,6 +13,7 @@ var counter uint64
 type Syncer interface {
 	Done()
 	Fail(message string)
+	command() *Command
 }
 
 type CommandError struct {
@@ -43,6 +44,8 @@ func (c *Command) Fail(message string) {
 	c.Ready <- struct{}{}
 }
 
+func (c *Command) command() *Command { return c }
+
 func (c *Command) IncrementId() {
 	c.Id = atomic.AddUint64(&counter, 1)
 }
@@ -55,7 +58,7 @@ func newCommand(command string) *Command {
 	return &Command{
 		Id:    atomic.AddUint64(&counter, 1),
 		Name:  command,
-		Ready: make(chan struct{}),
+		Ready: make(chan struct{}, 1), // Never blocks on an abandoned call
 	}
 }
 
@@ -87,12 +90,12 @@ func newAccountTxCommand(account data.Account, pageSize int, marker map[string]i
 	}
 }
 
-func newBinaryLedgerDataCommand(ledger interface{}, marker *data.Hash256) *BinaryLedgerDataCommand {
+func newBinaryLedgerDataCommand(ledger LedgerIndex, marker *data.Hash256) *BinaryLedgerDataCommand {
 	return &BinaryLedgerDataCommand{
-		Command: newCommand("ledger_data"),
-		Ledger:  ledger,
-		Binary:  true,
-		Marker:  marker,
+		Command:        newCommand("ledger_data"),
+		LedgerSelector: ledger.Selector(),
+		Binary:         true,
+		Marker:         marker,
 	}
 }
 
@@ -150,7 +153,7 @@ type LedgerClosedResult struct {
 
 type LedgerCommand struct {
 	*Command
-	LedgerIndex  interface{}   `json:"ledger_index"`
+	LedgerSelector
 	Accounts     bool          `json:"accounts"`
 	Transactions bool          `json:"transactions"`
 	Expand       bool          `json:"expand"`
@@ -163,7 +166,7 @@ type LedgerResult struct {
 
 type LedgerHeaderCommand struct {
 	*Command
-	Ledger interface{} `json:"ledger"`
+	LedgerSelector
 	Result *LedgerHeaderResult
 }
 
@@ -176,14 +179,14 @@ type LedgerHeaderResult struct {
 
 type LedgerDataCommand struct {
 	*Command
-	Ledger interface{}       `json:"ledger"`
+	LedgerSelector
 	Marker *data.Hash256     `json:"marker,omitempty"`
 	Result *LedgerDataResult `json:"result,omitempty"`
 }
 
 type BinaryLedgerDataCommand struct {
 	*Command
-	Ledger interface{}             `json:"ledger"`
+	LedgerSelector
 	Binary bool                    `json:"binary"`
 	Marker *data.Hash256           `json:"marker,omitempty"`
 	Result *BinaryLedgerDataResult `json:"result,omitempty"`
@@ -240,11 +243,11 @@ type AccountInfoResult struct {
 
 type AccountLinesCommand struct {
 	*Command
-	Account     data.Account        `json:"account"`
-	Limit       uint32              `json:"limit"`
-	LedgerIndex interface{}         `json:"ledger_index,omitempty"`
-	Marker      *data.Hash256       `json:"marker,omitempty"`
-	Result      *AccountLinesResult `json:"result,omitempty"`
+	Account data.Account `json:"account"`
+	Limit   uint32       `json:"limit"`
+	LedgerSelector
+	Marker *data.Hash256       `json:"marker,omitempty"`
+	Result *AccountLinesResult `json:"result,omitempty"`
 }
 
 type AccountLinesResult struct {
@@ -256,11 +259,11 @@ type AccountLinesResult struct {
 
 type AccountOffersCommand struct {
 	*Command
-	Account     data.Account         `json:"account"`
-	Limit       uint32               `json:"limit"`
-	LedgerIndex interface{}          `json:"ledger_index,omitempty"`
-	Marker      *data.Hash256        `json:"marker,omitempty"`
-	Result      *AccountOffersResult `json:"result,omitempty"`
+	Account data.Account `json:"account"`
+	Limit   uint32       `json:"limit"`
+	LedgerSelector
+	Marker *data.Hash256        `json:"marker,omitempty"`
+	Result *AccountOffersResult `json:"result,omitempty"`
 }
 
 type AccountOffersResult struct {
@@ -272,12 +275,12 @@ type AccountOffersResult struct {
 
 type BookOffersCommand struct {
 	*Command
-	LedgerIndex interface{}  `json:"ledger_index,omitempty"`
-	Taker       data.Account `json:"taker"`
-	TakerPays   data.Asset   `json:"taker_pays"`
-	TakerGets   data.Asset   `json:"taker_gets"`
-	Limit       uint32       `json:"limit"`
-	Result      *BookOffersResult
+	LedgerSelector
+	Taker     data.Account `json:"taker"`
+	TakerPays data.Asset   `json:"taker_pays"`
+	TakerGets data.Asset   `json:"taker_gets"`
+	Limit     uint32       `json:"limit"`
+	Result    *BookOffersResult
 }
 
 type BookOffersResult struct {
@@ -309,3 +312,30 @@ type FeeResult struct {
 	MaxQueueSize uint32 `json:"max_queue_size,string"`
 	Status       string `json:"status"`
 }
+
+type ServerInfoCommand struct {
+	*Command
+	Result *ServerInfoResult
+}
+
+type ServerInfoResult struct {
+	Info struct {
+		BuildVersion    string  `json:"build_version"`
+		CompleteLedgers string  `json:"complete_ledgers"`
+		HostID          string  `json:"hostid"`
+		LoadFactor      float64 `json:"load_factor"`
+		NetworkID       uint32  `json:"network_id"`
+		Peers           uint32  `json:"peers"`
+		PubkeyNode      string  `json:"pubkey_node"`
+		ServerState     string  `json:"server_state"`
+		Uptime          uint64  `json:"uptime"`
+		ValidatedLedger *struct {
+			Age            uint32       `json:"age"`
+			BaseFeeXRP     float64      `json:"base_fee_xrp"`
+			Hash           data.Hash256 `json:"hash"`
+			ReserveBaseXRP float64      `json:"reserve_base_xrp"`
+			ReserveIncXRP  float64      `json:"reserve_inc_xrp"`
+			LedgerSequence uint32       `json:"seq"`
+		} `json:"validated_ledger"`
+	} `json:"info"`
+}
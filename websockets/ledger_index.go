@@ -0,0 +1,116 @@
+package websockets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/atticlab/ripple/data"
+)
+
+// LedgerIndex is either a ledger sequence, a ledger hash or one of the
+// keywords "validated", "closed" or "current" accepted by rippled. The zero
+// LedgerIndex selects no ledger, leaving the choice to rippled.
+type LedgerIndex struct {
+	sequence uint32
+	keyword  string
+	hash     data.Hash256
+}
+
+var (
+	LedgerValidated = LedgerIndex{keyword: "validated"}
+	LedgerClosed    = LedgerIndex{keyword: "closed"}
+	LedgerCurrent   = LedgerIndex{keyword: "current"}
+)
+
+func NewLedgerIndex(sequence uint32) LedgerIndex {
+	return LedgerIndex{sequence: sequence}
+}
+
+func NewLedgerHash(hash data.Hash256) LedgerIndex {
+	return LedgerIndex{hash: hash}
+}
+
+func (l LedgerIndex) IsSymbolic() bool   { return l.keyword != "" }
+func (l LedgerIndex) IsHash() bool       { return !l.hash.IsZero() }
+func (l LedgerIndex) IsZero() bool       { return l == LedgerIndex{} }
+func (l LedgerIndex) Keyword() string    { return l.keyword }
+func (l LedgerIndex) Sequence() uint32   { return l.sequence }
+func (l LedgerIndex) Hash() data.Hash256 { return l.hash }
+
+func (l LedgerIndex) String() string {
+	switch {
+	case l.IsSymbolic():
+		return l.keyword
+	case l.IsHash():
+		return l.hash.String()
+	default:
+		return strconv.FormatUint(uint64(l.sequence), 10)
+	}
+}
+
+func (l LedgerIndex) MarshalJSON() ([]byte, error) {
+	switch {
+	case l.IsSymbolic():
+		return json.Marshal(l.keyword)
+	case l.IsHash():
+		return json.Marshal(l.hash)
+	default:
+		return json.Marshal(l.sequence)
+	}
+}
+
+func (l *LedgerIndex) UnmarshalJSON(b []byte) error {
+	if !bytes.HasPrefix(b, []byte(`"`)) {
+		var sequence uint32
+		if err := json.Unmarshal(b, &sequence); err != nil {
+			return err
+		}
+		*l = NewLedgerIndex(sequence)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	switch s {
+	case LedgerValidated.keyword, LedgerClosed.keyword, LedgerCurrent.keyword:
+		*l = LedgerIndex{keyword: s}
+		return nil
+	}
+	if len(s) == 64 {
+		hash, err := data.NewHash256(s)
+		if err != nil {
+			return fmt.Errorf("Unknown ledger index: %s", s)
+		}
+		*l = NewLedgerHash(*hash)
+		return nil
+	}
+	// Some responses quote the sequence
+	sequence, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return fmt.Errorf("Unknown ledger index: %s", s)
+	}
+	*l = NewLedgerIndex(uint32(sequence))
+	return nil
+}
+
+// LedgerSelector picks the ledger of a request by ledger_hash or
+// ledger_index, or leaves the choice to rippled when both are omitted
+type LedgerSelector struct {
+	LedgerIndex *LedgerIndex  `json:"ledger_index,omitempty"`
+	LedgerHash  *data.Hash256 `json:"ledger_hash,omitempty"`
+}
+
+// Selector returns the LedgerSelector for l
+func (l LedgerIndex) Selector() LedgerSelector {
+	switch {
+	case l.IsZero():
+		return LedgerSelector{}
+	case l.IsHash():
+		return LedgerSelector{LedgerHash: &l.hash}
+	default:
+		return LedgerSelector{LedgerIndex: &l}
+	}
+}
@@ -0,0 +1,77 @@
+package websockets
+
+import (
+	"encoding/json"
+
+	"github.com/atticlab/ripple/data"
+	. "gopkg.in/check.v1"
+)
+
+type LedgerIndexSuite struct{}
+
+var _ = Suite(&LedgerIndexSuite{})
+
+func (s *LedgerIndexSuite) TestLedgerIndexJSON(c *C) {
+	tests := []struct {
+		json     string
+		expected LedgerIndex
+		symbolic bool
+	}{
+		{`"validated"`, LedgerValidated, true},
+		{`"closed"`, LedgerClosed, true},
+		{`"current"`, LedgerCurrent, true},
+		{`6917762`, NewLedgerIndex(6917762), false},
+	}
+	for _, test := range tests {
+		var l LedgerIndex
+		c.Assert(json.Unmarshal([]byte(test.json), &l), IsNil)
+		c.Check(l, Equals, test.expected)
+		c.Check(l.IsSymbolic(), Equals, test.symbolic)
+		b, err := json.Marshal(l)
+		c.Assert(err, IsNil)
+		c.Check(string(b), Equals, test.json)
+	}
+
+	var l LedgerIndex
+	c.Assert(json.Unmarshal([]byte(`"6917762"`), &l), IsNil)
+	c.Check(l.Sequence(), Equals, uint32(6917762))
+	c.Check(LedgerValidated.Keyword(), Equals, "validated")
+
+	const hash = "4109C6F2045FC7EFF4CDE8F9905D19C28820D86304080FF886B299F0206E42B5"
+	c.Assert(json.Unmarshal([]byte(`"`+hash+`"`), &l), IsNil)
+	c.Check(l.IsHash(), Equals, true)
+	c.Check(l.Hash().String(), Equals, hash)
+	b, err := json.Marshal(l)
+	c.Assert(err, IsNil)
+	c.Check(string(b), Equals, `"`+hash+`"`)
+
+	c.Check(json.Unmarshal([]byte(`"pending"`), &l), ErrorMatches, "Unknown ledger index: pending")
+	c.Check(json.Unmarshal([]byte(`"`+hash[:63]+`X"`), &l), ErrorMatches, "Unknown ledger index: .*")
+	c.Check(json.Unmarshal([]byte(`-1`), &l), NotNil)
+}
+
+func (s *LedgerIndexSuite) TestLedgerSelector(c *C) {
+	hash, err := data.NewHash256("4109C6F2045FC7EFF4CDE8F9905D19C28820D86304080FF886B299F0206E42B5")
+	c.Assert(err, IsNil)
+	for _, test := range []struct {
+		ledger   LedgerIndex
+		expected string
+	}{
+		{LedgerIndex{}, `{}`},
+		{LedgerValidated, `{"ledger_index":"validated"}`},
+		{NewLedgerIndex(6917762), `{"ledger_index":6917762}`},
+		{NewLedgerHash(*hash), `{"ledger_hash":"4109C6F2045FC7EFF4CDE8F9905D19C28820D86304080FF886B299F0206E42B5"}`},
+	} {
+		b, err := json.Marshal(test.ledger.Selector())
+		c.Assert(err, IsNil)
+		c.Check(string(b), Equals, test.expected, Commentf("%s", test.ledger))
+	}
+
+	cmd := &AccountLinesCommand{Command: newCommand("account_lines"), LedgerSelector: LedgerClosed.Selector()}
+	b, err := json.Marshal(cmd)
+	c.Assert(err, IsNil)
+	var fields map[string]interface{}
+	c.Assert(json.Unmarshal(b, &fields), IsNil)
+	c.Check(fields["ledger_index"], Equals, "closed")
+	c.Check(fields["command"], Equals, "account_lines")
+}
@@ -1,7 +1,9 @@
 package websockets
 
 import (
-	"github.com/dabankio/ripple/data"
+	"context"
+
+	"github.com/atticlab/ripple/data"
 )
 
 // https://ripple.com/build/rippled-apis/#path-find
@@ -28,15 +30,26 @@ type PathFindCreateCommand struct {
 	SendMax            *data.Amount      `json:"send_max,omitempty"`
 	SourceCurrencies   *[]SourceCurrency `json:"source_currencies,omitempty"`
 
-	// All commands have Result in their struct?
-	Result *PathFindCreateResult
+	Result *PathFindCreateResult `json:"result,omitempty"`
+}
+
+// The status and close subcommands of path_find
+type PathFindCommand struct {
+	*Command
+	Subcommand string                `json:"subcommand"`
+	Result     *PathFindCreateResult `json:"result,omitempty"`
 }
 
 type SourceCurrency struct {
 	Currency string `json:"currency"`
 }
 
-func (r *Remote) PathFindCreate(src, dest data.Account, amt data.Amount, sendMax *data.Amount, sourceCurrencies *[]SourceCurrency) (*PathFindCreateResult, error) {
+// Synchronously creates a path_find request, whose initial alternatives are
+// returned. rippled then sends updated alternatives as ledgers close, which
+// are received over the Incoming channel as *PathFindCreateResult until
+// PathFindClose is called. A connection has at most one path_find request,
+// so creating another replaces it.
+func (r *Remote) PathFindCreate(ctx context.Context, src, dest data.Account, amt data.Amount, sendMax *data.Amount, sourceCurrencies *[]SourceCurrency) (*PathFindCreateResult, error) {
 	cmd := &PathFindCreateCommand{
 		Command:            newCommand("path_find"),
 		Subcommand:         "create",
@@ -46,10 +59,8 @@ func (r *Remote) PathFindCreate(src, dest data.Account, amt data.Amount, sendMax
 		SendMax:            sendMax,
 		SourceCurrencies:   sourceCurrencies,
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
@@ -93,14 +104,38 @@ func (r *Remote) PathFindCreate(src, dest data.Account, amt data.Amount, sendMax
 */
 
 type PathFindAlternative struct {
-	// TODO paths_computed
-	SourceAmount data.Amount `json:"source_amount"`
+	SourceAmount  data.Amount  `json:"source_amount"`
+	PathsComputed data.PathSet `json:"paths_computed,omitempty"`
 }
 
 type PathFindCreateResult struct {
-	SourceAccount      data.Account `json:"source_account"`
-	DestinationAccount data.Account `json:"destination_account"`
-	DestinationAmount  data.Amount  `json:"destination_amount"`
-	// TODO
-	Alternatives []PathFindAlternative
+	SourceAccount      data.Account          `json:"source_account"`
+	DestinationAccount data.Account          `json:"destination_account"`
+	DestinationAmount  data.Amount           `json:"destination_amount"`
+	Alternatives       []PathFindAlternative `json:"alternatives"`
+	// False until rippled has searched all the paths it will consider
+	FullReply bool `json:"full_reply"`
+	// Set in the response to PathFindClose
+	Closed bool `json:"closed,omitempty"`
+}
+
+func (r *Remote) pathFind(ctx context.Context, subcommand string) (*PathFindCreateResult, error) {
+	cmd := &PathFindCommand{
+		Command:    newCommand("path_find"),
+		Subcommand: subcommand,
+	}
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return cmd.Result, nil
+}
+
+// Synchronously gets the latest alternatives of the path_find request
+func (r *Remote) PathFindStatus(ctx context.Context) (*PathFindCreateResult, error) {
+	return r.pathFind(ctx, "status")
+}
+
+// Synchronously closes the path_find request, stopping its updates
+func (r *Remote) PathFindClose(ctx context.Context) (*PathFindCreateResult, error) {
+	return r.pathFind(ctx, "close")
 }
@@ -2,6 +2,7 @@ package websockets
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -34,6 +35,8 @@ type Remote struct {
 	Incoming chan interface{}
 	outgoing chan Syncer
 	ws       *websocket.Conn
+	closing  chan struct{} // Closed by Close()
+	done     chan struct{} // Closed once the connection is lost
 }
 
 // NewRemote returns a new remote session connected to the specified
@@ -56,6 +59,8 @@ func NewRemote(endpoint string) (*Remote, error) {
 		Incoming: make(chan interface{}, 1000),
 		outgoing: make(chan Syncer, 10),
 		ws:       ws,
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
 	}
 
 	go r.run()
@@ -66,7 +71,7 @@ func NewRemote(endpoint string) (*Remote, error) {
 // goroutines have been cleaned up.
 // Any commands that are pending a response will return with an error.
 func (r *Remote) Close() {
-	close(r.outgoing)
+	close(r.closing)
 
 	// Drain the Incoming channel and block until it is closed,
 	// indicating that this Remote is fully cleaned up.
@@ -76,7 +81,7 @@ func (r *Remote) Close() {
 
 // run spawns the read/write pumps and then runs until Close() is called.
 func (r *Remote) run() {
-	outbound := make(chan interface{})
+	outbound := make(chan []byte)
 	inbound := make(chan []byte)
 	pending := make(map[uint64]Syncer)
 
@@ -89,6 +94,9 @@ func (r *Remote) run() {
 			c.Fail("Connection Closed")
 		}
 
+		// Commands sent from now on fail without being queued
+		close(r.done)
+
 		// Drain the inbound channel and block until it is closed,
 		// indicating that the readPump has returned.
 		for _ = range inbound {
@@ -97,8 +105,12 @@ func (r *Remote) run() {
 
 	// Spawn read/write goroutines
 	go func() {
-		defer r.ws.Close()
 		r.writePump(outbound)
+		r.ws.Close()
+		// Discard anything sent after a write error, until the run loop
+		// sees the connection close
+		for _ = range outbound {
+		}
 	}()
 	go func() {
 		defer close(inbound)
@@ -109,11 +121,19 @@ func (r *Remote) run() {
 	var response Command
 	for {
 		select {
-		case command, ok := <-r.outgoing:
-			if !ok {
-				return
+		case <-r.closing:
+			return
+
+		case command := <-r.outgoing:
+			// Marshalled here rather than in the writePump so that the
+			// command is only ever touched by this goroutine
+			b, err := json.Marshal(command)
+			if err != nil {
+				glog.Errorln(err)
+				command.Fail(err.Error())
+				continue
 			}
-			outbound <- command
+			outbound <- b
 			id := reflect.ValueOf(command).Elem().FieldByName("Id").Uint()
 			pending[id] = command
 
@@ -128,14 +148,13 @@ func (r *Remote) run() {
 				continue
 			}
 			// Stream message
-			factory, ok := streamMessageFactory[response.Type]
-			if ok {
-				cmd := factory()
-				if err := json.Unmarshal(in, &cmd); err != nil {
+			if response.Type != "" && response.Type != "response" {
+				msg, err := DecodeStreamMessage(in)
+				if err != nil {
 					glog.Errorln(err.Error(), string(in))
 					continue
 				}
-				r.Incoming <- cmd
+				r.Incoming <- msg
 				continue
 			}
 
@@ -155,32 +174,96 @@ func (r *Remote) run() {
 	}
 }
 
+// call queues cmd and waits for its response, returning the error of ctx
+// if it is done first
+func (r *Remote) call(ctx context.Context, cmd Syncer) error {
+	if err := r.send(ctx, cmd); err != nil {
+		return err
+	}
+	return r.wait(ctx, cmd.command())
+}
+
+// send queues cmd, failing if the connection has been lost
+func (r *Remote) send(ctx context.Context, cmd Syncer) error {
+	select {
+	case <-r.done:
+		return connectionClosed()
+	default:
+	}
+	select {
+	case r.outgoing <- cmd:
+		return nil
+	case <-r.done:
+		return connectionClosed()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wait blocks until the response to c arrives, returning its error
+func (r *Remote) wait(ctx context.Context, c *Command) error {
+	if err := r.ready(ctx, c); err != nil {
+		return err
+	}
+	if c.CommandError != nil {
+		return c.CommandError
+	}
+	return nil
+}
+
+// ready blocks until c is answered or failed. A command still queued when
+// the connection is lost never is.
+func (r *Remote) ready(ctx context.Context, c *Command) error {
+	select {
+	case <-c.Ready:
+		return nil
+	case <-r.done:
+		// Commands are answered or failed before done is closed
+		select {
+		case <-c.Ready:
+			return nil
+		default:
+			return connectionClosed()
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func connectionClosed() *CommandError {
+	return &CommandError{
+		Name:    "Client Error",
+		Code:    -1,
+		Message: "Connection Closed",
+	}
+}
+
 // Synchronously get a single transaction
-func (r *Remote) Tx(hash data.Hash256) (*TxResult, error) {
+func (r *Remote) Tx(ctx context.Context, hash data.Hash256) (*TxResult, error) {
 	cmd := &TxCommand{
 		Command:     newCommand("tx"),
 		Transaction: hash,
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
 
-func (r *Remote) accountTx(account data.Account, c chan *data.TransactionWithMetaData, pageSize int, minLedger, maxLedger int64) {
+func (r *Remote) accountTx(ctx context.Context, account data.Account, c chan *data.TransactionWithMetaData, pageSize int, minLedger, maxLedger int64) {
 	defer close(c)
 	cmd := newAccountTxCommand(account, pageSize, nil, minLedger, maxLedger)
 	for ; ; cmd = newAccountTxCommand(account, pageSize, cmd.Result.Marker, minLedger, maxLedger) {
-		r.outgoing <- cmd
-		<-cmd.Ready
-		if cmd.CommandError != nil {
-			glog.Errorln(cmd.Error())
+		if err := r.call(ctx, cmd); err != nil {
+			glog.Errorln(err.Error())
 			return
 		}
 		for _, tx := range cmd.Result.Transactions {
-			c <- tx
+			select {
+			case c <- tx:
+			case <-ctx.Done():
+				return
+			}
 		}
 		if cmd.Result.Marker == nil {
 			return
@@ -196,14 +279,15 @@ func (r *Remote) accountTx(account data.Account, c chan *data.TransactionWithMet
 //
 // Use minLedger -1 for the earliest ledger available.
 // Use maxLedger -1 for the most recent validated ledger.
-func (r *Remote) AccountTx(account data.Account, pageSize int, minLedger, maxLedger int64) chan *data.TransactionWithMetaData {
+// The channel is closed early if ctx is done.
+func (r *Remote) AccountTx(ctx context.Context, account data.Account, pageSize int, minLedger, maxLedger int64) chan *data.TransactionWithMetaData {
 	c := make(chan *data.TransactionWithMetaData)
-	go r.accountTx(account, c, pageSize, minLedger, maxLedger)
+	go r.accountTx(ctx, account, c, pageSize, minLedger, maxLedger)
 	return c
 }
 
 // Synchronously submit a single transaction
-func (r *Remote) Submit(tx data.Transaction) (*SubmitResult, error) {
+func (r *Remote) Submit(ctx context.Context, tx data.Transaction) (*SubmitResult, error) {
 	_, raw, err := data.Raw(tx)
 	if err != nil {
 		return nil, err
@@ -212,16 +296,14 @@ func (r *Remote) Submit(tx data.Transaction) (*SubmitResult, error) {
 		Command: newCommand("submit"),
 		TxBlob:  fmt.Sprintf("%X", raw),
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
 
 // Synchronously submit multiple transactions
-func (r *Remote) SubmitBatch(txs []data.Transaction) ([]*SubmitResult, error) {
+func (r *Remote) SubmitBatch(ctx context.Context, txs []data.Transaction) ([]*SubmitResult, error) {
 	commands := make([]*SubmitCommand, len(txs))
 	results := make([]*SubmitResult, len(txs))
 	for i := range txs {
@@ -233,39 +315,39 @@ func (r *Remote) SubmitBatch(txs []data.Transaction) ([]*SubmitResult, error) {
 			Command: newCommand("submit"),
 			TxBlob:  fmt.Sprintf("%X", raw),
 		}
-		r.outgoing <- cmd
+		if err := r.send(ctx, cmd); err != nil {
+			return nil, err
+		}
 		commands[i] = cmd
 	}
 	for i := range commands {
-		<-commands[i].Ready
+		if err := r.ready(ctx, commands[i].Command); err != nil {
+			return nil, err
+		}
 		results[i] = commands[i].Result
 	}
 	return results, nil
 }
 
 // Synchronously gets ledger entries
-func (r *Remote) LedgerData(ledger interface{}, marker *data.Hash256) (*LedgerDataResult, error) {
+func (r *Remote) LedgerData(ctx context.Context, ledger LedgerIndex, marker *data.Hash256) (*LedgerDataResult, error) {
 	cmd := &LedgerDataCommand{
-		Command: newCommand("ledger_data"),
-		Ledger:  ledger,
-		Marker:  marker,
+		Command:        newCommand("ledger_data"),
+		LedgerSelector: ledger.Selector(),
+		Marker:         marker,
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
 
-func (r *Remote) streamLedgerData(ledger interface{}, c chan data.LedgerEntrySlice) {
+func (r *Remote) streamLedgerData(ctx context.Context, ledger LedgerIndex, c chan data.LedgerEntrySlice) {
 	defer close(c)
 	cmd := newBinaryLedgerDataCommand(ledger, nil)
 	for ; ; cmd = newBinaryLedgerDataCommand(ledger, cmd.Result.Marker) {
-		r.outgoing <- cmd
-		<-cmd.Ready
-		if cmd.CommandError != nil {
-			glog.Errorln(cmd.Error())
+		if err := r.call(ctx, cmd); err != nil {
+			glog.Errorln(err.Error())
 			return
 		}
 		les := make(data.LedgerEntrySlice, len(cmd.Result.State))
@@ -283,65 +365,64 @@ func (r *Remote) streamLedgerData(ledger interface{}, c chan data.LedgerEntrySli
 				continue
 			}
 		}
-		c <- les
+		select {
+		case c <- les:
+		case <-ctx.Done():
+			return
+		}
 		if cmd.Result.Marker == nil {
 			return
 		}
 	}
 }
 
-// Asynchronously retrieve all data for a ledger using the binary form
-func (r *Remote) StreamLedgerData(ledger interface{}) chan data.LedgerEntrySlice {
+// Asynchronously retrieve all data for a ledger using the binary form.
+// The channel is closed early if ctx is done.
+func (r *Remote) StreamLedgerData(ctx context.Context, ledger LedgerIndex) chan data.LedgerEntrySlice {
 	c := make(chan data.LedgerEntrySlice)
-	go r.streamLedgerData(ledger, c)
+	go r.streamLedgerData(ctx, ledger, c)
 	return c
 }
 
 // Synchronously gets the last closed ledger
-func (r *Remote) ClosedLedger() (*LedgerClosedResult, error) {
+func (r *Remote) ClosedLedger(ctx context.Context) (*LedgerClosedResult, error) {
 	cmd := &LedgerClosedCommand{
 		Command: newCommand("ledger_closed"),
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
 
 // Synchronously gets a single ledger
-func (r *Remote) Ledger(ledger interface{}, transactions bool) (*LedgerResult, error) {
+func (r *Remote) Ledger(ctx context.Context, ledger LedgerIndex, transactions bool) (*LedgerResult, error) {
 	cmd := &LedgerCommand{
-		Command:      newCommand("ledger"),
-		LedgerIndex:  ledger,
-		Transactions: transactions,
-		Expand:       true,
+		Command:        newCommand("ledger"),
+		LedgerSelector: ledger.Selector(),
+		Transactions:   transactions,
+		Expand:         true,
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	cmd.Result.Ledger.Transactions.Sort()
 	return cmd.Result, nil
 }
 
-func (r *Remote) LedgerHeader(ledger interface{}) (*LedgerHeaderResult, error) {
+func (r *Remote) LedgerHeader(ctx context.Context, ledger LedgerIndex) (*LedgerHeaderResult, error) {
 	cmd := &LedgerHeaderCommand{
-		Command: newCommand("ledger_header"),
-		Ledger:  ledger,
+		Command:        newCommand("ledger_header"),
+		LedgerSelector: ledger.Selector(),
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
 
 // Synchronously requests paths
-func (r *Remote) RipplePathFind(src, dest data.Account, amount data.Amount, srcCurr *[]data.Currency) (*RipplePathFindResult, error) {
+func (r *Remote) RipplePathFind(ctx context.Context, src, dest data.Account, amount data.Amount, srcCurr *[]data.Currency) (*RipplePathFindResult, error) {
 	cmd := &RipplePathFindCommand{
 		Command:       newCommand("ripple_path_find"),
 		SrcAccount:    src,
@@ -349,52 +430,47 @@ func (r *Remote) RipplePathFind(src, dest data.Account, amount data.Amount, srcC
 		DestAccount:   dest,
 		DestAmount:    amount,
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
 
 // Synchronously requests account info
-func (r *Remote) AccountInfo(a data.Account) (*AccountInfoResult, error) {
+func (r *Remote) AccountInfo(ctx context.Context, a data.Account) (*AccountInfoResult, error) {
 	cmd := &AccountInfoCommand{
 		Command: newCommand("account_info"),
 		Account: a,
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
 
 // Synchronously requests account line info
-func (r *Remote) AccountLines(account data.Account, ledgerIndex interface{}) (*AccountLinesResult, error) {
+func (r *Remote) AccountLines(ctx context.Context, account data.Account, ledger LedgerIndex) (*AccountLinesResult, error) {
 	var (
 		lines  data.AccountLineSlice
 		marker *data.Hash256
 	)
 	for {
 		cmd := &AccountLinesCommand{
-			Command:     newCommand("account_lines"),
-			Account:     account,
-			Limit:       400,
-			Marker:      marker,
-			LedgerIndex: ledgerIndex,
+			Command:        newCommand("account_lines"),
+			Account:        account,
+			Limit:          400,
+			Marker:         marker,
+			LedgerSelector: ledger.Selector(),
+		}
+		if err := r.call(ctx, cmd); err != nil {
+			return nil, err
 		}
-		r.outgoing <- cmd
-		<-cmd.Ready
 		switch {
-		case cmd.CommandError != nil:
-			return nil, cmd.CommandError
 		case cmd.Result.Marker != nil:
 			lines = append(lines, cmd.Result.Lines...)
 			marker = cmd.Result.Marker
 			if cmd.Result.LedgerSequence != nil {
-				ledgerIndex = *cmd.Result.LedgerSequence
+				ledger = NewLedgerIndex(*cmd.Result.LedgerSequence)
 			}
 		default:
 			cmd.Result.Lines = append(lines, cmd.Result.Lines...)
@@ -405,29 +481,28 @@ func (r *Remote) AccountLines(account data.Account, ledgerIndex interface{}) (*A
 }
 
 // Synchronously requests account offers
-func (r *Remote) AccountOffers(account data.Account, ledgerIndex interface{}) (*AccountOffersResult, error) {
+func (r *Remote) AccountOffers(ctx context.Context, account data.Account, ledger LedgerIndex) (*AccountOffersResult, error) {
 	var (
 		offers data.AccountOfferSlice
 		marker *data.Hash256
 	)
 	for {
 		cmd := &AccountOffersCommand{
-			Command:     newCommand("account_offers"),
-			Account:     account,
-			Limit:       400,
-			Marker:      marker,
-			LedgerIndex: ledgerIndex,
+			Command:        newCommand("account_offers"),
+			Account:        account,
+			Limit:          400,
+			Marker:         marker,
+			LedgerSelector: ledger.Selector(),
+		}
+		if err := r.call(ctx, cmd); err != nil {
+			return nil, err
 		}
-		r.outgoing <- cmd
-		<-cmd.Ready
 		switch {
-		case cmd.CommandError != nil:
-			return nil, cmd.CommandError
 		case cmd.Result.Marker != nil:
 			offers = append(offers, cmd.Result.Offers...)
 			marker = cmd.Result.Marker
 			if cmd.Result.LedgerSequence != nil {
-				ledgerIndex = *cmd.Result.LedgerSequence
+				ledger = NewLedgerIndex(*cmd.Result.LedgerSequence)
 			}
 		default:
 			cmd.Result.Offers = append(offers, cmd.Result.Offers...)
@@ -437,26 +512,24 @@ func (r *Remote) AccountOffers(account data.Account, ledgerIndex interface{}) (*
 	}
 }
 
-func (r *Remote) BookOffers(taker data.Account, ledgerIndex interface{}, pays, gets data.Asset) (*BookOffersResult, error) {
+func (r *Remote) BookOffers(ctx context.Context, taker data.Account, ledger LedgerIndex, pays, gets data.Asset) (*BookOffersResult, error) {
 	cmd := &BookOffersCommand{
-		Command:     newCommand("book_offers"),
-		LedgerIndex: ledgerIndex,
-		Taker:       taker,
-		TakerPays:   pays,
-		TakerGets:   gets,
-		Limit:       5000, // Marker not implemented....
-	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+		Command:        newCommand("book_offers"),
+		LedgerSelector: ledger.Selector(),
+		Taker:          taker,
+		TakerPays:      pays,
+		TakerGets:      gets,
+		Limit:          5000, // Marker not implemented....
+	}
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
 
 // Synchronously subscribe to streams and receive a confirmation message
 // Streams are recived asynchronously over the Incoming channel
-func (r *Remote) Subscribe(ledger, transactions, transactionsProposed, server bool) (*SubscribeResult, error) {
+func (r *Remote) Subscribe(ctx context.Context, ledger, transactions, transactionsProposed, server bool) (*SubscribeResult, error) {
 	streams := []string{}
 	if ledger {
 		streams = append(streams, "ledger")
@@ -474,10 +547,8 @@ func (r *Remote) Subscribe(ledger, transactions, transactionsProposed, server bo
 		Command: newCommand("subscribe"),
 		Streams: streams,
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 
 	if ledger && cmd.Result.LedgerStreamMsg == nil {
@@ -496,28 +567,62 @@ type OrderBookSubscription struct {
 	Both      bool       `json:"both"`
 }
 
-func (r *Remote) SubscribeOrderBooks(books []OrderBookSubscription) (*SubscribeResult, error) {
+func (r *Remote) SubscribeOrderBooks(ctx context.Context, books []OrderBookSubscription) (*SubscribeResult, error) {
 	cmd := &SubscribeCommand{
 		Command: newCommand("subscribe"),
 		Streams: []string{"ledger", "server"},
 		Books:   books,
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return cmd.Result, nil
+}
+
+// Synchronously subscribe to the validated transactions affecting accounts,
+// which are received over the Incoming channel as *TransactionStreamMsg
+func (r *Remote) SubscribeAccounts(ctx context.Context, accounts []data.Account) (*SubscribeResult, error) {
+	cmd := &SubscribeCommand{
+		Command:  newCommand("subscribe"),
+		Streams:  []string{},
+		Accounts: accounts,
+	}
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
 
-func (r *Remote) Fee() (*FeeResult, error) {
+// Synchronously subscribe to the book changes stream, which is received over
+// the Incoming channel as *BookChangesStreamMsg once per validated ledger
+func (r *Remote) SubscribeBookChanges(ctx context.Context) (*SubscribeResult, error) {
+	cmd := &SubscribeCommand{
+		Command: newCommand("subscribe"),
+		Streams: []string{"book_changes"},
+	}
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return cmd.Result, nil
+}
+
+func (r *Remote) Fee(ctx context.Context) (*FeeResult, error) {
 	cmd := &FeeCommand{
 		Command: newCommand("fee"),
 	}
-	r.outgoing <- cmd
-	<-cmd.Ready
-	if cmd.CommandError != nil {
-		return nil, cmd.CommandError
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return cmd.Result, nil
+}
+
+// Synchronously requests the status of the server
+func (r *Remote) ServerInfo(ctx context.Context) (*ServerInfoResult, error) {
+	cmd := &ServerInfoCommand{
+		Command: newCommand("server_info"),
+	}
+	if err := r.call(ctx, cmd); err != nil {
+		return nil, err
 	}
 	return cmd.Result, nil
 }
@@ -542,7 +647,7 @@ func (r *Remote) readPump(inbound chan<- []byte) {
 // Consumes from the outbound channel and sends them over the websocket.
 // Also sends PING messages at the specified interval.
 // Returns when outbound channel is closed, or an error is encountered.
-func (r *Remote) writePump(outbound <-chan interface{}) {
+func (r *Remote) writePump(outbound <-chan []byte) {
 	ticker := time.NewTicker(pingPeriod)
 	defer ticker.Stop()
 
@@ -550,19 +655,12 @@ func (r *Remote) writePump(outbound <-chan interface{}) {
 		select {
 
 		// An outbound message is available to send
-		case message, ok := <-outbound:
+		case b, ok := <-outbound:
 			if !ok {
 				r.ws.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
 
-			b, err := json.Marshal(message)
-			if err != nil {
-				// Outbound message cannot be JSON serialized (log it and continue)
-				glog.Errorln(err)
-				continue
-			}
-
 			glog.V(2).Infoln(dump(b))
 			if err := r.ws.WriteMessage(websocket.TextMessage, b); err != nil {
 				glog.Errorln(err)
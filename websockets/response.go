@@ -0,0 +1,74 @@
+package websockets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// RippleError is the error returned by rippled when a request fails.
+// The same fields are used by both the WebSocket and JSON-RPC APIs.
+type RippleError struct {
+	Name    string `json:"error"`
+	Code    int    `json:"error_code"`
+	Message string `json:"error_message"`
+}
+
+func (e *RippleError) Error() string {
+	return fmt.Sprintf("%s %d %s", e.Name, e.Code, e.Message)
+}
+
+// errorName returns the name of the error reported by rippled, such as
+// txnNotFound, from either transport
+func errorName(err error) (string, bool) {
+	switch e := err.(type) {
+	case *CommandError:
+		return e.Name, true
+	case *RippleError:
+		return e.Name, true
+	default:
+		return "", false
+	}
+}
+
+type responseStatus struct {
+	RippleError
+	Status string `json:"status"`
+}
+
+func (s *responseStatus) failed() bool {
+	return s.Status == "error" || s.Name != ""
+}
+
+type responseEnvelope struct {
+	responseStatus
+	Type   string          `json:"type"`
+	Result json.RawMessage `json:"result"`
+}
+
+// DecodeResponse unmarshals the result of a rippled response into v.
+// Accepts the WebSocket form {"type":"response","status":"success","result":{...}},
+// the JSON-RPC form {"result":{...,"status":"success"}} or a bare result.
+// If rippled reports an error, a *RippleError is returned and v is untouched.
+func DecodeResponse(b []byte, v interface{}) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) == 0 || trimmed[0] != '{' {
+		return json.Unmarshal(b, v)
+	}
+	var envelope responseEnvelope
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return err
+	}
+	if envelope.failed() {
+		return &envelope.RippleError
+	}
+	if envelope.Result == nil {
+		// No envelope
+		return json.Unmarshal(b, v)
+	}
+	// JSON-RPC reports the status inside the result
+	var inner responseStatus
+	if err := json.Unmarshal(envelope.Result, &inner); err == nil && inner.failed() {
+		return &inner.RippleError
+	}
+	return json.Unmarshal(envelope.Result, v)
+}
@@ -0,0 +1,73 @@
+package websockets
+
+import (
+	"io/ioutil"
+
+	"github.com/atticlab/ripple/data"
+	. "gopkg.in/check.v1"
+)
+
+type ResponseSuite struct{}
+
+var _ = Suite(&ResponseSuite{})
+
+func decodeResponseFile(c *C, v interface{}, path string) error {
+	b, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+	return DecodeResponse(b, v)
+}
+
+func (s *ResponseSuite) TestDecodeWebSocketResponse(c *C) {
+	var result AccountInfoResult
+	c.Assert(decodeResponseFile(c, &result, "testdata/account_info.json"), IsNil)
+	c.Assert(result.LedgerSequence, Equals, uint32(7636529))
+	c.Assert(result.AccountData.LedgerEntryType, Equals, data.ACCOUNT_ROOT)
+	c.Assert(*result.AccountData.Sequence, Equals, uint32(546))
+}
+
+func (s *ResponseSuite) TestDecodeRPCResponse(c *C) {
+	var result AccountInfoResult
+	c.Assert(decodeResponseFile(c, &result, "testdata/account_info_rpc.json"), IsNil)
+	c.Assert(result.LedgerSequence, Equals, uint32(7636529))
+	c.Assert(result.AccountData.Account.String(), Equals, "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(result.AccountData.Balance.String(), Equals, "10321199.422233")
+}
+
+func (s *ResponseSuite) TestDecodeErrorResponse(c *C) {
+	for _, path := range []string{"testdata/account_info_error.json", "testdata/account_info_rpc_error.json"} {
+		var result AccountInfoResult
+		err := decodeResponseFile(c, &result, path)
+		c.Assert(err, NotNil, Commentf(path))
+		rippleErr, ok := err.(*RippleError)
+		c.Assert(ok, Equals, true, Commentf(path))
+		c.Assert(rippleErr.Name, Equals, "actNotFound")
+		c.Assert(rippleErr.Code, Equals, 19)
+		c.Assert(rippleErr.Message, Equals, "Account not found.")
+		c.Assert(result.AccountData.Account, IsNil)
+	}
+}
+
+func (s *ResponseSuite) TestDecodeRawResult(c *C) {
+	var result LedgerClosedResult
+	raw := `{"ledger_hash":"0C5C5B39EA40D40ACA6EB47E50B2B85FD516D1A2BA67BA3E050349D3EF3632A4","ledger_index":6917762}`
+	c.Assert(DecodeResponse([]byte(raw), &result), IsNil)
+	c.Assert(result.LedgerIndex, Equals, uint32(6917762))
+	c.Assert(result.LedgerHash.String(), Equals, "0C5C5B39EA40D40ACA6EB47E50B2B85FD516D1A2BA67BA3E050349D3EF3632A4")
+
+	var hashes []data.Hash256
+	c.Assert(DecodeResponse([]byte(`["0C5C5B39EA40D40ACA6EB47E50B2B85FD516D1A2BA67BA3E050349D3EF3632A4"]`), &hashes), IsNil)
+	c.Assert(hashes, HasLen, 1)
+}
+
+func (s *ResponseSuite) TestDecodeServerInfo(c *C) {
+	var result ServerInfoResult
+	c.Assert(decodeResponseFile(c, &result, "testdata/server_info.json"), IsNil)
+	c.Assert(result.Info.ServerState, Equals, "full")
+	c.Assert(result.Info.CompleteLedgers, Equals, "32570-82521761")
+	c.Assert(result.Info.Peers, Equals, uint32(21))
+	c.Assert(result.Info.ValidatedLedger, NotNil)
+	c.Assert(result.Info.ValidatedLedger.LedgerSequence, Equals, uint32(82521761))
+	c.Assert(result.Info.ValidatedLedger.BaseFeeXRP, Equals, 0.00001)
+	c.Assert(result.Info.ValidatedLedger.ReserveBaseXRP, Equals, float64(10))
+	c.Assert(result.Info.ValidatedLedger.Hash.String(), Equals, "5F2B2C1E6F8D4F5B51D2EC1E0F6E2E4A8A2D1D3C5B6A7F8E9D0C1B2A3F4E5D6C")
+}
@@ -0,0 +1,254 @@
+package websockets
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/atticlab/ripple/data"
+	"github.com/golang/glog"
+)
+
+var (
+	// Delay before the first reconnection attempt, doubled after each
+	// failed attempt up to maxReconnectDelay
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+// LedgerGapMsg is delivered on the Incoming channel of a Session when
+// ledgers were closed without a ledgerClosed message being received,
+// usually while reconnecting. Start and End are inclusive.
+type LedgerGapMsg struct {
+	Start uint32
+	End   uint32
+}
+
+func (*LedgerGapMsg) StreamType() string { return "ledgerGap" }
+
+// A subscription replayed by a Session after reconnecting
+type subscription struct {
+	Streams  []string
+	Books    []OrderBookSubscription
+	Accounts []data.Account
+}
+
+func (s *subscription) command() *SubscribeCommand {
+	return &SubscribeCommand{
+		Command:  newCommand("subscribe"),
+		Streams:  s.Streams,
+		Books:    s.Books,
+		Accounts: s.Accounts,
+	}
+}
+
+// Session is a Remote which reconnects with exponential backoff when its
+// connection is dropped and replays its subscriptions. Stream messages of
+// all connections are delivered on Incoming, with a *LedgerGapMsg for any
+// ledgers missed in between so that they can be backfilled.
+// To close the session, use Close().
+type Session struct {
+	Incoming chan interface{}
+
+	endpoint      string
+	mu            sync.Mutex
+	remote        *Remote
+	subscriptions []*subscription
+	lastLedger    uint32
+	// Done when the session is closing
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewSession connects to the specified server endpoint URI, returning an
+// error if the first connection fails.
+func NewSession(endpoint string) (*Session, error) {
+	remote, err := NewRemote(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{
+		Incoming: make(chan interface{}, 1000),
+		endpoint: endpoint,
+		remote:   remote,
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	go s.run()
+	return s, nil
+}
+
+// Remote returns the current connection for synchronous commands, which
+// fail with an error if it has been dropped
+func (s *Session) Remote() *Remote {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.remote
+}
+
+// Subscribe subscribes to streams, order books and accounts, which are
+// subscribed to again whenever the session reconnects
+func (s *Session) Subscribe(ctx context.Context, streams []string, books []OrderBookSubscription, accounts []data.Account) (*SubscribeResult, error) {
+	sub := &subscription{
+		Streams:  streams,
+		Books:    books,
+		Accounts: accounts,
+	}
+	// Record it first so that it is replayed if the connection drops
+	// before the result arrives
+	s.mu.Lock()
+	remote := s.remote
+	s.subscriptions = append(s.subscriptions, sub)
+	s.mu.Unlock()
+
+	cmd := sub.command()
+	if err := remote.call(ctx, cmd); err != nil {
+		if e, ok := err.(*CommandError); !ok || e.Code != -1 {
+			// Cancelled, or rejected by the server rather than lost
+			// with the connection
+			s.forget(sub)
+		}
+		return nil, err
+	}
+	// Ledgers missed before this subscription are of no interest to it
+	s.observe(cmd.Result)
+	return cmd.Result, nil
+}
+
+func (s *Session) forget(sub *subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.subscriptions {
+		if s.subscriptions[i] == sub {
+			s.subscriptions = append(s.subscriptions[:i], s.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Close shuts down the Session and blocks until all internal
+// goroutines have been cleaned up.
+func (s *Session) Close() {
+	s.cancel()
+	for _ = range s.Incoming {
+	}
+}
+
+// run forwards stream messages and reconnects until Close() is called.
+func (s *Session) run() {
+	defer close(s.Incoming)
+	remote := s.Remote()
+	for {
+		if !s.forward(remote) {
+			remote.Close()
+			return
+		}
+		remote.Close()
+		glog.Errorln("Connection lost:", s.endpoint)
+		if remote = s.reconnect(); remote == nil {
+			return
+		}
+	}
+}
+
+// forward delivers the messages of remote until its connection is dropped,
+// returning false if the session is closing
+func (s *Session) forward(remote *Remote) bool {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return false
+		case msg, ok := <-remote.Incoming:
+			if !ok {
+				return true
+			}
+			if ledger, ok := msg.(*LedgerStreamMsg); ok {
+				if gap := s.advance(ledger.LedgerSequence); gap != nil {
+					if !s.deliver(gap) {
+						return false
+					}
+				}
+			}
+			if !s.deliver(msg) {
+				return false
+			}
+		}
+	}
+}
+
+func (s *Session) deliver(msg interface{}) bool {
+	select {
+	case s.Incoming <- msg:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// advance records the latest closed ledger, returning the ledgers skipped
+// since the previous one
+func (s *Session) advance(ledger uint32) *LedgerGapMsg {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last := s.lastLedger
+	if ledger <= last {
+		return nil
+	}
+	s.lastLedger = ledger
+	if last == 0 || ledger == last+1 {
+		return nil
+	}
+	return &LedgerGapMsg{Start: last + 1, End: ledger - 1}
+}
+
+// The ledger in a subscribe result is closed but never streamed
+func (s *Session) observe(result *SubscribeResult) *LedgerGapMsg {
+	if result == nil || result.LedgerStreamMsg == nil {
+		return nil
+	}
+	return s.advance(result.LedgerSequence)
+}
+
+// reconnect dials until a connection is made and its subscriptions are
+// replayed, returning nil if the session is closing
+func (s *Session) reconnect() *Remote {
+	for delay := minReconnectDelay; ; delay *= 2 {
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+		select {
+		case <-s.ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		remote, err := NewRemote(s.endpoint)
+		if err != nil {
+			glog.Errorln("Reconnect failed:", err)
+			continue
+		}
+		if err := s.resubscribe(remote); err != nil {
+			glog.Errorln("Resubscribe failed:", err)
+			remote.Close()
+			continue
+		}
+		glog.Infoln("Reconnected:", s.endpoint)
+		return remote
+	}
+}
+
+func (s *Session) resubscribe(remote *Remote) error {
+	s.mu.Lock()
+	s.remote = remote
+	subscriptions := append([]*subscription(nil), s.subscriptions...)
+	s.mu.Unlock()
+
+	for _, sub := range subscriptions {
+		cmd := sub.command()
+		if err := remote.call(s.ctx, cmd); err != nil {
+			return err
+		}
+		if gap := s.observe(cmd.Result); gap != nil && !s.deliver(gap) {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package websockets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "gopkg.in/check.v1"
+)
+
+type SessionSuite struct{}
+
+var _ = Suite(&SessionSuite{})
+
+const ledgerHash = "21EB30937A47EA6B71B63183806FFE9308CCB786137AA00FFB32A7094C6426FA"
+
+// serveLedgers answers a subscribe request on the nth connection with the
+// first ledger and then streams the remaining ledgers before hanging up
+func serveLedgers(c *C, connections [][]int) *httptest.Server {
+	var mu sync.Mutex
+	var n int
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		ledgers := connections[n]
+		n++
+		last := n == len(connections)
+		mu.Unlock()
+		ws, err := upgrader.Upgrade(w, req, nil)
+		c.Assert(err, IsNil)
+		defer ws.Close()
+		var request map[string]interface{}
+		c.Assert(ws.ReadJSON(&request), IsNil)
+		c.Check(request["command"], Equals, "subscribe")
+		c.Assert(ws.WriteJSON(map[string]interface{}{
+			"id":     request["id"],
+			"type":   "response",
+			"status": "success",
+			"result": map[string]interface{}{"ledger_index": ledgers[0], "ledger_hash": ledgerHash},
+		}), IsNil)
+		for _, ledger := range ledgers[1:] {
+			c.Assert(ws.WriteJSON(map[string]interface{}{
+				"type":         "ledgerClosed",
+				"ledger_index": ledger,
+				"ledger_hash":  ledgerHash,
+			}), IsNil)
+		}
+		if last {
+			// Keep the last connection open until the client closes it
+			ws.ReadMessage()
+		}
+	}))
+}
+
+func (s *SessionSuite) TestReconnect(c *C) {
+	defer func(delay time.Duration) { minReconnectDelay = delay }(minReconnectDelay)
+	minReconnectDelay = 10 * time.Millisecond
+
+	server := serveLedgers(c, [][]int{{10, 11}, {14, 15}})
+	defer server.Close()
+	session, err := NewSession(strings.Replace(server.URL, "http", "ws", 1))
+	c.Assert(err, IsNil)
+	defer session.Close()
+	result, err := session.Subscribe(context.Background(), []string{"ledger"}, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(result.LedgerSequence, Equals, uint32(10))
+
+	var received []interface{}
+	for len(received) < 3 {
+		select {
+		case msg := <-session.Incoming:
+			received = append(received, msg)
+		case <-time.After(5 * time.Second):
+			c.Fatalf("Timed out after: %v", received)
+		}
+	}
+	c.Assert(received[0].(*LedgerStreamMsg).LedgerSequence, Equals, uint32(11))
+	c.Assert(received[1], DeepEquals, &LedgerGapMsg{Start: 12, End: 13})
+	c.Assert(received[2].(*LedgerStreamMsg).LedgerSequence, Equals, uint32(15))
+}
+
+func (s *SessionSuite) TestAdvance(c *C) {
+	session := &Session{}
+	c.Check(session.advance(100), IsNil)
+	c.Check(session.advance(101), IsNil)
+	c.Check(session.advance(101), IsNil)
+	c.Check(session.advance(99), IsNil)
+	c.Check(session.advance(105), DeepEquals, &LedgerGapMsg{Start: 102, End: 104})
+	c.Check(session.lastLedger, Equals, uint32(105))
+}
+
+func (s *SessionSuite) TestCancel(c *C) {
+	// Accepts the connection but never responds
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ws, err := upgrader.Upgrade(w, req, nil)
+		c.Assert(err, IsNil)
+		defer ws.Close()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	remote, err := NewRemote(strings.Replace(server.URL, "http", "ws", 1))
+	c.Assert(err, IsNil)
+	defer remote.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = remote.ServerInfo(ctx)
+	c.Assert(err, Equals, context.DeadlineExceeded)
+}
+
+func (s *SessionSuite) TestReconnectWindow(c *C) {
+	defer func(delay time.Duration) { minReconnectDelay = delay }(minReconnectDelay)
+	minReconnectDelay = time.Minute
+
+	// Hangs up on every connection
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ws, err := upgrader.Upgrade(w, req, nil)
+		c.Assert(err, IsNil)
+		ws.Close()
+	}))
+	defer server.Close()
+	session, err := NewSession(strings.Replace(server.URL, "http", "ws", 1))
+	c.Assert(err, IsNil)
+	defer session.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// Before and after the dropped connection is closed by the session
+	for i := 0; i < 2; i++ {
+		_, err = session.Remote().ServerInfo(ctx)
+		c.Assert(err, FitsTypeOf, &CommandError{})
+		c.Check(err.(*CommandError).Message, Equals, "Connection Closed")
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,182 @@
+package websockets
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atticlab/ripple/crypto"
+	"github.com/atticlab/ripple/data"
+)
+
+var (
+	// Ledgers after the last validated ledger in which a transaction
+	// submitted by SubmitAndWait without a LastLedgerSequence may be included
+	lastLedgerOffset uint32 = 20
+
+	// Interval between checks for the validation of a submitted transaction
+	submitPollInterval = time.Second
+)
+
+// ExpiredError is returned by SubmitAndWait when a transaction was not
+// included in any validated ledger up to its LastLedgerSequence, so can
+// never be. It is safe to sign and submit it again with a new
+// LastLedgerSequence, and the current Sequence if Result is tefPAST_SEQ.
+type ExpiredError struct {
+	Hash               data.Hash256
+	LastLedgerSequence uint32
+	Result             data.TransactionResult // Of the last submission
+}
+
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf("Transaction %s expired after ledger %d: %s", e.Hash, e.LastLedgerSequence, e.Result)
+}
+
+// SubmitAndWait signs tx with keyPair, submits it and waits until it is
+// included in a validated ledger, returning its final result and metadata.
+// A transaction without a LastLedgerSequence is given one shortly after the
+// last validated ledger, so that it provably expires if it is not included.
+// A nil keyPair submits tx as already signed, which requires a
+// LastLedgerSequence. The transaction is resubmitted while rippled reports
+// that it might yet apply, and an *ExpiredError is returned once the server
+// has validated every ledger up to LastLedgerSequence without it.
+func SubmitAndWait(ctx context.Context, client Client, tx data.Transaction, keyPair *crypto.KeyPair) (*TxResult, error) {
+	validated, err := validatedLedger(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	first := validated + 1
+	base := tx.GetBase()
+	if base.LastLedgerSequence == nil {
+		if keyPair == nil {
+			return nil, fmt.Errorf("A signed transaction requires a LastLedgerSequence")
+		}
+		last := first + lastLedgerOffset
+		base.LastLedgerSequence = &last
+	}
+	if keyPair != nil {
+		if err := data.Sign(tx, keyPair.Key, keyPair.Sequence); err != nil {
+			return nil, err
+		}
+	}
+	w := &submission{
+		client: client,
+		tx:     tx,
+		hash:   *tx.GetHash(),
+		first:  first,
+		last:   *base.LastLedgerSequence,
+	}
+	if err := w.submit(ctx); err != nil {
+		return nil, err
+	}
+	return w.wait(ctx)
+}
+
+func validatedLedger(ctx context.Context, client Client) (uint32, error) {
+	info, err := client.ServerInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if info.Info.ValidatedLedger == nil {
+		return 0, fmt.Errorf("Server has no validated ledger: %s", info.Info.ServerState)
+	}
+	return info.Info.ValidatedLedger.LedgerSequence, nil
+}
+
+// A transaction submitted by SubmitAndWait
+type submission struct {
+	client      Client
+	tx          data.Transaction
+	hash        data.Hash256
+	first, last uint32 // The ledgers which might include it
+	result      data.TransactionResult
+	resubmit    bool
+}
+
+func (w *submission) submit(ctx context.Context) error {
+	result, err := w.client.Submit(ctx, w.tx)
+	if err != nil {
+		return err
+	}
+	w.result = result.EngineResult
+	switch {
+	case w.result.Applied(), w.result.Queued():
+		// Applied to the open ledger, or held in the queue until the fee drops
+		w.resubmit = false
+	case w.result.String() == "tefPAST_SEQ", w.result.String() == "tefALREADY", w.result.String() == "tefMAX_LEDGER":
+		// Perhaps already included
+		w.resubmit = false
+	case w.result.IsRetryable():
+		w.resubmit = true
+	default:
+		return fmt.Errorf("Transaction %s failed: %s %s", w.hash, w.result, result.EngineResultMessage)
+	}
+	return nil
+}
+
+func (w *submission) wait(ctx context.Context) (*TxResult, error) {
+	for {
+		select {
+		case <-time.After(submitPollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		result, err := w.lookup(ctx)
+		if result != nil || err != nil {
+			return result, err
+		}
+		info, err := w.client.ServerInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		validated := info.Info.ValidatedLedger
+		if validated != nil && validated.LedgerSequence >= w.last && ledgersContain(info.Info.CompleteLedgers, w.first, w.last) {
+			// It may have been validated since the lookup
+			if result, err := w.lookup(ctx); result != nil || err != nil {
+				return result, err
+			}
+			return nil, &ExpiredError{Hash: w.hash, LastLedgerSequence: w.last, Result: w.result}
+		}
+		if w.resubmit {
+			if err := w.submit(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// lookup returns the transaction once it is validated
+func (w *submission) lookup(ctx context.Context) (*TxResult, error) {
+	result, err := w.client.Tx(ctx, w.hash)
+	if name, ok := errorName(err); ok && name == "txnNotFound" {
+		return nil, nil
+	}
+	if err != nil || !result.Validated {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ledgersContain is true when the complete_ledgers of server_info, such as
+// "32570-6959228,6959230-6959240", include all the ledgers from first to last
+func ledgersContain(complete string, first, last uint32) bool {
+	for _, r := range strings.Split(complete, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(r), "-", 2)
+		start, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			continue
+		}
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.ParseUint(bounds[1], 10, 32); err != nil {
+				continue
+			}
+		}
+		if uint64(first) >= start && uint64(last) <= end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package websockets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/atticlab/ripple/crypto"
+	"github.com/atticlab/ripple/data"
+	. "gopkg.in/check.v1"
+)
+
+type SubmitSuite struct {
+	keyPair  *crypto.KeyPair
+	interval time.Duration
+}
+
+var _ = Suite(&SubmitSuite{})
+
+func (s *SubmitSuite) SetUpSuite(c *C) {
+	seed, err := crypto.NewSeed("snoPBrXtMeMyMHUVTgbuqAfg1SUTb")
+	c.Assert(err, IsNil)
+	s.keyPair, err = seed.DeriveKeyPair(crypto.ECDSA, 0)
+	c.Assert(err, IsNil)
+	s.interval = submitPollInterval
+	submitPollInterval = time.Millisecond
+}
+
+func (s *SubmitSuite) TearDownSuite(c *C) {
+	submitPollInterval = s.interval
+}
+
+// ledgers validates a ledger on each call to ServerInfo, returning the
+// results in turn from Submit and including the transaction in the ledger
+// given by validatedIn, if any
+type ledgers struct {
+	Client
+	validated   uint32
+	results     []string
+	submitted   []data.Hash256
+	validatedIn uint32
+}
+
+func (l *ledgers) ServerInfo(ctx context.Context) (*ServerInfoResult, error) {
+	l.validated++
+	var result ServerInfoResult
+	info := fmt.Sprintf(`{"info":{"complete_ledgers":"1000-%d","validated_ledger":{"seq":%d}}}`, l.validated, l.validated)
+	if err := json.Unmarshal([]byte(info), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (l *ledgers) Submit(ctx context.Context, tx data.Transaction) (*SubmitResult, error) {
+	l.submitted = append(l.submitted, *tx.GetHash())
+	result := &SubmitResult{}
+	err := result.EngineResult.UnmarshalText([]byte(l.results[0]))
+	if len(l.results) > 1 {
+		l.results = l.results[1:]
+	}
+	return result, err
+}
+
+func (l *ledgers) Tx(ctx context.Context, hash data.Hash256) (*TxResult, error) {
+	if l.validatedIn == 0 || l.validated < l.validatedIn {
+		return nil, &RippleError{Name: "txnNotFound", Code: 29, Message: "Transaction not found."}
+	}
+	result := &TxResult{Validated: true}
+	result.Transaction = &data.Payment{}
+	result.LedgerSequence = l.validatedIn
+	return result, nil
+}
+
+func (s *SubmitSuite) payment(c *C) data.Transaction {
+	var txm data.TransactionWithMetaData
+	c.Assert(json.Unmarshal([]byte(`{"TransactionType":"Payment","Account":"rEhxGqkqPPSxQ3P25J66ft5TwpzV14k2de","Destination":"rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn","Amount":"1000000","Fee":"12","Flags":0,"Sequence":1}`), &txm), IsNil)
+	return txm.Transaction
+}
+
+func (s *SubmitSuite) TestQueued(c *C) {
+	client := &ledgers{validated: 1000, results: []string{"terQUEUED"}, validatedIn: 1004}
+	tx := s.payment(c)
+	result, err := SubmitAndWait(context.Background(), client, tx, s.keyPair)
+	c.Assert(err, IsNil)
+	c.Check(result.LedgerSequence, Equals, uint32(1004))
+	// Ledger 1001 was validated when it was signed
+	c.Check(*tx.GetBase().LastLedgerSequence, Equals, uint32(1022))
+	c.Check(client.submitted, DeepEquals, []data.Hash256{*tx.GetHash()})
+}
+
+func (s *SubmitSuite) TestResubmit(c *C) {
+	client := &ledgers{validated: 1000, results: []string{"telINSUF_FEE_P", "telINSUF_FEE_P", "tesSUCCESS"}, validatedIn: 1005}
+	tx := s.payment(c)
+	result, err := SubmitAndWait(context.Background(), client, tx, s.keyPair)
+	c.Assert(err, IsNil)
+	c.Check(result.LedgerSequence, Equals, uint32(1005))
+	c.Check(client.submitted, HasLen, 3)
+}
+
+func (s *SubmitSuite) TestExpired(c *C) {
+	client := &ledgers{validated: 1000, results: []string{"tefPAST_SEQ"}}
+	tx := s.payment(c)
+	last := uint32(1003)
+	tx.GetBase().LastLedgerSequence = &last
+	_, err := SubmitAndWait(context.Background(), client, tx, s.keyPair)
+	c.Assert(err, FitsTypeOf, &ExpiredError{})
+	c.Check(err.(*ExpiredError).LastLedgerSequence, Equals, last)
+	c.Check(err.(*ExpiredError).Result.String(), Equals, "tefPAST_SEQ")
+	c.Check(client.validated >= last, Equals, true)
+	c.Check(client.submitted, HasLen, 1)
+}
+
+func (s *SubmitSuite) TestFailed(c *C) {
+	client := &ledgers{validated: 1000, results: []string{"temBAD_AMOUNT"}}
+	_, err := SubmitAndWait(context.Background(), client, s.payment(c), s.keyPair)
+	c.Check(err, ErrorMatches, "Transaction .* failed: temBAD_AMOUNT.*")
+}
+
+func (s *SubmitSuite) TestSigned(c *C) {
+	client := &ledgers{validated: 1000, results: []string{"tesSUCCESS"}}
+	_, err := SubmitAndWait(context.Background(), client, s.payment(c), nil)
+	c.Check(err, ErrorMatches, "A signed transaction requires a LastLedgerSequence")
+	c.Check(client.submitted, HasLen, 0)
+}
+
+func (s *SubmitSuite) TestLedgersContain(c *C) {
+	for _, test := range []struct {
+		complete    string
+		first, last uint32
+		expected    bool
+	}{
+		{"32570-82521761", 82521700, 82521761, true},
+		{"32570-82521761", 82521700, 82521762, false},
+		{"32570-100,102-200", 99, 103, false},
+		{"32570-100,102-200", 102, 103, true},
+		{"5", 5, 5, true},
+		{"empty", 5, 5, false},
+	} {
+		c.Check(ledgersContain(test.complete, test.first, test.last), Equals, test.expected, Commentf(test.complete))
+	}
+}
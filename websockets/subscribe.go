@@ -48,19 +48,98 @@ func (s *ServerStreamMsg) TransactionCost() uint64 {
 	return (s.BaseFee * s.LoadFactor) / s.LoadBase
 }
 
+// Fields from subscribed validation stream messages
+type ValidationStreamMsg struct {
+	LedgerHash          data.Hash256    `json:"ledger_hash"`
+	LedgerSequence      uint32          `json:"ledger_index,string"`
+	SigningTime         data.RippleTime `json:"signing_time"`
+	Signature           string          `json:"signature"`
+	ValidationPublicKey string          `json:"validation_public_key"`
+	MasterKey           string          `json:"master_key,omitempty"`
+	Flags               uint32          `json:"flags"`
+	Full                bool            `json:"full"`
+	Amendments          []data.Hash256  `json:"amendments,omitempty"`
+}
+
+// Fields from subscribed book changes stream messages, which summarise
+// the trading in each order book over a validated ledger
+type BookChangesStreamMsg struct {
+	LedgerHash     data.Hash256    `json:"ledger_hash"`
+	LedgerSequence uint32          `json:"ledger_index"`
+	LedgerTime     data.RippleTime `json:"ledger_time"`
+	Changes        []BookChange    `json:"changes"`
+}
+
+// BookChange is the volume and price range of the trades between a pair of
+// assets, named as in rippled "XRP_drops" or "<issuer>/<currency>"
+type BookChange struct {
+	CurrencyA string              `json:"currency_a"`
+	CurrencyB string              `json:"currency_b"`
+	VolumeA   data.NonNativeValue `json:"volume_a"`
+	VolumeB   data.NonNativeValue `json:"volume_b"`
+	High      data.NonNativeValue `json:"high"`
+	Low       data.NonNativeValue `json:"low"`
+	Open      data.NonNativeValue `json:"open"`
+	Close     data.NonNativeValue `json:"close"`
+}
+
+// Stream messages of a type without a corresponding structure
+type RawStreamMsg struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+// StreamMessage is implemented by all the messages delivered
+// on the Incoming channel of a Remote
+type StreamMessage interface {
+	StreamType() string
+}
+
+func (*LedgerStreamMsg) StreamType() string      { return "ledgerClosed" }
+func (*TransactionStreamMsg) StreamType() string { return "transaction" }
+func (*ServerStreamMsg) StreamType() string      { return "serverStatus" }
+func (*ValidationStreamMsg) StreamType() string  { return "validationReceived" }
+func (*BookChangesStreamMsg) StreamType() string { return "bookChanges" }
+func (*PathFindCreateResult) StreamType() string { return "path_find" }
+func (msg *RawStreamMsg) StreamType() string     { return msg.Type }
+
 // Map message types to the appropriate data structure
-var streamMessageFactory = map[string]func() interface{}{
-	"ledgerClosed": func() interface{} { return &LedgerStreamMsg{} },
-	"transaction":  func() interface{} { return &TransactionStreamMsg{} },
-	"serverStatus": func() interface{} { return &ServerStreamMsg{} },
-	"path_find":    func() interface{} { return &PathFindCreateResult{} },
+var streamMessageFactory = map[string]func() StreamMessage{
+	"ledgerClosed":       func() StreamMessage { return &LedgerStreamMsg{} },
+	"transaction":        func() StreamMessage { return &TransactionStreamMsg{} },
+	"serverStatus":       func() StreamMessage { return &ServerStreamMsg{} },
+	"validationReceived": func() StreamMessage { return &ValidationStreamMsg{} },
+	"bookChanges":        func() StreamMessage { return &BookChangesStreamMsg{} },
+	"path_find":          func() StreamMessage { return &PathFindCreateResult{} },
+}
+
+// DecodeStreamMessage unmarshals a stream message into the structure for
+// its "type" field. Messages of unknown type are returned as a *RawStreamMsg.
+func DecodeStreamMessage(b []byte) (StreamMessage, error) {
+	var envelope struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return nil, err
+	}
+	factory, ok := streamMessageFactory[envelope.Type]
+	if !ok {
+		return &RawStreamMsg{Type: envelope.Type, Raw: append(json.RawMessage(nil), b...)}, nil
+	}
+	msg := factory()
+	if err := json.Unmarshal(b, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
 }
 
 type SubscribeCommand struct {
 	*Command
 	Streams []string                `json:"streams"`
 	Books   []OrderBookSubscription `json:"books,omitempty"`
-	Result  *SubscribeResult        `json:"result,omitempty"`
+	// Accounts whose validated transactions are streamed
+	Accounts []data.Account   `json:"accounts,omitempty"`
+	Result   *SubscribeResult `json:"result,omitempty"`
 }
 
 type SubscribeResult struct {
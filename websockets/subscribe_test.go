@@ -23,7 +23,7 @@ func (s *MessagesSuite) TestLedgerSubscribeResponse(c *C) {
 	c.Assert(msg.Result.FeeRef, Equals, uint64(10))
 	c.Assert(msg.Result.LedgerSequence, Equals, uint32(6959228))
 	c.Assert(msg.Result.LedgerHash.String(), Equals, "E23869F043A46C2735BCA40781A674C5F24460BAC26C6B7475550493A9180200")
-	c.Assert(msg.Result.LedgerTime.String(), Equals, "2014-Jun-01 20:56:40")
+	c.Assert(msg.Result.LedgerTime.String(), Equals, "2014-06-01T20:56:40Z")
 	c.Assert(msg.Result.ReserveBase, Equals, uint64(20000000))
 	c.Assert(msg.Result.ReserveIncrement, Equals, uint64(5000000))
 	c.Assert(msg.Result.ValidatedLedgers, Equals, "32570-6959228")
@@ -38,7 +38,7 @@ func (s *MessagesSuite) TestLedgerStreamMsg(c *C) {
 	c.Assert(msg.FeeRef, Equals, uint64(10))
 	c.Assert(msg.LedgerSequence, Equals, uint32(6959229))
 	c.Assert(msg.LedgerHash.String(), Equals, "21EB30937A47EA6B71B63183806FFE9308CCB786137AA00FFB32A7094C6426FA")
-	c.Assert(msg.LedgerTime.String(), Equals, "2014-Jun-01 20:56:40")
+	c.Assert(msg.LedgerTime.String(), Equals, "2014-06-01T20:56:40Z")
 	c.Assert(msg.ReserveBase, Equals, uint64(20000000))
 	c.Assert(msg.ReserveIncrement, Equals, uint64(5000000))
 	c.Assert(msg.ValidatedLedgers, Equals, "32570-6959229")
@@ -168,3 +168,108 @@ func BenchmarkTransactionStreamJSON(b *testing.B) {
 		}
 	}
 }
+
+func decodeStreamFile(c *C, path string) StreamMessage {
+	b, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+	msg, err := DecodeStreamMessage(b)
+	c.Assert(err, IsNil)
+	return msg
+}
+
+func (s *MessagesSuite) TestDecodeStreamMessage(c *C) {
+	ledger, ok := decodeStreamFile(c, "testdata/ledger_stream.json").(*LedgerStreamMsg)
+	c.Assert(ok, Equals, true)
+	c.Assert(ledger.LedgerSequence, Equals, uint32(6959229))
+	c.Assert(ledger.TxnCount, Equals, uint32(1))
+
+	tx, ok := decodeStreamFile(c, "testdata/transactions_stream.json").(*TransactionStreamMsg)
+	c.Assert(ok, Equals, true)
+	c.Assert(tx.StreamType(), Equals, "transaction")
+	c.Assert(tx.Transaction.GetHash().String(), Equals, "25174B56C40B090D4AFCDAC3F07DCCF8A49A096D62CE1CE6864A8624F790F980")
+	c.Assert(tx.Transaction.MetaData.TransactionResult.String(), Equals, "tesSUCCESS")
+
+	server, ok := decodeStreamFile(c, "testdata/server_stream.json").(*ServerStreamMsg)
+	c.Assert(ok, Equals, true)
+	c.Assert(server.Status, Equals, "syncing")
+
+	validation, ok := decodeStreamFile(c, "testdata/validation_stream.json").(*ValidationStreamMsg)
+	c.Assert(ok, Equals, true)
+	c.Assert(validation.LedgerSequence, Equals, uint32(6))
+	c.Assert(validation.LedgerHash.String(), Equals, "EC02890710AAA2B71221B0D560CFB22D64317C07B7406B02959AD84BAD33E602")
+	c.Assert(validation.ValidationPublicKey, Equals, "n94Gnc6svmaPPRHUAyyzc6ikdmbAqvFk8VTM5Zbfrc25CtjE2JFb")
+	c.Assert(validation.Full, Equals, true)
+	c.Assert(validation.Amendments, HasLen, 2)
+}
+
+func (s *MessagesSuite) TestDecodeUnknownStreamMessage(c *C) {
+	const manifest = `{"type":"manifestReceived","master_key":"nHUon2tpyJEHHYGmxqeGu37cvPYHzrMtUNQFVdCgGNvEkjmCpTqK","seq":1}`
+	msg, err := DecodeStreamMessage([]byte(manifest))
+	c.Assert(err, IsNil)
+	raw, ok := msg.(*RawStreamMsg)
+	c.Assert(ok, Equals, true)
+	c.Assert(raw.StreamType(), Equals, "manifestReceived")
+	c.Assert(string(raw.Raw), Equals, manifest)
+
+	_, err = DecodeStreamMessage([]byte(`[]`))
+	c.Assert(err, NotNil)
+}
+
+func (s *MessagesSuite) TestBookChangesStreamMsg(c *C) {
+	b, err := ioutil.ReadFile("testdata/book_changes_stream.json")
+	c.Assert(err, IsNil)
+	msg, err := DecodeStreamMessage(b)
+	c.Assert(err, IsNil)
+	changes, ok := msg.(*BookChangesStreamMsg)
+	c.Assert(ok, Equals, true)
+	c.Assert(changes.LedgerSequence, Equals, uint32(88530953))
+	c.Assert(changes.LedgerTime.String(), Equals, "2023-12-12T11:06:30Z")
+	c.Assert(changes.Changes, HasLen, 1)
+	change := changes.Changes[0]
+	c.Assert(change.CurrencyA, Equals, "XRP_drops")
+	c.Assert(change.CurrencyB, Equals, "rhub8VRN55s94qWKDv6jmDy1pUykJzF3wq/USD")
+	c.Assert(change.VolumeA.String(), Equals, "23020993")
+	c.Assert(change.VolumeB.String(), Equals, "11.51049219286966")
+}
+
+func (s *MessagesSuite) TestSubscribeAccountsCommand(c *C) {
+	account, err := data.NewAccountFromAddress("rPEZyTnSyQyXBCwMVYyaafSVPL8oMtfG6a")
+	c.Assert(err, IsNil)
+	cmd := &SubscribeCommand{
+		Command:  newCommand("subscribe"),
+		Streams:  []string{},
+		Accounts: []data.Account{*account},
+	}
+	b, err := json.Marshal(cmd)
+	c.Assert(err, IsNil)
+	var request map[string]interface{}
+	c.Assert(json.Unmarshal(b, &request), IsNil)
+	c.Assert(request["command"], Equals, "subscribe")
+	c.Assert(request["accounts"], DeepEquals, []interface{}{"rPEZyTnSyQyXBCwMVYyaafSVPL8oMtfG6a"})
+	c.Assert(request["books"], IsNil)
+}
+
+func (s *MessagesSuite) TestPathFindStreamMsg(c *C) {
+	b, err := ioutil.ReadFile("testdata/path_find_stream.json")
+	c.Assert(err, IsNil)
+	msg, err := DecodeStreamMessage(b)
+	c.Assert(err, IsNil)
+	result, ok := msg.(*PathFindCreateResult)
+	c.Assert(ok, Equals, true)
+	c.Assert(result.FullReply, Equals, true)
+	c.Assert(result.DestinationAmount.String(), Equals, "0.001/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+	c.Assert(result.Alternatives, HasLen, 1)
+	c.Assert(result.Alternatives[0].SourceAmount.String(), Equals, "0.001555/XRP")
+	c.Assert(result.Alternatives[0].PathsComputed, HasLen, 1)
+	c.Assert(result.Alternatives[0].PathsComputed[0].String(), Equals, "USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B => rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B")
+}
+
+func (s *MessagesSuite) TestPathFindCommand(c *C) {
+	cmd := &PathFindCommand{Command: newCommand("path_find"), Subcommand: "close"}
+	b, err := json.Marshal(cmd)
+	c.Assert(err, IsNil)
+	var request map[string]interface{}
+	c.Assert(json.Unmarshal(b, &request), IsNil)
+	c.Assert(request["command"], Equals, "path_find")
+	c.Assert(request["subcommand"], Equals, "close")
+}